@@ -0,0 +1,688 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog/replay"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+const debuggerHelp = `Commands:
+  step [n]             apply the next n events (default 1), printing each
+  continue             apply events until a breakpoint matches
+  until <predicate>    apply events until the predicate matches
+  break <predicate>    set a breakpoint
+  breakpoints          list the breakpoints
+  delete <id>          delete a breakpoint
+  goto <index>         travel to just after the event at index, forwards or backwards
+  back [n]             travel back n events (default 1)
+  event                print the last applied event
+  status [node]        print the status of the node of the last event, or of the given node
+  json [node]          print the status as JSON
+  help                 print this help
+  quit                 exit the debugger
+
+Predicates are space separated terms, all of which must hold:
+  node=<id>            the event is applied to the given node
+  event=<type>         the event is of the given type, e.g. Step or TickElapsed
+  msg=<type>           the event steps a message of the given type, e.g. Preprepare
+  seq=<seq_no>         the event refers to the given sequence number
+  epoch=<number>       the event refers to the given epoch
+  epoch-changes        the node's active epoch number or state changes
+  watermark-moves      the node's low watermark moves
+`
+
+// eventContext is the information a predicate is evaluated against.  The
+// statuses are those of the event's node before and after the event, and
+// are only populated when some predicate depends on them.
+type eventContext struct {
+	event  *recording.Event
+	before *status.StateMachine
+	after  *status.StateMachine
+}
+
+type predicate struct {
+	text        string
+	conditions  []func(*eventContext) bool
+	needsStatus bool
+}
+
+func (p *predicate) matches(ec *eventContext) bool {
+	for _, condition := range p.conditions {
+		if !condition(ec) {
+			return false
+		}
+	}
+	return true
+}
+
+func parsePredicate(text string) (*predicate, error) {
+	terms := strings.Fields(text)
+	if len(terms) == 0 {
+		return nil, errors.Errorf("empty predicate")
+	}
+
+	p := &predicate{
+		text: strings.Join(terms, " "),
+	}
+
+	for _, term := range terms {
+		switch term {
+		case "epoch-changes":
+			p.needsStatus = true
+			p.conditions = append(p.conditions, func(ec *eventContext) bool {
+				before, after := activeEpoch(ec.before), activeEpoch(ec.after)
+				if before == nil || after == nil {
+					return before != after
+				}
+				return before.Number != after.Number || before.State != after.State
+			})
+			continue
+		case "watermark-moves":
+			p.needsStatus = true
+			p.conditions = append(p.conditions, func(ec *eventContext) bool {
+				return ec.before != nil && ec.after != nil && ec.before.LowWatermark != ec.after.LowWatermark
+			})
+			continue
+		}
+
+		parts := strings.SplitN(term, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("unknown predicate term '%s'", term)
+		}
+		key, value := parts[0], parts[1]
+
+		switch key {
+		case "event":
+			p.conditions = append(p.conditions, func(ec *eventContext) bool {
+				return strings.EqualFold(eventTypeName(ec.event.StateEvent), value)
+			})
+			continue
+		case "msg":
+			p.conditions = append(p.conditions, func(ec *eventContext) bool {
+				step, ok := ec.event.StateEvent.Type.(*state.Event_Step)
+				return ok && strings.EqualFold(msgTypeName(step.Step.Msg), value)
+			})
+			continue
+		case "node", "seq", "epoch":
+		default:
+			return nil, errors.Errorf("unknown predicate term '%s'", term)
+		}
+
+		number, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("predicate term '%s' requires a number", term)
+		}
+
+		switch key {
+		case "node":
+			p.conditions = append(p.conditions, func(ec *eventContext) bool {
+				return ec.event.NodeId == number
+			})
+		case "seq":
+			p.conditions = append(p.conditions, func(ec *eventContext) bool {
+				seqNo, ok := eventSeqNo(ec.event.StateEvent)
+				return ok && seqNo == number
+			})
+		case "epoch":
+			p.conditions = append(p.conditions, func(ec *eventContext) bool {
+				epoch, ok := eventEpoch(ec.event.StateEvent)
+				return ok && epoch == number
+			})
+		}
+	}
+
+	return p, nil
+}
+
+func activeEpoch(s *status.StateMachine) *status.EpochTarget {
+	if s == nil || s.EpochTracker == nil {
+		return nil
+	}
+	return s.EpochTracker.ActiveEpoch
+}
+
+// eventSeqNo returns the sequence number the event refers to, if any.
+func eventSeqNo(event *state.Event) (uint64, bool) {
+	switch et := event.Type.(type) {
+	case *state.Event_CheckpointResult:
+		return et.CheckpointResult.SeqNo, true
+	case *state.Event_StateTransferComplete:
+		return et.StateTransferComplete.SeqNo, true
+	case *state.Event_StateTransferFailed:
+		return et.StateTransferFailed.SeqNo, true
+	case *state.Event_HashResult:
+		switch ot := et.HashResult.Origin.Type.(type) {
+		case *state.HashOrigin_Batch_:
+			return ot.Batch.SeqNo, true
+		case *state.HashOrigin_VerifyBatch_:
+			return ot.VerifyBatch.SeqNo, true
+		}
+	case *state.Event_Step:
+		switch mt := et.Step.Msg.Type.(type) {
+		case *msgs.Msg_Preprepare:
+			return mt.Preprepare.SeqNo, true
+		case *msgs.Msg_Prepare:
+			return mt.Prepare.SeqNo, true
+		case *msgs.Msg_Commit:
+			return mt.Commit.SeqNo, true
+		case *msgs.Msg_Checkpoint:
+			return mt.Checkpoint.SeqNo, true
+		case *msgs.Msg_FetchBatch:
+			return mt.FetchBatch.SeqNo, true
+		case *msgs.Msg_ForwardBatch:
+			return mt.ForwardBatch.SeqNo, true
+		}
+	}
+	return 0, false
+}
+
+// eventEpoch returns the epoch number the event refers to, if any.
+func eventEpoch(event *state.Event) (uint64, bool) {
+	switch et := event.Type.(type) {
+	case *state.Event_HashResult:
+		switch ot := et.HashResult.Origin.Type.(type) {
+		case *state.HashOrigin_Batch_:
+			return ot.Batch.Epoch, true
+		case *state.HashOrigin_EpochChange_:
+			return ot.EpochChange.EpochChange.GetNewEpoch(), true
+		}
+	case *state.Event_Step:
+		switch mt := et.Step.Msg.Type.(type) {
+		case *msgs.Msg_Preprepare:
+			return mt.Preprepare.Epoch, true
+		case *msgs.Msg_Prepare:
+			return mt.Prepare.Epoch, true
+		case *msgs.Msg_Commit:
+			return mt.Commit.Epoch, true
+		case *msgs.Msg_Suspect:
+			return mt.Suspect.Epoch, true
+		case *msgs.Msg_EpochChange:
+			return mt.EpochChange.NewEpoch, true
+		case *msgs.Msg_EpochChangeAck:
+			return mt.EpochChangeAck.EpochChange.GetNewEpoch(), true
+		case *msgs.Msg_NewEpoch:
+			return mt.NewEpoch.GetNewConfig().GetConfig().GetNumber(), true
+		case *msgs.Msg_NewEpochEcho:
+			return mt.NewEpochEcho.GetConfig().GetNumber(), true
+		case *msgs.Msg_NewEpochReady:
+			return mt.NewEpochReady.GetConfig().GetNumber(), true
+		}
+	}
+	return 0, false
+}
+
+// historyLength is the number of applied events the debugger retains, and
+// so may travel back over without replaying.  Between historyLength and
+// twice as many events are retained at any time.
+const historyLength = 10000
+
+// debugger replays a log under the control of commands.  It retains the
+// most recently applied events so that it can travel backwards over them
+// without replaying, and only replays the log from the start when the
+// state behind the furthest applied event is needed, or when travelling
+// further back than the retained events.
+type debugger struct {
+	args   *arguments
+	output io.Writer
+
+	// open returns a reader positioned at the start of the log,
+	// and the number of events which precede it.
+	open func() (eventReader, uint64, error)
+
+	reader  eventReader
+	player  *replay.Player
+	start   uint64
+	applied uint64
+	halted  error
+
+	// history holds the most recently applied events, the last of
+	// which is the event at index applied.
+	history []*recording.Event
+
+	// index is the position of the debugger, which is behind applied
+	// after travelling backwards.
+	index     uint64
+	lastEvent *recording.Event
+
+	breakpoints    map[int]*predicate
+	nextBreakpoint int
+}
+
+func (d *debugger) restart() error {
+	d.close()
+
+	reader, index, err := d.open()
+	if err != nil {
+		return err
+	}
+
+	d.reader = reader
+	d.player = d.args.newPlayer(d.output)
+	d.start = index
+	d.applied = index
+	d.halted = nil
+	d.history = nil
+	d.index = index
+	d.lastEvent = nil
+
+	return nil
+}
+
+func (d *debugger) close() {
+	if closer, ok := d.reader.(io.Closer); ok {
+		closer.Close()
+	}
+	d.reader = nil
+}
+
+// retained returns whether the debugger may travel back to the given
+// index without replaying, and if so, the event at that index.  The index
+// must not be beyond applied.
+func (d *debugger) retained(index uint64) (*recording.Event, bool) {
+	back := d.applied - index
+	switch {
+	case back < uint64(len(d.history)):
+		return d.history[uint64(len(d.history))-back-1], true
+	case back == uint64(len(d.history)) && index == d.start:
+		return nil, true
+	default:
+		return nil, false
+	}
+}
+
+// catchUp replays the log from the start up to the current index if the
+// debugger has travelled back, so that the player reflects its position.
+func (d *debugger) catchUp() error {
+	if d.index == d.applied {
+		return nil
+	}
+
+	index := d.index
+	if err := d.restart(); err != nil {
+		return err
+	}
+
+	for d.index < index {
+		if _, err := d.next(nil, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// next applies the next event and returns whether the given predicate or
+// any breakpoint matched it.  It returns io.EOF at the end of the log.
+// Events which were already applied before travelling back are taken from
+// the history, unless some predicate depends on the status.
+func (d *debugger) next(until *predicate, checkBreakpoints bool) (bool, error) {
+	needsStatus := until != nil && until.needsStatus
+	if checkBreakpoints {
+		for _, bp := range d.breakpoints {
+			needsStatus = needsStatus || bp.needsStatus
+		}
+	}
+
+	if needsStatus {
+		if err := d.catchUp(); err != nil {
+			return false, err
+		}
+	}
+
+	ec := &eventContext{}
+
+	if d.index < d.applied {
+		ec.event, _ = d.retained(d.index + 1)
+	} else {
+		if d.halted != nil {
+			return false, d.halted
+		}
+
+		event, err := d.reader.ReadEvent()
+		if err == io.EOF {
+			return false, io.EOF
+		}
+		if err != nil {
+			d.halted = errors.WithMessage(err, "failed reading input")
+			return false, d.halted
+		}
+
+		ec.event = event
+
+		if needsStatus {
+			ec.before = d.status(event.NodeId)
+		}
+
+		if _, err := d.player.Apply(event); err != nil {
+			if divergence, ok := err.(*replay.Divergence); ok {
+				err = errors.New(divergence.Pretty())
+			}
+			d.halted = err
+			return false, err
+		}
+
+		d.applied++
+		d.history = append(d.history, event)
+		if len(d.history) == 2*historyLength {
+			d.history = append([]*recording.Event(nil), d.history[historyLength:]...)
+		}
+
+		if needsStatus {
+			ec.after = d.status(event.NodeId)
+		}
+	}
+
+	d.index++
+	d.lastEvent = ec.event
+
+	if until != nil && until.matches(ec) {
+		return true, nil
+	}
+
+	if checkBreakpoints {
+		for id := 0; id < d.nextBreakpoint; id++ {
+			if bp, ok := d.breakpoints[id]; ok && bp.matches(ec) {
+				fmt.Fprintf(d.output, "breakpoint %d (%s) hit\n", id, bp.text)
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (d *debugger) status(nodeID uint64) *status.StateMachine {
+	node := d.player.Node(nodeID)
+	if node == nil {
+		return nil
+	}
+
+	s, err := node.StateMachine.Status()
+	if err != nil {
+		return nil
+	}
+
+	return s
+}
+
+func (d *debugger) printEvent() error {
+	if d.lastEvent == nil {
+		fmt.Fprintf(d.output, "no event has been applied\n")
+		return nil
+	}
+
+	text, err := textFormat(d.lastEvent, !d.args.verboseText)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal event")
+	}
+
+	fmt.Fprintf(d.output, "% 6d %s\n", d.index, text)
+	return nil
+}
+
+// run applies events until the predicate or a breakpoint
+// matches, or until the end of the log.
+func (d *debugger) run(until *predicate) error {
+	for {
+		matched, err := d.next(until, true)
+		if err == io.EOF {
+			fmt.Fprintf(d.output, "end of log after event %d\n", d.index)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if matched {
+			return d.printEvent()
+		}
+	}
+}
+
+// travel moves to just after the event at index.  Travelling backwards
+// over retained events only moves the position, anything further back
+// replays the log from the start.
+func (d *debugger) travel(index uint64) error {
+	if index < d.index {
+		if event, ok := d.retained(index); ok {
+			d.index = index
+			d.lastEvent = event
+		} else if err := d.restart(); err != nil {
+			return err
+		}
+	}
+
+	for d.index < index {
+		if _, err := d.next(nil, false); err != nil {
+			if err == io.EOF {
+				fmt.Fprintf(d.output, "end of log after event %d\n", d.index)
+				return nil
+			}
+			return err
+		}
+	}
+
+	return d.printEvent()
+}
+
+func (d *debugger) nodeStatus(fields []string) (*status.StateMachine, error) {
+	var nodeID uint64
+	switch {
+	case len(fields) > 1:
+		var err error
+		nodeID, err = strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("bad node id '%s'", fields[1])
+		}
+	case d.lastEvent != nil:
+		nodeID = d.lastEvent.NodeId
+	default:
+		return nil, errors.Errorf("no event has been applied, specify a node")
+	}
+
+	if err := d.catchUp(); err != nil {
+		return nil, err
+	}
+
+	node := d.player.Node(nodeID)
+	if node == nil {
+		return nil, errors.Errorf("node %d has not been initialized", nodeID)
+	}
+
+	return node.StateMachine.Status()
+}
+
+func optionalCount(fields []string) (uint64, error) {
+	if len(fields) < 2 {
+		return 1, nil
+	}
+	n, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("bad count '%s'", fields[1])
+	}
+	return n, nil
+}
+
+// execute runs a single command and returns whether the debugger should exit.
+// Errors are reported to the user rather than terminating the debugger.
+func (d *debugger) execute(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), fields[0]))
+
+	switch fields[0] {
+	case "step", "s":
+		n, err := optionalCount(fields)
+		if err != nil {
+			return false, err
+		}
+		for i := uint64(0); i < n; i++ {
+			if _, err := d.next(nil, false); err != nil {
+				if err == io.EOF {
+					fmt.Fprintf(d.output, "end of log after event %d\n", d.index)
+					return false, nil
+				}
+				return false, err
+			}
+			if err := d.printEvent(); err != nil {
+				return false, err
+			}
+		}
+	case "continue", "c":
+		return false, d.run(nil)
+	case "until", "u":
+		p, err := parsePredicate(rest)
+		if err != nil {
+			return false, err
+		}
+		return false, d.run(p)
+	case "break", "b":
+		p, err := parsePredicate(rest)
+		if err != nil {
+			return false, err
+		}
+		d.breakpoints[d.nextBreakpoint] = p
+		fmt.Fprintf(d.output, "breakpoint %d (%s) set\n", d.nextBreakpoint, p.text)
+		d.nextBreakpoint++
+	case "breakpoints":
+		for id := 0; id < d.nextBreakpoint; id++ {
+			if bp, ok := d.breakpoints[id]; ok {
+				fmt.Fprintf(d.output, "%d: %s\n", id, bp.text)
+			}
+		}
+	case "delete", "d":
+		if len(fields) != 2 {
+			return false, errors.Errorf("usage: delete <id>")
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return false, errors.Errorf("bad breakpoint id '%s'", fields[1])
+		}
+		if _, ok := d.breakpoints[id]; !ok {
+			return false, errors.Errorf("no breakpoint %d", id)
+		}
+		delete(d.breakpoints, id)
+	case "goto", "g":
+		if len(fields) != 2 {
+			return false, errors.Errorf("usage: goto <index>")
+		}
+		index, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return false, errors.Errorf("bad index '%s'", fields[1])
+		}
+		return false, d.travel(index)
+	case "back":
+		n, err := optionalCount(fields)
+		if err != nil {
+			return false, err
+		}
+		if n > d.index {
+			n = d.index
+		}
+		return false, d.travel(d.index - n)
+	case "event", "e":
+		return false, d.printEvent()
+	case "status":
+		s, err := d.nodeStatus(fields)
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprintf(d.output, "%s\n", s.Pretty())
+	case "json":
+		s, err := d.nodeStatus(fields)
+		if err != nil {
+			return false, err
+		}
+		text, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return false, errors.WithMessage(err, "could not marshal status")
+		}
+		fmt.Fprintf(d.output, "%s\n", text)
+	case "help", "h":
+		fmt.Fprint(d.output, debuggerHelp)
+	case "quit", "q":
+		return true, nil
+	default:
+		return false, errors.Errorf("unknown command '%s', try help", fields[0])
+	}
+
+	return false, nil
+}
+
+// debug runs the debugger, reading commands from the given input until
+// it is exhausted or the user quits.
+func (a *arguments) debug(commands io.Reader, output io.Writer) error {
+	if a.input != nil {
+		defer a.input.Close()
+	}
+
+	d := &debugger{
+		args:        a,
+		output:      output,
+		open:        a.rewindReader,
+		breakpoints: map[int]*predicate{},
+	}
+
+	if err := d.restart(); err != nil {
+		return err
+	}
+	defer d.close()
+
+	if a.startIndex > 0 {
+		if err := d.travel(a.startIndex); err != nil {
+			return err
+		}
+	}
+
+	scanner := bufio.NewScanner(commands)
+	for {
+		fmt.Fprint(output, "(mircat) ")
+		if !scanner.Scan() {
+			fmt.Fprintln(output)
+			return scanner.Err()
+		}
+
+		quit, err := d.execute(scanner.Text())
+		if err != nil {
+			fmt.Fprintf(output, "error: %s\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// rewindReader returns a reader from the start of the log, seeking the
+// input back to its beginning if it has already been read from.
+func (a *arguments) rewindReader() (eventReader, uint64, error) {
+	if a.inputDir == "" {
+		seeker, ok := a.input.(io.Seeker)
+		if !ok {
+			return nil, 0, errors.Errorf("input does not support rewinding")
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, errors.WithMessage(err, "could not rewind input")
+		}
+	}
+
+	return a.openReader()
+}
@@ -15,19 +15,16 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"runtime/debug"
-	"sort"
-	"time"
 
 	"github.com/pkg/errors"
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog/replay"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
 	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
-	"github.com/hyperledger-labs/mirbft/pkg/status"
 )
 
 // command line flags
@@ -101,7 +98,11 @@ func excludedByNodeID(re *recording.Event, nodeIDs []uint64) bool {
 
 type arguments struct {
 	input         io.ReadCloser
+	inputDir      string
+	mergeFiles    []string
+	startIndex    uint64
 	interactive   bool
+	repl          bool
 	printActions  bool
 	logLevel      statemachine.LogLevel
 	nodeIDs       []uint64
@@ -143,197 +144,118 @@ func (nl namedLogger) Log(level statemachine.LogLevel, msg string, args ...inter
 	fmt.Fprintf(nl.output, "\n")
 }
 
-type stateMachines struct {
-	logLevel statemachine.LogLevel
-	nodes    map[uint64]*stateMachine
-	output   io.Writer
-}
-
-// Wrapper for a state machine keeping track of pending actions and execution time.
-type stateMachine struct {
-	machine        *statemachine.StateMachine
-	pendingActions *statemachine.ActionList
-	executionTime  time.Duration
-}
-
-func newStateMachines(output io.Writer, logLevel statemachine.LogLevel) *stateMachines {
-	return &stateMachines{
-		output:   output,
-		logLevel: logLevel,
-		nodes:    map[uint64]*stateMachine{},
-	}
-}
-
-// Applies event to the corresponding (according to the event's NodeId) state machine.
-// For all events except state.Event_ActionsReceived, returns the actions produced by
-// the state machine when applying event.
-// For state.Event_ActionsReceived, returns all accumulated actions
-// produced since last state.Event_ActionsReceived event.
-func (s *stateMachines) apply(event *recording.Event) (result *statemachine.ActionList, err error) {
-	var node *stateMachine
-
-	// Select state machine to apply event to.
-	if _, ok := event.StateEvent.Type.(*state.Event_Initialize); ok {
-		// If the event is an initialization event, create a new state machine.
-		delete(s.nodes, event.NodeId)
-		node = &stateMachine{
-			machine: &statemachine.StateMachine{
-				Logger: namedLogger{
-					name:   fmt.Sprintf("node%d", event.NodeId),
-					output: s.output,
-					level:  s.logLevel,
-				},
-			},
-			pendingActions: &statemachine.ActionList{},
-		}
-		s.nodes[event.NodeId] = node
-	} else {
-		// For a non-initialization event, select the state machine by the event's NodeId.
-		var ok bool
-		node, ok = s.nodes[event.NodeId]
-		if !ok {
-			return nil, errors.Errorf("malformed log: node %d attempted to apply event of type %T without initializing first.", event.NodeId, event.StateEvent.Type)
-		}
-	}
-
-	// Catch errors resulting from applying event to state machine.
-	defer func() {
-		if r := recover(); r != nil {
-			err = errors.Errorf("node %d panic-ed while applying state event to state machine:\n\n%s\n\n%s", event.NodeId, r, debug.Stack())
-		}
-	}()
-
-	// Apply event to state machine and save resulting requested actions,
-	// while measuring time the application took.
-	start := time.Now()
-	actions := node.machine.ApplyEvent(event.StateEvent)
-	node.executionTime += time.Since(start)
-	node.pendingActions.PushBackList(actions)
-	if err != nil {
-		return nil, err
-	}
-
-	// If this was an event of receiving actions from the state machine,
-	// return all the pending actions.
-	// Otherwise, return only the actions produced by this event.
-	switch event.StateEvent.Type.(type) {
-	case *state.Event_ActionsReceived:
-		result := node.pendingActions
-		node.pendingActions = &statemachine.ActionList{}
-		return result, nil
-	default:
-		return actions, nil
-	}
-}
-
-func (s *stateMachines) status(event *recording.Event) (*status.StateMachine, error) {
-	node := s.nodes[event.NodeId]
-	return node.machine.Status()
-}
-
-func (a *arguments) shouldPrint(event *recording.Event) bool {
-	var eventTypeText string
-	switch event.StateEvent.Type.(type) {
+// eventTypeName returns the name of the type of the state event,
+// as used by --eventType/--notEventType and the debugger.
+func eventTypeName(event *state.Event) string {
+	switch event.Type.(type) {
 	case *state.Event_Initialize:
-		eventTypeText = "Initialize"
+		return "Initialize"
 	case *state.Event_LoadPersistedEntry:
-		eventTypeText = "LoadPersistedEntry"
+		return "LoadPersistedEntry"
 	case *state.Event_CompleteInitialization:
-		eventTypeText = "CompleteInitialization"
+		return "CompleteInitialization"
 	case *state.Event_TickElapsed:
-		eventTypeText = "TickElapsed"
+		return "TickElapsed"
 	case *state.Event_HashResult:
-		eventTypeText = "HashResult"
+		return "HashResult"
 	case *state.Event_CheckpointResult:
-		eventTypeText = "CheckpointResult"
+		return "CheckpointResult"
 	case *state.Event_RequestPersisted:
-		eventTypeText = "RequestPersisted"
+		return "RequestPersisted"
 	case *state.Event_ActionsReceived:
-		eventTypeText = "ActionsReceived"
+		return "ActionsReceived"
 	case *state.Event_Step:
-		eventTypeText = "Step"
+		return "Step"
 	case *state.Event_StateTransferComplete:
-		eventTypeText = "StateTransferComplete"
+		return "StateTransferComplete"
 	case *state.Event_StateTransferFailed:
-		eventTypeText = "StateTransferFailed"
+		return "StateTransferFailed"
+	default:
+		panic(fmt.Sprintf("Unknown event type '%T'", event.Type))
+	}
+}
+
+// msgTypeName returns the name of the type of the message,
+// as used by --stepType/--notStepType and the debugger.
+func msgTypeName(msg *msgs.Msg) string {
+	switch msg.Type.(type) {
+	case *msgs.Msg_Preprepare:
+		return "Preprepare"
+	case *msgs.Msg_Prepare:
+		return "Prepare"
+	case *msgs.Msg_Commit:
+		return "Commit"
+	case *msgs.Msg_Checkpoint:
+		return "Checkpoint"
+	case *msgs.Msg_Suspect:
+		return "Suspect"
+	case *msgs.Msg_EpochChange:
+		return "EpochChange"
+	case *msgs.Msg_EpochChangeAck:
+		return "EpochChangeAck"
+	case *msgs.Msg_NewEpoch:
+		return "NewEpoch"
+	case *msgs.Msg_NewEpochEcho:
+		return "NewEpochEcho"
+	case *msgs.Msg_NewEpochReady:
+		return "NewEpochReady"
+	case *msgs.Msg_FetchBatch:
+		return "FetchBatch"
+	case *msgs.Msg_ForwardBatch:
+		return "ForwardBatch"
+	case *msgs.Msg_FetchRequest:
+		return "FetchRequest"
+	case *msgs.Msg_ForwardRequest:
+		return "ForwardRequest"
+	case *msgs.Msg_RequestAck:
+		return "RequestAck"
 	default:
-		panic(fmt.Sprintf("Unknown event type '%T'", event.StateEvent.Type))
+		panic("unknown message type")
 	}
+}
 
-	if excludeByType(eventTypeText, a.eventTypes, a.notEventTypes) {
+func (a *arguments) shouldPrint(event *recording.Event) bool {
+	if excludeByType(eventTypeName(event.StateEvent), a.eventTypes, a.notEventTypes) {
 		return false
 	}
 
-	switch et := event.StateEvent.Type.(type) {
-	case *state.Event_Initialize:
-	case *state.Event_LoadPersistedEntry:
-	case *state.Event_CompleteInitialization:
-	case *state.Event_TickElapsed:
-	case *state.Event_HashResult:
-	case *state.Event_CheckpointResult:
-	case *state.Event_RequestPersisted:
-	case *state.Event_ActionsReceived:
-	case *state.Event_Step:
-		var stepTypeText string
-		switch et.Step.Msg.Type.(type) {
-		case *msgs.Msg_Preprepare:
-			stepTypeText = "Preprepare"
-		case *msgs.Msg_Prepare:
-			stepTypeText = "Prepare"
-		case *msgs.Msg_Commit:
-			stepTypeText = "Commit"
-		case *msgs.Msg_Checkpoint:
-			stepTypeText = "Checkpoint"
-		case *msgs.Msg_Suspect:
-			stepTypeText = "Suspect"
-		case *msgs.Msg_EpochChange:
-			stepTypeText = "EpochChange"
-		case *msgs.Msg_EpochChangeAck:
-			stepTypeText = "EpochChangeAck"
-		case *msgs.Msg_NewEpoch:
-			stepTypeText = "NewEpoch"
-		case *msgs.Msg_NewEpochEcho:
-			stepTypeText = "NewEpochEcho"
-		case *msgs.Msg_NewEpochReady:
-			stepTypeText = "NewEpochReady"
-		case *msgs.Msg_FetchBatch:
-			stepTypeText = "FetchBatch"
-		case *msgs.Msg_ForwardBatch:
-			stepTypeText = "ForwardBatch"
-		case *msgs.Msg_FetchRequest:
-			stepTypeText = "FetchRequest"
-		case *msgs.Msg_ForwardRequest:
-			stepTypeText = "ForwardRequest"
-		case *msgs.Msg_RequestAck:
-			stepTypeText = "RequestAck"
-		default:
-			panic("unknown message type")
-		}
-		if excludeByType(stepTypeText, a.stepTypes, a.notStepTypes) {
+	if step, ok := event.StateEvent.Type.(*state.Event_Step); ok {
+		if excludeByType(msgTypeName(step.Step.Msg), a.stepTypes, a.notStepTypes) {
 			return false
 		}
-	case *state.Event_StateTransferComplete:
-		eventTypeText = "StateTransferComplete"
-	case *state.Event_StateTransferFailed:
-		eventTypeText = "StateTransferFailed"
-	default:
-		panic(fmt.Sprintf("Unknown event type '%T'", event.StateEvent.Type))
 	}
 
 	return true
 }
 
+func (a *arguments) newPlayer(output io.Writer) *replay.Player {
+	return replay.NewPlayer(
+		replay.LoggerOpt(func(nodeID uint64) statemachine.Logger {
+			return namedLogger{
+				name:   fmt.Sprintf("node%d", nodeID),
+				output: output,
+				level:  a.logLevel,
+			}
+		}),
+	)
+}
+
 func (a *arguments) execute(output io.Writer) error {
-	defer a.input.Close()
+	if a.input != nil {
+		defer a.input.Close()
+	}
 
 	// In case of "interactive" mode, events from the
-	// event log will be applied to these state machines.
-	s := newStateMachines(output, a.logLevel)
+	// event log will be applied to the state machines of this player.
+	player := a.newPlayer(output)
 
 	// Create log reader.
-	reader, err := eventlog.NewReader(a.input)
+	reader, index, err := a.openReader()
 	if err != nil {
-		return errors.WithMessage(err, "bad input file")
+		return err
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
 	}
 
 	// Get log indices at which state machine status will be printed.
@@ -343,9 +265,8 @@ func (a *arguments) execute(output io.Writer) error {
 	}
 
 	// The log itself does not explicitly keep track of indices,
-	// so we need to keep track of them here.
-	index := uint64(0)
-
+	// so we need to keep track of them here, starting from the
+	// number of events which precede the first one read.
 	// Read events from log until the reader returns the io.EOF error.
 	for event, err := reader.ReadEvent(); err != io.EOF; event, err = reader.ReadEvent() {
 
@@ -364,7 +285,7 @@ func (a *arguments) execute(output io.Writer) error {
 		// We always print the event if the status index matches,
 		// otherwise the output could be quite confusing.
 		_, printStatus := statusIndices[index]
-		if printStatus || a.shouldPrint(event) {
+		if printStatus || (index >= a.startIndex && a.shouldPrint(event)) {
 			text, err := textFormat(event, !a.verboseText)
 			if err != nil {
 				return errors.WithMessage(err, "could not marshal event")
@@ -378,7 +299,10 @@ func (a *arguments) execute(output io.Writer) error {
 		if a.interactive {
 
 			// Apply event to SM.
-			actions, err := s.apply(event)
+			actions, err := player.Apply(event)
+			if divergence, ok := err.(*replay.Divergence); ok {
+				return errors.New(divergence.Pretty())
+			}
 			if err != nil {
 				return err
 			}
@@ -415,7 +339,7 @@ func (a *arguments) execute(output io.Writer) error {
 			// Print state machine status if requested for this index.
 			// Note that config options enforce that if printStatus is set, so is interactive
 			if printStatus {
-				status, err := s.status(event)
+				status, err := player.Node(event.NodeId).StateMachine.Status()
 				if err != nil {
 					return errors.WithMessage(err, "could not retrieve status")
 				}
@@ -431,26 +355,63 @@ func (a *arguments) execute(output io.Writer) error {
 
 		// If no node IDs have been provided, default to all.
 		if nodeIDs == nil {
-			for id := range s.nodes {
-				nodeIDs = append(nodeIDs, id)
-			}
-			sort.Slice(nodeIDs, func(i, j int) bool {
-				return nodeIDs[i] < nodeIDs[j]
-			})
+			nodeIDs = player.NodeIDs()
 		}
 
 		// Print execution time for each node.
 		for _, nodeID := range nodeIDs {
-			fmt.Fprintf(output, "Node %d successfully completed execution in %v\n", nodeID, s.nodes[nodeID].executionTime)
+			fmt.Fprintf(output, "Node %d successfully completed execution in %v\n", nodeID, player.Node(nodeID).ExecutionTime)
 		}
 	}
 
 	return nil
 }
 
+// eventReader is implemented by both the stream and segmented log readers.
+type eventReader interface {
+	ReadEvent() (*recording.Event, error)
+}
+
+// openReader returns a reader for the input, and the number of events
+// in the log which precede the first event it returns.  When reading
+// a segmented log in non-interactive mode, the reader is positioned
+// at the start index using the segment index.  Otherwise, all events
+// must be read, as they must be applied to the state machine.
+func (a *arguments) openReader() (eventReader, uint64, error) {
+	if a.inputDir == "" {
+		reader, err := eventlog.NewReader(a.input)
+		if err != nil {
+			return nil, 0, errors.WithMessage(err, "bad input file")
+		}
+		return reader, 0, nil
+	}
+
+	segmentIndex, err := eventlog.OpenSegmentIndex(a.inputDir)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "bad input directory")
+	}
+
+	eventNo := segmentIndex.FirstEventNo()
+	if !a.interactive && a.startIndex > eventNo+1 {
+		// Indices are one-based, while event numbers are zero-based.
+		eventNo = a.startIndex - 1
+	}
+
+	reader, err := segmentIndex.ReaderAt(eventNo)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "bad start index")
+	}
+
+	return reader, eventNo, nil
+}
+
 func parseArgs(args []string) (*arguments, error) {
 	app := kingpin.New("mircat", "Utility for processing Mir state event logs.")
 	input := app.Flag("input", "The input file to read (defaults to stdin).").Default(os.Stdin.Name()).File()
+	inputDir := app.Flag("inputDir", "A directory of log segments written by a file recorder to read instead of --input.").ExistingDir()
+	mergeFiles := app.Flag("merge", "Merge the recordings of several nodes from these files into a single timeline with aligned clocks (repeatable).").ExistingFiles()
+	startIndex := app.Flag("startIndex", "Do not report events before this index.  When reading a directory non-interactively, skips directly to it.").Uint64()
+	repl := app.Flag("repl", "Debug the log interactively, reading commands from stdin.  Implies --interactive.").Default("false").Bool()
 	interactive := app.Flag("interactive", "Whether to apply this log to a Mir state machine.").Default("false").Bool()
 	printActions := app.Flag("printActions", "Print actions produced by each event. (Must combine with --interactive)").Default("false").Bool()
 	nodeIDs := app.Flag("nodeID", "Report events from this nodeID only (useful for interleaved logs), may be repeated").Uint64List()
@@ -467,6 +428,13 @@ func parseArgs(args []string) (*arguments, error) {
 		return nil, err
 	}
 
+	if *repl {
+		if *inputDir == "" && (*input).Name() == os.Stdin.Name() {
+			return nil, errors.Errorf("cannot read the log from stdin with --repl")
+		}
+		*interactive = true
+	}
+
 	switch {
 	case *eventTypes != nil && *notEventTypes != nil:
 		return nil, errors.Errorf("cannot set both --eventType and --notEventType")
@@ -478,6 +446,12 @@ func parseArgs(args []string) (*arguments, error) {
 		return nil, errors.Errorf("cannot set logLevel for non-interactive playback")
 	case *printActions && !*interactive:
 		return nil, errors.Errorf("cannot print actions for non-interactive playback")
+	case *inputDir != "" && (*input).Name() != os.Stdin.Name():
+		return nil, errors.Errorf("cannot set both --input and --inputDir")
+	case *mergeFiles != nil && (*inputDir != "" || (*input).Name() != os.Stdin.Name()):
+		return nil, errors.Errorf("cannot combine --merge with --input or --inputDir")
+	case *mergeFiles != nil && *interactive:
+		return nil, errors.Errorf("cannot merge recordings for interactive playback")
 	}
 
 	mirLogLevel := statemachine.LevelInfo
@@ -495,7 +469,11 @@ func parseArgs(args []string) (*arguments, error) {
 
 	return &arguments{
 		input:         *input,
+		inputDir:      *inputDir,
+		mergeFiles:    *mergeFiles,
+		startIndex:    *startIndex,
 		interactive:   *interactive,
+		repl:          *repl,
 		printActions:  *printActions,
 		nodeIDs:       *nodeIDs,
 		eventTypes:    *eventTypes,
@@ -514,7 +492,14 @@ func main() {
 	if err != nil {
 		kingpin.Fatalf("failed to parse arguments, %s, try --help", err)
 	}
-	err = args.execute(os.Stdout)
+	switch {
+	case args.repl:
+		err = args.debug(os.Stdin, os.Stdout)
+	case args.mergeFiles != nil:
+		err = args.executeMerge(os.Stdout)
+	default:
+		err = args.execute(os.Stdout)
+	}
 	if err != nil {
 		fmt.Println("")
 		kingpin.Fatalf("%s", err)
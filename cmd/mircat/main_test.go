@@ -3,11 +3,19 @@ package main
 import (
 	"bytes"
 	"compress/gzip"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
 	"github.com/hyperledger-labs/mirbft/pkg/testengine"
 )
 
@@ -78,6 +86,35 @@ var _ = Describe("Parsing", func() {
 		})
 	})
 
+	When("both an input file and an input directory are present", func() {
+		It("returns an error", func() {
+			_, err := parseArgs([]string{
+				"--input", "main.go",
+				"--inputDir", ".",
+			})
+			Expect(err).To(MatchError("cannot set both --input and --inputDir"))
+		})
+	})
+
+	When("merging is combined with an input file", func() {
+		It("returns an error", func() {
+			_, err := parseArgs([]string{
+				"--input", "main.go",
+				"--merge", "main.go",
+			})
+			Expect(err).To(MatchError("cannot combine --merge with --input or --inputDir"))
+		})
+	})
+
+	When("the debugger would read the log from stdin", func() {
+		It("returns an error", func() {
+			_, err := parseArgs([]string{
+				"--repl",
+			})
+			Expect(err).To(MatchError("cannot read the log from stdin with --repl"))
+		})
+	})
+
 	When("status indexes are specified, but interactive is not", func() {
 		It("returns an error", func() {
 			_, err := parseArgs([]string{
@@ -125,7 +162,329 @@ var _ = Describe("Execution", func() {
 	It("reads from the source", func() {
 		err := args.execute(output)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(output.String()).To(ContainSubstring("1 [node_id=0 time=10 state_event=[initialize=[id=0 batch_size=1 heartbeat_ticks=2 suspect_ticks=4 new_epoch_timeout_ticks=8 buffer_size=5242880]]]"))
+		Expect(output.String()).To(ContainSubstring("1 [node_id=0 time=10 state_event=[initialize=[id=0 batch_size=1 heartbeat_ticks=2 suspect_ticks=4 new_epoch_timeout_ticks=8 buffer_size=5242880 batch_size_bytes=0 max_batch_latency_ticks=0 fetch_timeout_ticks=0 ack_resend_ticks=0 out_of_correct_epoch_ticks=0]]]"))
 		Expect(output.String()).To(ContainSubstring("4 [node_id=0 time=10 state_event=[complete_initialization=[]]]"))
 	})
+
+	It("replays recordings with the recorded timeouts", func() {
+		logBytes.Reset()
+		gzWriter := gzip.NewWriter(logBytes)
+
+		recorder := (&testengine.Spec{
+			NodeCount:     4,
+			ClientCount:   4,
+			ReqsPerClient: 20,
+			TweakRecorder: func(r *testengine.Recorder) {
+				for _, nodeConfig := range r.NodeConfigs {
+					nodeConfig.InitParms.FetchTimeoutTicks = 8
+					nodeConfig.InitParms.AckResendTicks = 40
+					nodeConfig.InitParms.OutOfCorrectEpochTicks = 20
+				}
+			},
+		}).Recorder()
+
+		recording, err := recorder.Recording(gzWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = recording.DrainClients(5000)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gzWriter.Close()).To(Succeed())
+
+		err = args.execute(output)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.String()).To(ContainSubstring("fetch_timeout_ticks=8 ack_resend_ticks=40 out_of_correct_epoch_ticks=20"))
+	})
+
+	It("skips to the start index of a segmented log", func() {
+		dir, err := ioutil.TempDir("", "mircat.*")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		recorder, err := eventlog.NewFileRecorder(3, dir, eventlog.IndexIntervalOpt(10))
+		Expect(err).NotTo(HaveOccurred())
+		for i := 0; i < 50; i++ {
+			err := recorder.Intercept(&state.Event{
+				Type: &state.Event_TickElapsed{
+					TickElapsed: &state.EventTickElapsed{},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(recorder.Stop()).To(Succeed())
+
+		args = &arguments{
+			inputDir:   dir,
+			startIndex: 42,
+		}
+
+		err = args.execute(output)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output.String()).NotTo(ContainSubstring("    41 [node_id=3"))
+		Expect(output.String()).To(ContainSubstring("    42 [node_id=3"))
+		Expect(output.String()).To(ContainSubstring("    50 [node_id=3"))
+	})
+})
+
+var _ = Describe("Debugger", func() {
+	var (
+		logFile *os.File
+		output  *bytes.Buffer
+		args    *arguments
+	)
+
+	BeforeEach(func() {
+		var err error
+		logFile, err = ioutil.TempFile("", "mircat.*")
+		Expect(err).NotTo(HaveOccurred())
+
+		gzWriter := gzip.NewWriter(logFile)
+		recorder := (&testengine.Spec{
+			NodeCount:     4,
+			ClientCount:   4,
+			ReqsPerClient: 20,
+		}).Recorder()
+
+		recording, err := recorder.Recording(gzWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = recording.DrainClients(5000)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gzWriter.Close()).To(Succeed())
+
+		output = &bytes.Buffer{}
+		args = &arguments{
+			input:       logFile,
+			repl:        true,
+			interactive: true,
+			logLevel:    statemachine.LevelError,
+		}
+	})
+
+	AfterEach(func() {
+		os.Remove(logFile.Name())
+	})
+
+	debug := func(commands ...string) string {
+		err := args.debug(strings.NewReader(strings.Join(commands, "\n")), output)
+		Expect(err).NotTo(HaveOccurred())
+		return output.String()
+	}
+
+	It("steps through events", func() {
+		result := debug("step 3", "event")
+		Expect(result).To(ContainSubstring("     1 [node_id=0 time=10 state_event=[initialize="))
+		Expect(result).To(ContainSubstring("     3 [node_id=0 time=10 state_event=[load_persisted_entry=[index=2"))
+		Expect(strings.Count(result, "     3 [node_id=0")).To(Equal(2))
+	})
+
+	It("stops at breakpoints and travels back in time", func() {
+		result := debug(
+			"break node=1 msg=Preprepare",
+			"continue",
+			"back 2",
+			"goto 1",
+			"breakpoints",
+			"delete 0",
+			"breakpoints",
+		)
+		Expect(result).To(ContainSubstring("breakpoint 0 (node=1 msg=Preprepare) set"))
+		Expect(result).To(ContainSubstring("breakpoint 0 (node=1 msg=Preprepare) hit\n  2039 [node_id=1"))
+		Expect(result).To(ContainSubstring("  2037 [node_id=0"))
+		Expect(result).To(ContainSubstring("(mircat)      1 [node_id=0"))
+		Expect(strings.Count(result, "0: node=1 msg=Preprepare")).To(Equal(1))
+	})
+
+	It("runs until status conditions hold and prints the status", func() {
+		result := debug(
+			"until node=2 watermark-moves",
+			"status",
+			"json",
+		)
+		Expect(result).To(ContainSubstring("    14 [node_id=2 time=10 state_event=[complete_initialization=[]]]"))
+		Expect(result).To(ContainSubstring("NodeID=2, LowWatermark=1, HighWatermark=40, Epoch=1"))
+		Expect(result).To(ContainSubstring(`"node_id": 2,`))
+	})
+
+	It("travels back over applied events without replaying them", func() {
+		opened := 0
+		d := &debugger{
+			args:   args,
+			output: output,
+			open: func() (eventReader, uint64, error) {
+				opened++
+				return args.rewindReader()
+			},
+			breakpoints: map[int]*predicate{},
+		}
+		Expect(d.restart()).To(Succeed())
+		defer d.close()
+
+		execute := func(line string) string {
+			output.Reset()
+			_, err := d.execute(line)
+			Expect(err).NotTo(HaveOccurred())
+			return output.String()
+		}
+
+		execute("goto 100")
+		Expect(execute("back 60")).To(HavePrefix("    40 [node_id="))
+		Expect(execute("step 2")).To(ContainSubstring("    42 [node_id="))
+		Expect(execute("goto 0")).To(Equal("no event has been applied\n"))
+		Expect(execute("step")).To(HavePrefix("     1 [node_id=0 time=10 state_event=[initialize="))
+		Expect(opened).To(Equal(1))
+
+		By("replaying once the state at the earlier event is needed")
+		execute("goto 40")
+		status := execute("json 0")
+		Expect(opened).To(Equal(2))
+
+		fresh := &debugger{
+			args:        args,
+			output:      output,
+			open:        args.rewindReader,
+			breakpoints: map[int]*predicate{},
+		}
+		Expect(fresh.restart()).To(Succeed())
+		defer fresh.close()
+		d = fresh
+		execute("goto 40")
+		Expect(execute("json 0")).To(Equal(status))
+	})
+
+	It("reports bad commands and predicates", func() {
+		result := debug(
+			"bogus",
+			"until node=x",
+			"until color=red",
+			"status",
+			"quit",
+			"step",
+		)
+		Expect(result).To(ContainSubstring("error: unknown command 'bogus', try help"))
+		Expect(result).To(ContainSubstring("error: predicate term 'node=x' requires a number"))
+		Expect(result).To(ContainSubstring("error: unknown predicate term 'color=red'"))
+		Expect(result).To(ContainSubstring("error: no event has been applied, specify a node"))
+		Expect(result).NotTo(ContainSubstring("     1 [node_id=0"))
+	})
+})
+
+var _ = Describe("Merging", func() {
+	var (
+		dir       string
+		output    *bytes.Buffer
+		args      *arguments
+		clockSkew = map[uint64]int64{0: 0, 1: 1000, 2: -500, 3: 0}
+	)
+
+	BeforeEach(func() {
+		logBytes := &bytes.Buffer{}
+		gzWriter := gzip.NewWriter(logBytes)
+
+		recorder := (&testengine.Spec{
+			NodeCount:     4,
+			ClientCount:   4,
+			ReqsPerClient: 20,
+		}).Recorder()
+
+		recording, err := recorder.Recording(gzWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = recording.DrainClients(5000)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gzWriter.Close()).To(Succeed())
+
+		dir, err = ioutil.TempDir("", "mircat.*")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Split the interleaved recording into one file per node,
+		// skewing the clock of each node.
+		reader, err := eventlog.NewReader(logBytes)
+		Expect(err).NotTo(HaveOccurred())
+
+		writers := map[uint64]*gzip.Writer{}
+		args = &arguments{}
+		for {
+			event, err := reader.ReadEvent()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			writer, ok := writers[event.NodeId]
+			if !ok {
+				file, err := os.Create(filepath.Join(dir, fmt.Sprintf("node%d.gz", event.NodeId)))
+				Expect(err).NotTo(HaveOccurred())
+				defer file.Close()
+				writer = gzip.NewWriter(file)
+				writers[event.NodeId] = writer
+				args.mergeFiles = append(args.mergeFiles, file.Name())
+			}
+
+			// Steps from peers are recorded with the signature of their
+			// sender, while steps to self are looped back unsigned.
+			if step, ok := event.StateEvent.Type.(*state.Event_Step); ok && step.Step.Source != event.NodeId {
+				step.Step.Msg.Signature = []byte(fmt.Sprintf("signed-by-%d", step.Step.Source))
+			}
+
+			event.Time += clockSkew[event.NodeId]
+			Expect(eventlog.WriteRecordedEvent(writer, event)).To(Succeed())
+		}
+
+		for _, writer := range writers {
+			Expect(writer.Close()).To(Succeed())
+		}
+
+		output = &bytes.Buffer{}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("aligns the clocks of the nodes", func() {
+		Expect(args.executeMerge(output)).To(Succeed())
+		Expect(output.String()).To(HavePrefix("node 0 clock offset 0\nnode 1 clock offset "))
+
+		t := newTimeline()
+		for _, name := range args.mergeFiles {
+			Expect(loadFile(t, name)).To(Succeed())
+		}
+		Expect(t.reconstructSends()).To(Succeed())
+		Expect(t.matchSteps()).To(Succeed())
+		t.alignClocks()
+
+		// The estimate is only exact if the minimal latencies
+		// between each pair of nodes are symmetric.
+		for nodeID, skew := range clockSkew {
+			Expect(t.offsets[nodeID]).To(BeNumerically("~", -skew, 10))
+		}
+	})
+
+	It("annotates steps with the event which sent the message", func() {
+		args.nodeIDs = []uint64{1}
+		args.stepTypes = []string{"Preprepare"}
+		Expect(args.executeMerge(output)).To(Succeed())
+		Expect(output.String()).To(MatchRegexp(`\[node_id=1 time=\d+ state_event=\[step=\[source=0 msg=\[preprepare=\[seq_no=\d+ epoch=1 .*\n       sent by:  +\d+ node_id=0 HashResult\n`))
+	})
+
+	It("orders every step after the event which sent it", func() {
+		t := newTimeline()
+		for _, name := range args.mergeFiles {
+			Expect(loadFile(t, name)).To(Succeed())
+		}
+		Expect(t.reconstructSends()).To(Succeed())
+		Expect(t.matchSteps()).To(Succeed())
+		t.alignClocks()
+
+		var matched int
+		for _, te := range t.merge() {
+			if _, ok := te.event.StateEvent.Type.(*state.Event_Step); !ok {
+				continue
+			}
+			Expect(te.origin).NotTo(BeNil())
+			Expect(te.origin.index).To(BeNumerically("<", te.index))
+			matched++
+		}
+		Expect(matched).To(BeNumerically(">", 0))
+	})
 })
@@ -0,0 +1,375 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog/replay"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+// timelineEvent is a recorded event placed in the merged timeline.
+type timelineEvent struct {
+	event *recording.Event
+
+	// origin is, for a step, the event of the sending node whose
+	// application produced the action which sent the message.
+	origin *timelineEvent
+
+	alignedTime int64
+	index       uint64
+	emitted     bool
+}
+
+// sentMsg is a message sent by a node, as reconstructed from the actions
+// of its replayed state machine.  The message is sent once the actions
+// are handed off, so the hand-off time is the time it was sent.
+type sentMsg struct {
+	origin      *timelineEvent
+	handoffTime int64
+}
+
+// timeline merges the recordings of several nodes.
+type timeline struct {
+	nodes map[uint64][]*timelineEvent
+
+	// sent contains the reconstructed messages in the order they were
+	// sent, keyed by source, destination and message.
+	sent map[string][]*sentMsg
+
+	// minDelays contains, for each source and destination, the smallest
+	// difference between the local receive and local send times.
+	minDelays map[[2]uint64]int64
+
+	offsets map[uint64]int64
+}
+
+// sendKey identifies a message from source to dest.
+func sendKey(source, dest uint64, msg *msgs.Msg) string {
+	return fmt.Sprintf("%d-%d-%x", source, dest, replay.MsgKey(msg))
+}
+
+func newTimeline() *timeline {
+	return &timeline{
+		nodes:     map[uint64][]*timelineEvent{},
+		sent:      map[string][]*sentMsg{},
+		minDelays: map[[2]uint64]int64{},
+		offsets:   map[uint64]int64{},
+	}
+}
+
+// load reads all events of a recording.  A node's events
+// must all be contained in a single recording.
+func (t *timeline) load(reader eventReader, name string) error {
+	seen := map[uint64]struct{}{}
+	for {
+		event, err := reader.ReadEvent()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "failed reading %s", name)
+		}
+
+		if _, ok := seen[event.NodeId]; !ok {
+			if _, ok := t.nodes[event.NodeId]; ok {
+				return errors.Errorf("events of node %d are contained in more than one input, including %s", event.NodeId, name)
+			}
+			seen[event.NodeId] = struct{}{}
+		}
+
+		t.nodes[event.NodeId] = append(t.nodes[event.NodeId], &timelineEvent{
+			event: event,
+		})
+	}
+}
+
+func (t *timeline) nodeIDs() []uint64 {
+	nodeIDs := make([]uint64, 0, len(t.nodes))
+	for nodeID := range t.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		return nodeIDs[i] < nodeIDs[j]
+	})
+	return nodeIDs
+}
+
+// reconstructSends replays the events of each node to recover
+// the messages it sent, and the events which caused them.
+func (t *timeline) reconstructSends() error {
+	player := replay.NewPlayer(
+		replay.LoggerOpt(func(uint64) statemachine.Logger {
+			return namedLogger{
+				output: ioutil.Discard,
+				level:  statemachine.LevelError,
+			}
+		}),
+	)
+
+	for _, nodeID := range t.nodeIDs() {
+		type unsent struct {
+			key    string
+			origin *timelineEvent
+		}
+		var pending []unsent
+
+		for _, te := range t.nodes[nodeID] {
+			actions, err := player.Apply(te.event)
+			if err != nil {
+				return errors.WithMessagef(err, "could not replay node %d", nodeID)
+			}
+
+			if _, ok := te.event.StateEvent.Type.(*state.Event_ActionsReceived); ok {
+				for _, u := range pending {
+					t.sent[u.key] = append(t.sent[u.key], &sentMsg{
+						origin:      u.origin,
+						handoffTime: te.event.Time,
+					})
+				}
+				pending = nil
+				continue
+			}
+
+			iter := actions.Iterator()
+			for action := iter.Next(); action != nil; action = iter.Next() {
+				var targets []uint64
+				var msg *msgs.Msg
+				switch at := action.Type.(type) {
+				case *state.Action_Send:
+					targets, msg = at.Send.Targets, at.Send.Msg
+				case *state.Action_ForwardRequest:
+					targets = at.ForwardRequest.Targets
+					msg = &msgs.Msg{
+						Type: &msgs.Msg_ForwardRequest{
+							ForwardRequest: &msgs.ForwardRequest{
+								RequestAck: at.ForwardRequest.Ack,
+							},
+						},
+					}
+				default:
+					continue
+				}
+
+				for _, target := range targets {
+					pending = append(pending, unsent{key: sendKey(nodeID, target, msg), origin: te})
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchSteps annotates each step with the event which caused its
+// message to be sent, and collects the delays between the nodes.
+func (t *timeline) matchSteps() error {
+	for nodeID, events := range t.nodes {
+		for _, te := range events {
+			step, ok := te.event.StateEvent.Type.(*state.Event_Step)
+			if !ok {
+				continue
+			}
+
+			key := sendKey(step.Step.Source, nodeID, step.Step.Msg)
+			sends := t.sent[key]
+			if len(sends) == 0 {
+				// The sender's recording may be missing or truncated.
+				continue
+			}
+			t.sent[key] = sends[1:]
+
+			te.origin = sends[0].origin
+			if step.Step.Source == nodeID {
+				continue
+			}
+
+			pair := [2]uint64{step.Step.Source, nodeID}
+			delay := te.event.Time - sends[0].handoffTime
+			if minDelay, ok := t.minDelays[pair]; !ok || delay < minDelay {
+				t.minDelays[pair] = delay
+			}
+		}
+	}
+
+	return nil
+}
+
+// alignClocks computes the offset of each node's clock relative to the clock
+// of the lowest node ID.  The offset between two nodes which exchanged
+// messages in both directions is estimated assuming the minimal latency is
+// symmetric, as NTP does.  Where messages were only sent in one direction,
+// the minimal latency is assumed to be zero.  Nodes which cannot be related
+// to the first node keep their own clocks.
+func (t *timeline) alignClocks() {
+	nodeIDs := t.nodeIDs()
+	if len(nodeIDs) == 0 {
+		return
+	}
+
+	t.offsets[nodeIDs[0]] = 0
+	queue := []uint64{nodeIDs[0]}
+	for len(queue) > 0 {
+		known := queue[0]
+		queue = queue[1:]
+		for _, nodeID := range nodeIDs {
+			if _, ok := t.offsets[nodeID]; ok {
+				continue
+			}
+
+			// o_n - o_k >= -forward, and o_n - o_k <= backward.
+			forward, hasForward := t.minDelays[[2]uint64{known, nodeID}]
+			backward, hasBackward := t.minDelays[[2]uint64{nodeID, known}]
+			switch {
+			case hasForward && hasBackward:
+				t.offsets[nodeID] = t.offsets[known] + (backward-forward)/2
+			case hasForward:
+				t.offsets[nodeID] = t.offsets[known] - forward
+			case hasBackward:
+				t.offsets[nodeID] = t.offsets[known] + backward
+			default:
+				continue
+			}
+			queue = append(queue, nodeID)
+		}
+	}
+
+	for _, nodeID := range nodeIDs {
+		offset := t.offsets[nodeID]
+		for _, te := range t.nodes[nodeID] {
+			te.alignedTime = te.event.Time + offset
+		}
+	}
+}
+
+// merge orders the events of all nodes by their aligned time, preserving
+// the order of each node's events, and never placing a step before the
+// event which caused its message to be sent.
+func (t *timeline) merge() []*timelineEvent {
+	nodeIDs := t.nodeIDs()
+	heads := make([]int, len(nodeIDs))
+
+	var result []*timelineEvent
+	for {
+		next := -1
+		fallback := -1
+		for i, nodeID := range nodeIDs {
+			events := t.nodes[nodeID]
+			if heads[i] >= len(events) {
+				continue
+			}
+
+			te := events[heads[i]]
+			if fallback < 0 || te.alignedTime < t.nodes[nodeIDs[fallback]][heads[fallback]].alignedTime {
+				fallback = i
+			}
+
+			if te.origin != nil && !te.origin.emitted {
+				continue
+			}
+
+			if next < 0 || te.alignedTime < t.nodes[nodeIDs[next]][heads[next]].alignedTime {
+				next = i
+			}
+		}
+
+		if fallback < 0 {
+			return result
+		}
+
+		if next < 0 {
+			// The causal order is cyclic, which should be impossible
+			// unless the recordings are inconsistent.
+			next = fallback
+		}
+
+		te := t.nodes[nodeIDs[next]][heads[next]]
+		heads[next]++
+		te.emitted = true
+		te.index = uint64(len(result) + 1)
+		result = append(result, te)
+	}
+}
+
+func describeEvent(event *recording.Event) string {
+	if step, ok := event.StateEvent.Type.(*state.Event_Step); ok {
+		return fmt.Sprintf("Step %s from node %d", msgTypeName(step.Step.Msg), step.Step.Source)
+	}
+	return eventTypeName(event.StateEvent)
+}
+
+// executeMerge prints the events of several recordings as a single
+// timeline, with each event stamped with its aligned time.
+func (a *arguments) executeMerge(output io.Writer) error {
+	t := newTimeline()
+	for _, name := range a.mergeFiles {
+		if err := loadFile(t, name); err != nil {
+			return err
+		}
+	}
+
+	if err := t.reconstructSends(); err != nil {
+		return err
+	}
+
+	if err := t.matchSteps(); err != nil {
+		return err
+	}
+
+	t.alignClocks()
+
+	for _, nodeID := range t.nodeIDs() {
+		fmt.Fprintf(output, "node %d clock offset %d\n", nodeID, t.offsets[nodeID])
+	}
+
+	for _, te := range t.merge() {
+		if excludedByNodeID(te.event, a.nodeIDs) || !a.shouldPrint(te.event) {
+			continue
+		}
+
+		aligned := proto.Clone(te.event).(*recording.Event)
+		aligned.Time = te.alignedTime
+		text, err := textFormat(aligned, !a.verboseText)
+		if err != nil {
+			return errors.WithMessage(err, "could not marshal event")
+		}
+
+		fmt.Fprintf(output, "% 6d %s\n", te.index, text)
+		if te.origin != nil {
+			fmt.Fprintf(output, "       sent by: % 6d node_id=%d %s\n", te.origin.index, te.origin.event.NodeId, describeEvent(te.origin.event))
+		}
+	}
+
+	return nil
+}
+
+func loadFile(t *timeline, name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return errors.WithMessage(err, "could not open input")
+	}
+	defer file.Close()
+
+	reader, err := eventlog.NewReader(file)
+	if err != nil {
+		return errors.WithMessagef(err, "bad input file %s", name)
+	}
+
+	return t.load(reader, name)
+}
@@ -41,12 +41,12 @@ var _ = Describe("textmarshal", func() {
 	It("concisely marshals mirbft messages", func() {
 		txt, err := textFormat(sampleStepEvent, true)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(txt).To(Equal("[node_id=7 time=9 state_event=[step=[source=4 msg=[prepare=[seq_no=11 epoch=0 digest=deadbeef]]]]]"))
+		Expect(txt).To(Equal("[node_id=7 time=9 state_event=[step=[source=4 msg=[prepare=[seq_no=11 epoch=0 digest=deadbeef] signature=]]]]"))
 	})
 
 	It("verbosely marshals mirbft messages", func() {
 		txt, err := textFormat(sampleStepEvent, false)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(txt).To(Equal("[node_id=7 time=9 state_event=[step=[source=4 msg=[prepare=[seq_no=11 epoch=0 digest=deadbeefdeadbeef]]]]]"))
+		Expect(txt).To(Equal("[node_id=7 time=9 state_event=[step=[source=4 msg=[prepare=[seq_no=11 epoch=0 digest=deadbeefdeadbeef] signature=]]]]"))
 	})
 })
@@ -6,6 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 
 package mirbft
 
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
 type Config struct {
 	// Logger provides the logging functions.
 	Logger Logger
@@ -14,6 +20,16 @@ type Config struct {
 	// before it is cut. (Note, batches may be cut earlier, so this is a max size).
 	BatchSize uint32
 
+	// BatchSizeBytes determines how large a batch may grow (in bytes of request
+	// data) before it is cut.  A request larger than this is sent in a batch of
+	// its own.  If zero, batches are limited by BatchSize alone.
+	BatchSizeBytes uint64
+
+	// MaxBatchLatencyTicks is the number of ticks a leader may hold a partial
+	// batch before it is cut, regardless of its size.  If zero, partial batches
+	// are only cut when a heartbeat is due.
+	MaxBatchLatencyTicks uint32
+
 	// HeartbeatTicks is the number of ticks before a heartbeat is emitted
 	// by a leader.
 	HeartbeatTicks uint32
@@ -33,4 +49,48 @@ type Config struct {
 	// result is computed, watermarks cannot advance).  This should be set
 	// to a minimum of a few MB.
 	BufferSize uint32
+
+	// FetchTimeoutTicks is the number of ticks a replica waits for a fetched
+	// request before fetching it again from another node.  If zero, it
+	// defaults to statemachine.DefaultFetchTimeoutTicks.  High latency
+	// networks should increase this to avoid constant refetching.
+	FetchTimeoutTicks uint32
+
+	// AckResendTicks is the number of ticks before a replica first re-sends
+	// its acks for requests which are not yet correct, the interval grows
+	// linearly with each re-send.  If zero, it defaults to
+	// statemachine.DefaultAckResendTicks.  It must be greater than the
+	// FetchTimeoutTicks.
+	AckResendTicks uint32
+
+	// OutOfCorrectEpochTicks is the number of ticks a replica remains in an
+	// epoch after a quorum of nodes has been observed in a later one, before
+	// abandoning it.  If zero, it defaults to
+	// statemachine.DefaultOutOfCorrectEpochTicks.
+	OutOfCorrectEpochTicks uint32
+}
+
+func (c *Config) validate() error {
+	switch {
+	case c.BatchSize == 0:
+		return errors.Errorf("batch size must be greater than 0")
+	case c.NewEpochTimeoutTicks <= 1:
+		return errors.Errorf("new epoch timeout ticks must be greater than 1, got %d", c.NewEpochTimeoutTicks)
+	}
+
+	fetchTimeoutTicks := c.FetchTimeoutTicks
+	if fetchTimeoutTicks == 0 {
+		fetchTimeoutTicks = statemachine.DefaultFetchTimeoutTicks
+	}
+
+	ackResendTicks := c.AckResendTicks
+	if ackResendTicks == 0 {
+		ackResendTicks = statemachine.DefaultAckResendTicks
+	}
+
+	if ackResendTicks <= fetchTimeoutTicks {
+		return errors.Errorf("ack resend ticks (%d) must be greater than fetch timeout ticks (%d)", ackResendTicks, fetchTimeoutTicks)
+	}
+
+	return nil
 }
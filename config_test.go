@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+)
+
+var _ = Describe("Config", func() {
+	var config *mirbft.Config
+
+	BeforeEach(func() {
+		config = &mirbft.Config{
+			BatchSize:            1,
+			SuspectTicks:         4,
+			HeartbeatTicks:       2,
+			NewEpochTimeoutTicks: 8,
+			BufferSize:           5 * 1024 * 1024,
+			Logger:               mirbft.ConsoleWarnLogger,
+		}
+	})
+
+	It("accepts the default timeouts", func() {
+		_, err := mirbft.NewNode(0, config, &mirbft.ProcessorConfig{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("accepts custom timeouts", func() {
+		config.FetchTimeoutTicks = 40
+		config.AckResendTicks = 200
+		config.OutOfCorrectEpochTicks = 100
+		_, err := mirbft.NewNode(0, config, &mirbft.ProcessorConfig{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects a fetch timeout beyond the default ack resend interval", func() {
+		config.FetchTimeoutTicks = 40
+		_, err := mirbft.NewNode(0, config, &mirbft.ProcessorConfig{})
+		Expect(err).To(MatchError("invalid config: ack resend ticks (20) must be greater than fetch timeout ticks (40)"))
+	})
+
+	It("rejects a new epoch timeout of a single tick", func() {
+		config.NewEpochTimeoutTicks = 1
+		_, err := mirbft.NewNode(0, config, &mirbft.ProcessorConfig{})
+		Expect(err).To(MatchError("invalid config: new epoch timeout ticks must be greater than 1, got 1"))
+	})
+})
@@ -8,6 +8,9 @@ package mirbft
 
 import (
 	"fmt"
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
 )
 
 type LogLevel int
@@ -21,6 +24,10 @@ const (
 
 type consoleLogger LogLevel
 
+func (l consoleLogger) Enabled(level LogLevel, component string) bool {
+	return level >= LogLevel(l)
+}
+
 func (l consoleLogger) Log(level LogLevel, text string, args ...interface{}) {
 	if level < LogLevel(l) {
 		return
@@ -65,3 +72,91 @@ type Logger interface {
 	// values are unspecified.
 	Log(level LogLevel, text string, args ...interface{})
 }
+
+// LevelChecker may be implemented by a Logger which discards some entries,
+// so that entries which would be discarded anyway need not be built.
+type LevelChecker interface {
+	// Enabled returns whether an entry at the given level, from the given
+	// state machine component, would be logged.
+	Enabled(level LogLevel, component string) bool
+}
+
+// ComponentLevels is a Logger which discards the entries below the level
+// set for the state machine component which emitted them, and passes the
+// rest to another Logger.  Components are identified by the
+// statemachine.ComponentKey tag, and entries without it are filtered by the
+// default level.  The levels may be changed at runtime, concurrently with
+// logging.
+type ComponentLevels struct {
+	logger Logger
+
+	mutex        sync.RWMutex
+	defaultLevel LogLevel
+	levels       map[string]LogLevel
+}
+
+// NewComponentLevels creates a ComponentLevels which passes entries at or
+// above the default level to the given logger.
+func NewComponentLevels(logger Logger, defaultLevel LogLevel) *ComponentLevels {
+	return &ComponentLevels{
+		logger:       logger,
+		defaultLevel: defaultLevel,
+		levels:       map[string]LogLevel{},
+	}
+}
+
+// SetDefaultLevel sets the level for components without a level of their own.
+func (cl *ComponentLevels) SetDefaultLevel(level LogLevel) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	cl.defaultLevel = level
+}
+
+// SetLevel sets the level for a component, such as statemachine.ComponentEpochTracker.
+func (cl *ComponentLevels) SetLevel(component string, level LogLevel) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	cl.levels[component] = level
+}
+
+// ClearLevel reverts a component to the default level.
+func (cl *ComponentLevels) ClearLevel(component string) {
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+	delete(cl.levels, component)
+}
+
+// Level returns the level in effect for a component.
+func (cl *ComponentLevels) Level(component string) LogLevel {
+	cl.mutex.RLock()
+	defer cl.mutex.RUnlock()
+	if level, ok := cl.levels[component]; ok {
+		return level
+	}
+	return cl.defaultLevel
+}
+
+func (cl *ComponentLevels) Enabled(level LogLevel, component string) bool {
+	if level < cl.Level(component) {
+		return false
+	}
+
+	if checker, ok := cl.logger.(LevelChecker); ok {
+		return checker.Enabled(level, component)
+	}
+
+	return true
+}
+
+func (cl *ComponentLevels) Log(level LogLevel, text string, args ...interface{}) {
+	var component string
+	if len(args) >= 2 && args[0] == statemachine.ComponentKey {
+		component, _ = args[1].(string)
+	}
+
+	if level < cl.Level(component) {
+		return
+	}
+
+	cl.logger.Log(level, text, args...)
+}
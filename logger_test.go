@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+type logEntry struct {
+	level mirbft.LogLevel
+	text  string
+}
+
+type recordingLogger struct {
+	entries []logEntry
+}
+
+func (rl *recordingLogger) Log(level mirbft.LogLevel, text string, args ...interface{}) {
+	rl.entries = append(rl.entries, logEntry{level: level, text: text})
+}
+
+var _ = Describe("ComponentLevels", func() {
+	var (
+		recorder *recordingLogger
+		levels   *mirbft.ComponentLevels
+	)
+
+	BeforeEach(func() {
+		recorder = &recordingLogger{}
+		levels = mirbft.NewComponentLevels(recorder, mirbft.LevelInfo)
+	})
+
+	logAll := func() {
+		levels.Log(mirbft.LevelDebug, "untagged")
+		levels.Log(mirbft.LevelDebug, "epoch", statemachine.ComponentKey, statemachine.ComponentEpochTracker, "epoch_no", 3)
+		levels.Log(mirbft.LevelDebug, "checkpoint", statemachine.ComponentKey, statemachine.ComponentCheckpointTracker)
+		levels.Log(mirbft.LevelWarn, "warning", statemachine.ComponentKey, statemachine.ComponentCheckpointTracker)
+	}
+
+	It("filters entries by the default level", func() {
+		logAll()
+		Expect(recorder.entries).To(Equal([]logEntry{
+			{level: mirbft.LevelWarn, text: "warning"},
+		}))
+	})
+
+	It("filters entries by the level of their component", func() {
+		levels.SetLevel(statemachine.ComponentEpochTracker, mirbft.LevelDebug)
+		levels.SetLevel(statemachine.ComponentCheckpointTracker, mirbft.LevelError)
+		logAll()
+		Expect(recorder.entries).To(Equal([]logEntry{
+			{level: mirbft.LevelDebug, text: "epoch"},
+		}))
+
+		levels.ClearLevel(statemachine.ComponentCheckpointTracker)
+		levels.SetDefaultLevel(mirbft.LevelDebug)
+		Expect(levels.Level(statemachine.ComponentCheckpointTracker)).To(Equal(mirbft.LevelDebug))
+	})
+
+	It("reports which entries it would log", func() {
+		levels.SetLevel(statemachine.ComponentEpochTracker, mirbft.LevelDebug)
+		Expect(levels.Enabled(mirbft.LevelDebug, statemachine.ComponentEpochTracker)).To(BeTrue())
+		Expect(levels.Enabled(mirbft.LevelDebug, statemachine.ComponentCheckpointTracker)).To(BeFalse())
+		Expect(levels.Enabled(mirbft.LevelInfo, statemachine.ComponentCheckpointTracker)).To(BeTrue())
+
+		By("deferring to the level of the wrapped logger")
+		levels = mirbft.NewComponentLevels(mirbft.ConsoleWarnLogger, mirbft.LevelDebug)
+		Expect(levels.Enabled(mirbft.LevelInfo, statemachine.ComponentEpochTracker)).To(BeFalse())
+		Expect(levels.Enabled(mirbft.LevelWarn, statemachine.ComponentEpochTracker)).To(BeTrue())
+	})
+})
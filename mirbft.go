@@ -16,6 +16,7 @@ package mirbft
 import (
 	"context"
 	"fmt"
+	"runtime"
 	"sync"
 	"time"
 
@@ -32,6 +33,7 @@ var ErrStopped = fmt.Errorf("stopped at caller request")
 type replicas struct {
 	mutex    sync.Mutex
 	eventC   chan *statemachine.EventList
+	verifyCs []chan *stepRequest
 	replicas processor.Replicas
 }
 
@@ -41,6 +43,17 @@ func (r *replicas) replica(id uint64) *processor.Replica {
 	return r.replicas.Replica(id)
 }
 
+func (r *replicas) verificationFailures() map[uint64]uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.replicas.VerificationFailures()
+}
+
+type stepRequest struct {
+	source uint64
+	msg    *msgs.Msg
+}
+
 type Client struct {
 	client          *processor.Client
 	resultC         chan<- *statemachine.EventList
@@ -90,6 +103,7 @@ type Node struct {
 	clientResultsC   chan *statemachine.EventList
 	hashActionsC     chan *statemachine.ActionList
 	hashResultsC     chan *statemachine.EventList
+	hashJobsC        chan *hashJob
 	netActionsC      chan *statemachine.ActionList
 	netResultsC      chan *statemachine.EventList
 	appActionsC      chan *statemachine.ActionList
@@ -139,21 +153,46 @@ func NewNode(
 	config *Config,
 	processorConfig *ProcessorConfig,
 ) (*Node, error) {
+	if err := config.validate(); err != nil {
+		return nil, errors.WithMessage(err, "invalid config")
+	}
+
+	clients := &processor.Clients{
+		RequestStore:     processorConfig.RequestStore,
+		Hasher:           processorConfig.Hasher,
+		RequestValidator: processorConfig.RequestValidator,
+		RateLimit:        processorConfig.ClientRateLimit,
+	}
+
+	var verifyCs []chan *stepRequest
+	if processorConfig.Verifier != nil {
+		verifyCs = make([]chan *stepRequest, runtime.NumCPU())
+		for i := range verifyCs {
+			// Buffering lets Step return while earlier messages
+			// from the same source are still being verified.
+			verifyCs[i] = make(chan *stepRequest, 100)
+		}
+	}
+
 	return &Node{
 		ID:              id,
 		Config:          config,
 		processorConfig: processorConfig,
 
 		replicas: &replicas{
-			eventC: make(chan *statemachine.EventList),
+			eventC:   make(chan *statemachine.EventList),
+			verifyCs: verifyCs,
+			replicas: processor.Replicas{
+				Clients:       clients,
+				StateTransfer: processorConfig.StateTransfer,
+				Verifier:      processorConfig.Verifier,
+				Metrics:       processorConfig.Metrics,
+			},
 		},
 		stateMachine: &statemachine.StateMachine{
 			Logger: logAdapter{Logger: config.Logger},
 		},
-		clients: &processor.Clients{
-			RequestStore: processorConfig.RequestStore,
-			Hasher:       processorConfig.Hasher,
-		},
+		clients:         clients,
 		workItems:       processor.NewWorkItems(),
 		workErrNotifier: newWorkErrNotifier(),
 
@@ -164,6 +203,7 @@ func NewNode(
 		clientResultsC:   make(chan *statemachine.EventList),
 		hashActionsC:     make(chan *statemachine.ActionList),
 		hashResultsC:     make(chan *statemachine.EventList),
+		hashJobsC:        make(chan *hashJob),
 		netActionsC:      make(chan *statemachine.ActionList),
 		netResultsC:      make(chan *statemachine.EventList),
 		appActionsC:      make(chan *statemachine.ActionList),
@@ -202,7 +242,22 @@ func (n *Node) Status(ctx context.Context) (*status.StateMachine, error) {
 	}
 }
 
+// Step passes a message received from the given source to the node.  If a Verifier is
+// configured, the message is verified asynchronously by a pool of workers, and Step returns
+// as soon as the message has been handed to a worker.  Messages from a given source are
+// always verified by the same worker, so their order is preserved.
 func (n *Node) Step(ctx context.Context, source uint64, msg *msgs.Msg) error {
+	if n.replicas.verifyCs != nil {
+		select {
+		case n.replicas.verifyCs[source%uint64(len(n.replicas.verifyCs))] <- &stepRequest{source: source, msg: msg}:
+			return nil
+		case <-n.workErrNotifier.ExitStatusC():
+			return n.workErrNotifier.Err()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
 	r := n.replicas.replica(source)
 
 	e, err := r.Step(msg)
@@ -220,6 +275,12 @@ func (n *Node) Step(ctx context.Context, source uint64, msg *msgs.Msg) error {
 	}
 }
 
+// VerificationFailures returns the number of messages received from each
+// source which failed verification and were dropped.
+func (n *Node) VerificationFailures() map[uint64]uint64 {
+	return n.replicas.verificationFailures()
+}
+
 func (n *Node) Client(id uint64) *Client {
 	return &Client{
 		client:          n.clients.Client(id),
@@ -236,7 +297,16 @@ func (n *Node) doWALWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	walResults, err := processor.ProcessWALActions(n.processorConfig.WAL, actions)
+	wal := n.processorConfig.WAL
+	if n.processorConfig.Metrics != nil {
+		processor.WALActionsMetrics(n.processorConfig.Metrics, actions)
+		wal = &processor.TimedWAL{
+			WAL:     wal,
+			Metrics: n.processorConfig.Metrics,
+		}
+	}
+
+	walResults, err := processor.ProcessWALActions(wal, actions)
 	if err != nil {
 		return errors.WithMessage(err, "could not perform WAL actions")
 	}
@@ -279,6 +349,23 @@ func (n *Node) doClientWork(exitC <-chan struct{}) error {
 	return nil
 }
 
+// hashJob is a contiguous portion of a hash action list, handed to one
+// of the hash workers.  The results are sent on resultC, which must be
+// buffered so that the worker never blocks.
+type hashJob struct {
+	actions *statemachine.ActionList
+	resultC chan hashJobResult
+}
+
+type hashJobResult struct {
+	events *statemachine.EventList
+	err    error
+}
+
+// doHashWork splits the hash actions across the hash workers, then
+// reassembles the results in the order of the original actions.  This way,
+// the events produced are identical to those from hashing serially, and
+// so the event log does not depend on the number of hash workers.
 func (n *Node) doHashWork(exitC <-chan struct{}) error {
 	var actions *statemachine.ActionList
 	select {
@@ -287,9 +374,42 @@ func (n *Node) doHashWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	hashResults, err := processor.ProcessHashActions(n.processorConfig.Hasher, actions)
-	if err != nil {
-		return errors.WithMessage(err, "could not perform hash actions")
+	var hashResults *statemachine.EventList
+	if workers := n.hashWorkers(); workers == 1 || actions.Len() == 1 {
+		var err error
+		hashResults, err = processor.ProcessHashActions(n.processorConfig.Hasher, actions)
+		if err != nil {
+			return errors.WithMessage(err, "could not perform hash actions")
+		}
+	} else {
+		jobs := splitActions(actions, workers)
+		for _, job := range jobs {
+			select {
+			case n.hashJobsC <- job:
+			case <-exitC:
+				return ErrStopped
+			}
+		}
+
+		hashResults = &statemachine.EventList{}
+		for _, job := range jobs {
+			var result hashJobResult
+			select {
+			case result = <-job.resultC:
+			case <-exitC:
+				return ErrStopped
+			}
+
+			if result.err != nil {
+				return errors.WithMessage(result.err, "could not perform hash actions")
+			}
+
+			hashResults.PushBackList(result.events)
+		}
+	}
+
+	if n.processorConfig.Metrics != nil {
+		processor.HashActionsMetrics(n.processorConfig.Metrics, actions)
 	}
 
 	select {
@@ -301,6 +421,56 @@ func (n *Node) doHashWork(exitC <-chan struct{}) error {
 	return nil
 }
 
+func (n *Node) doHashWorkerWork(exitC <-chan struct{}) error {
+	var job *hashJob
+	select {
+	case job = <-n.hashJobsC:
+	case <-exitC:
+		return ErrStopped
+	}
+
+	events, err := processor.ProcessHashActions(n.processorConfig.Hasher, job.actions)
+	job.resultC <- hashJobResult{
+		events: events,
+		err:    err,
+	}
+
+	return nil
+}
+
+func (n *Node) hashWorkers() int {
+	if n.processorConfig.HashWorkers <= 0 {
+		return runtime.NumCPU()
+	}
+	return n.processorConfig.HashWorkers
+}
+
+// splitActions partitions the actions into at most count jobs
+// of contiguous actions, of as near to equal length as possible.
+func splitActions(actions *statemachine.ActionList, count int) []*hashJob {
+	if count > actions.Len() {
+		count = actions.Len()
+	}
+
+	jobs := make([]*hashJob, count)
+	iter := actions.Iterator()
+	remaining := actions.Len()
+	for i := range jobs {
+		jobs[i] = &hashJob{
+			actions: &statemachine.ActionList{},
+			resultC: make(chan hashJobResult, 1),
+		}
+
+		jobLen := remaining / (count - i)
+		for j := 0; j < jobLen; j++ {
+			jobs[i].actions.PushBack(iter.Next())
+		}
+		remaining -= jobLen
+	}
+
+	return jobs
+}
+
 func (n *Node) doNetWork(exitC <-chan struct{}) error {
 	var actions *statemachine.ActionList
 	select {
@@ -309,7 +479,7 @@ func (n *Node) doNetWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	netResults, err := processor.ProcessNetActions(n.ID, n.processorConfig.Link, actions)
+	netResults, err := processor.ProcessNetActions(n.ID, n.processorConfig.Link, n.processorConfig.RequestStore, n.processorConfig.Signer, actions)
 	if err != nil {
 		return errors.WithMessage(err, "could not perform net actions")
 	}
@@ -323,6 +493,37 @@ func (n *Node) doNetWork(exitC <-chan struct{}) error {
 	return nil
 }
 
+func (n *Node) doVerifyWork(verifyC <-chan *stepRequest) workFunc {
+	return func(exitC <-chan struct{}) error {
+		var req *stepRequest
+		select {
+		case req = <-verifyC:
+		case <-exitC:
+			return ErrStopped
+		}
+
+		events, err := n.replicas.replica(req.source).Step(req.msg)
+		if err != nil {
+			// The caller of Step has already returned, so there is no one
+			// to return this error to, and a malformed message from a peer
+			// is no reason to halt.
+			n.Config.Logger.Log(LevelWarn, "dropping invalid message", "source", req.source, "error", err)
+			if n.processorConfig.Metrics != nil {
+				n.processorConfig.Metrics.MsgDropped(req.source)
+			}
+			return nil
+		}
+
+		select {
+		case n.replicas.eventC <- events:
+		case <-exitC:
+			return ErrStopped
+		}
+
+		return nil
+	}
+}
+
 func (n *Node) doAppWork(exitC <-chan struct{}) error {
 	var actions *statemachine.ActionList
 	select {
@@ -331,11 +532,15 @@ func (n *Node) doAppWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	appResults, err := processor.ProcessAppActions(n.processorConfig.App, actions)
+	appResults, err := processor.ProcessAppActions(n.processorConfig.App, n.processorConfig.StateTransfer, actions)
 	if err != nil {
 		return errors.WithMessage(err, "could not perform app actions")
 	}
 
+	if n.processorConfig.Metrics != nil {
+		processor.AppActionsMetrics(n.processorConfig.Metrics, actions)
+	}
+
 	select {
 	case n.appResultsC <- appResults:
 	case <-exitC:
@@ -387,6 +592,20 @@ func (n *Node) doStateMachineWork(exitC <-chan struct{}) (err error) {
 		return err
 	}
 
+	if n.processorConfig.OddityReporter != nil {
+		processor.ReportOddities(n.processorConfig.OddityReporter, actions)
+	}
+
+	if n.processorConfig.Metrics != nil && containsTick(events) {
+		// Sampling the status is too expensive to do for every batch
+		// of events, so the status derived metrics are updated per tick.
+		s, err := n.stateMachine.Status()
+		if err != nil {
+			return err
+		}
+		processor.StatusMetrics(n.processorConfig.Metrics, s)
+	}
+
 	if actions.Len() == 0 {
 		return nil
 	}
@@ -401,6 +620,16 @@ func (n *Node) doStateMachineWork(exitC <-chan struct{}) (err error) {
 	return nil
 }
 
+func containsTick(events *statemachine.EventList) bool {
+	iter := events.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		if _, ok := event.Type.(*state.Event_TickElapsed); ok {
+			return true
+		}
+	}
+	return false
+}
+
 type workFunc func(exitC <-chan struct{}) error
 
 func (n *Node) doUntilErr(work workFunc) {
@@ -420,16 +649,54 @@ type ProcessorConfig struct {
 	WAL          processor.WAL
 	RequestStore processor.RequestStore
 	Interceptor  processor.EventInterceptor
+
+	// StateTransfer, if set, fetches checkpoint data from peers during
+	// state transfer, rather than leaving this to App.TransferTo.
+	StateTransfer *processor.StateTransfer
+
+	// Signer, if set, is used to sign all outgoing messages.
+	Signer processor.Signer
+
+	// Verifier, if set, is used to authenticate all incoming messages.
+	// Messages which fail verification are counted and dropped.
+	Verifier processor.Verifier
+
+	// RequestValidator, if set, must accept each request, whether proposed
+	// locally or forwarded by a peer, before it is acknowledged.
+	RequestValidator processor.RequestValidator
+
+	// ClientRateLimit, if set, bounds the rate at which each client may
+	// propose request data through this node.  Proposals beyond the limit
+	// fail with a *processor.RateLimitedError.
+	ClientRateLimit *processor.RateLimit
+
+	// Metrics, if set, is updated with measurements of the work queues,
+	// WAL, hashing, commits, epochs, checkpoints and peer messages.
+	Metrics processor.Metrics
+
+	// OddityReporter, if set, is notified of each message from a peer
+	// which was discarded for violating the protocol, such as a duplicate
+	// prepare, or a NewEpoch message not from the epoch primary.
+	OddityReporter processor.OddityReporter
+
+	// HashWorkers is the number of go routines across which hash actions
+	// are split and computed concurrently, it defaults to runtime.NumCPU().
+	HashWorkers int
 }
 
 func (n *Node) runtimeParms() *state.EventInitialParameters {
 	return &state.EventInitialParameters{
-		Id:                   n.ID,
-		BatchSize:            n.Config.BatchSize,
-		HeartbeatTicks:       n.Config.HeartbeatTicks,
-		SuspectTicks:         n.Config.SuspectTicks,
-		NewEpochTimeoutTicks: n.Config.NewEpochTimeoutTicks,
-		BufferSize:           n.Config.BufferSize,
+		Id:                     n.ID,
+		BatchSize:              n.Config.BatchSize,
+		BatchSizeBytes:         n.Config.BatchSizeBytes,
+		MaxBatchLatencyTicks:   n.Config.MaxBatchLatencyTicks,
+		HeartbeatTicks:         n.Config.HeartbeatTicks,
+		SuspectTicks:           n.Config.SuspectTicks,
+		NewEpochTimeoutTicks:   n.Config.NewEpochTimeoutTicks,
+		BufferSize:             n.Config.BufferSize,
+		FetchTimeoutTicks:      n.Config.FetchTimeoutTicks,
+		AckResendTicks:         n.Config.AckResendTicks,
+		OutOfCorrectEpochTicks: n.Config.OutOfCorrectEpochTicks,
 	}
 }
 
@@ -449,6 +716,27 @@ func (n *Node) ProcessAsNewNode(
 	return n.process(exitC, tickC)
 }
 
+// ProcessAsJoiningNode starts a node which has been added to a running
+// network.  The stable checkpoint at seqNo must be one whose network state
+// includes this node, its state is transferred from the other nodes before
+// the node participates.
+func (n *Node) ProcessAsJoiningNode(
+	exitC <-chan struct{},
+	tickC <-chan time.Time,
+	seqNo uint64,
+	networkState *msgs.NetworkState,
+	checkpointValue []byte,
+) error {
+	events, err := processor.InitializeWALForJoiningNode(n.processorConfig.WAL, n.runtimeParms(), seqNo, networkState, checkpointValue)
+	if err != nil {
+		n.workErrNotifier.SetExitStatus(nil, errors.Errorf("state machine was not started"))
+		return err
+	}
+
+	n.workItems.ResultEvents().PushBackList(events)
+	return n.process(exitC, tickC)
+}
+
 func (n *Node) RestartProcessing(
 	exitC <-chan struct{},
 	tickC <-chan time.Time,
@@ -463,16 +751,28 @@ func (n *Node) RestartProcessing(
 	return n.process(exitC, tickC)
 }
 func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
-	var wg sync.WaitGroup
-	for _, work := range []workFunc{
+	workFuncs := []workFunc{
 		n.doWALWork,
 		n.doClientWork,
-		n.doHashWork, // TODO, spawn more of these
+		n.doHashWork,
 		n.doNetWork,
 		n.doAppWork,
 		n.doReqStoreWork,
 		n.doStateMachineWork,
-	} {
+	}
+
+	for _, verifyC := range n.replicas.verifyCs {
+		workFuncs = append(workFuncs, n.doVerifyWork(verifyC))
+	}
+
+	if workers := n.hashWorkers(); workers > 1 {
+		for i := 0; i < workers; i++ {
+			workFuncs = append(workFuncs, n.doHashWorkerWork)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, work := range workFuncs {
 		wg.Add(1)
 		go func(work workFunc) {
 			wg.Done()
@@ -522,14 +822,17 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 		case actions := <-n.resultResultsC:
 			n.workItems.AddStateMachineResults(actions)
 		case stepEvents := <-n.replicas.eventC:
-			// TODO, once request forwarding works, we'll
-			// need to split this into the req store component
-			// and 'other' that goes into the result events.
-			n.workItems.ResultEvents().PushBackList(stepEvents)
+			n.workItems.AddStepResults(stepEvents)
+			if n.processorConfig.StateTransfer != nil {
+				n.workItems.AppActions().PushBackList(n.processorConfig.StateTransfer.Fetched())
+			}
 		case <-n.workErrNotifier.ExitC():
 			return n.workErrNotifier.Err()
 		case <-tickC:
 			n.workItems.ResultEvents().TickElapsed()
+			if n.processorConfig.StateTransfer != nil {
+				n.workItems.ResultEvents().PushBackList(n.processorConfig.StateTransfer.Tick())
+			}
 		case <-exitC:
 			n.workErrNotifier.Fail(ErrStopped)
 		}
@@ -561,6 +864,10 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 		if reqStoreEventsC == nil && n.workItems.ReqStoreEvents().Len() > 0 {
 			reqStoreEventsC = n.reqStoreEventsC
 		}
+
+		if n.processorConfig.Metrics != nil {
+			processor.WorkItemsMetrics(n.processorConfig.Metrics, n.workItems)
+		}
 	}
 }
 
@@ -9,6 +9,8 @@ package mirbft_test
 import (
 	"context"
 	"crypto"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
 	"io/ioutil"
@@ -77,6 +79,27 @@ func (fl *FakeLink) Send(dest uint64, msg *msgs.Msg) {
 	fl.FakeTransport.Send(fl.Source, dest, msg)
 }
 
+// FakeAuth signs and verifies messages using an HMAC keyed
+// by the node ID, which is sufficient to exercise authentication.
+type FakeAuth uint64
+
+func fakeAuthMAC(nodeID uint64, data []byte) []byte {
+	mac := hmac.New(sha256.New, Uint64ToBytes(nodeID))
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func (fa FakeAuth) Sign(data []byte) ([]byte, error) {
+	return fakeAuthMAC(uint64(fa), data), nil
+}
+
+func (fa FakeAuth) Verify(source uint64, data, signature []byte) error {
+	if !hmac.Equal(fakeAuthMAC(source, data), signature) {
+		return fmt.Errorf("invalid signature from node %d", source)
+	}
+	return nil
+}
+
 type FakeTransport struct {
 	// Buffers is source x dest
 	Buffers   [][]chan *msgs.Msg
@@ -194,6 +217,8 @@ type TestConfig struct {
 	BatchSize          uint32
 	ClientWidth        uint32
 	ParallelProcess    bool
+	SignMessages       bool
+	HashWorkers        int
 }
 
 func Uint64ToBytes(value uint64) []byte {
@@ -307,6 +332,20 @@ var _ = Describe("StressyTest", func() {
 			MsgCount:           1000,
 		}),
 
+		Entry("FourNodeBFT signed messages greenpath", &TestConfig{
+			NodeCount:          4,
+			CheckpointInterval: 20,
+			MsgCount:           1000,
+			SignMessages:       true,
+		}),
+
+		Entry("FourNodeBFT parallel hashing greenpath", &TestConfig{
+			NodeCount:          4,
+			CheckpointInterval: 20,
+			MsgCount:           1000,
+			HashWorkers:        4,
+		}),
+
 		Entry("FourNodeBFT single bucket greenpath", &TestConfig{
 			NodeCount:          4,
 			BucketCount:        1,
@@ -326,6 +365,37 @@ var _ = Describe("StressyTest", func() {
 	)
 })
 
+// RejectingValidator rejects every request.
+type RejectingValidator struct{}
+
+func (RejectingValidator) ValidateRequest(clientID, reqNo uint64, data []byte) error {
+	return fmt.Errorf("request from client %d is not signed", clientID)
+}
+
+var _ = Describe("Node", func() {
+	It("returns requests rejected by the validator to the proposer", func() {
+		node, err := mirbft.NewNode(
+			0,
+			&mirbft.Config{
+				BatchSize:            1,
+				SuspectTicks:         4,
+				HeartbeatTicks:       2,
+				NewEpochTimeoutTicks: 8,
+				BufferSize:           5 * 1024 * 1024,
+				Logger:               mirbft.ConsoleWarnLogger,
+			},
+			&mirbft.ProcessorConfig{
+				Hasher:           crypto.SHA256,
+				RequestValidator: RejectingValidator{},
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = node.Client(1).Propose(context.Background(), 0, clientReq(1, 0))
+		Expect(err).To(MatchError("request for client_id=1 req_no=0 failed validation: request from client 1 is not signed"))
+	})
+})
+
 type TestReplica struct {
 	ID                  uint64
 	Config              *mirbft.Config
@@ -335,6 +405,8 @@ type TestReplica struct {
 	FakeTransport       *FakeTransport
 	FakeClient          *FakeClient
 	ParallelProcess     bool
+	SignMessages        bool
+	HashWorkers         int
 	DoneC               <-chan struct{}
 }
 
@@ -384,17 +456,25 @@ func (tr *TestReplica) Run() (*status.StateMachine, error) {
 	var wg sync.WaitGroup
 	defer wg.Wait()
 
+	processorConfig := &mirbft.ProcessorConfig{
+		Link:         tr.FakeTransport.Link(tr.ID),
+		Hasher:       crypto.SHA256,
+		RequestStore: reqStore,
+		App:          tr.App,
+		WAL:          wal,
+		Interceptor:  interceptor,
+		HashWorkers:  tr.HashWorkers,
+	}
+
+	if tr.SignMessages {
+		processorConfig.Signer = FakeAuth(tr.ID)
+		processorConfig.Verifier = FakeAuth(tr.ID)
+	}
+
 	node, err := mirbft.NewNode(
 		tr.ID,
 		tr.Config,
-		&mirbft.ProcessorConfig{
-			Link:         tr.FakeTransport.Link(tr.ID),
-			Hasher:       crypto.SHA256,
-			RequestStore: reqStore,
-			App:          tr.App,
-			WAL:          wal,
-			Interceptor:  interceptor,
-		},
+		processorConfig,
 	)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -507,6 +587,8 @@ func CreateNetwork(testConfig *TestConfig, doneC <-chan struct{}) *Network {
 				MsgCount: uint64(testConfig.MsgCount),
 			},
 			ParallelProcess: testConfig.ParallelProcess,
+			SignMessages:    testConfig.SignMessages,
+			HashWorkers:     testConfig.HashWorkers,
 			DoneC:           doneC,
 		}
 	}
@@ -0,0 +1,419 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package client is a library for submitting requests to a network of replicas and
+// learning when they commit.  Each request is proposed to several replicas, so that
+// it is accepted even if some replicas are faulty, and the returned Future resolves
+// once the request is observed in a committed QEntry.  If the request does not commit
+// in time, it is proposed again, to every replica.
+package client
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+)
+
+// ErrTimeout is returned by a Future whose request did not commit
+// within the configured number of attempts.
+var ErrTimeout = errors.New("request did not commit in time")
+
+const (
+	defaultTimeout     = 5 * time.Second
+	defaultMaxAttempts = 3
+)
+
+// Proposer submits requests to a single replica, it is implemented
+// by *mirbft.Client.
+type Proposer interface {
+	NextReqNo() (uint64, error)
+	Propose(ctx context.Context, reqNo uint64, data []byte) error
+}
+
+// CommitObserver is notified of every QEntry committed by a replica.
+type CommitObserver interface {
+	Committed(source uint64, qEntry *msgs.QEntry)
+}
+
+// CommitObservers notifies each of its members in turn.
+type CommitObservers []CommitObserver
+
+func (cos CommitObservers) Committed(source uint64, qEntry *msgs.QEntry) {
+	for _, co := range cos {
+		co.Committed(source, qEntry)
+	}
+}
+
+type observingApp struct {
+	processor.App
+	source   uint64
+	observer CommitObserver
+}
+
+func (oa *observingApp) Apply(qEntry *msgs.QEntry) error {
+	oa.observer.Committed(oa.source, qEntry)
+	return oa.App.Apply(qEntry)
+}
+
+// ObserveCommits wraps the app of the given replica, so that each
+// committed QEntry is reported to the observer before it is applied.
+func ObserveCommits(source uint64, app processor.App, observer CommitObserver) processor.App {
+	return &observingApp{
+		App:      app,
+		source:   source,
+		observer: observer,
+	}
+}
+
+type Config struct {
+	ClientID uint64
+
+	// Replicas are the replicas to which requests may be proposed, by node ID.
+	Replicas map[uint64]Proposer
+
+	// Targets is the number of replicas to which each request is first proposed,
+	// typically f+1, so that at least one correct replica receives it.  The targets
+	// are rotated between requests to spread the load.  If zero, requests are
+	// always proposed to every replica.
+	Targets int
+
+	// Timeout is the time to wait for a request to commit before proposing it
+	// again to every replica, it defaults to 5 seconds.
+	Timeout time.Duration
+
+	// MaxAttempts is the number of times a request is proposed before its
+	// Future fails with ErrTimeout, it defaults to 3.
+	MaxAttempts int
+
+	// RequiredCommits is the number of distinct replicas which must report
+	// the request committed with the same digest before its Future resolves,
+	// it defaults to 1.  When commits are reported by untrusted replicas, this
+	// should be f+1.
+	RequiredCommits int
+
+	// Hasher, if set, is used to check that the committed request has the
+	// digest of the submitted data.
+	Hasher processor.Hasher
+}
+
+type Client struct {
+	config *Config
+
+	mutex     sync.Mutex
+	nextReqNo *uint64
+	pending   map[uint64]*Future
+}
+
+func New(config *Config) *Client {
+	return &Client{
+		config:  config,
+		pending: map[uint64]*Future{},
+	}
+}
+
+// Future tracks a submitted request until it commits or fails.
+type Future struct {
+	ReqNo uint64
+
+	mutex   *sync.Mutex // the mutex of the client
+	digest  []byte
+	doneC   chan struct{}
+	err     error
+	acks    map[uint64]struct{}
+	commits map[uint64][]byte // the digest each replica committed
+}
+
+// Done returns a channel which is closed once the request
+// has committed or failed.
+func (f *Future) Done() <-chan struct{} {
+	return f.doneC
+}
+
+// Err returns the reason the request failed, it is nil if the request
+// committed, or is not yet done.
+func (f *Future) Err() error {
+	select {
+	case <-f.doneC:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Acks returns the IDs of the replicas which have accepted
+// a proposal of the request.
+func (f *Future) Acks() []uint64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	acks := make([]uint64, 0, len(f.acks))
+	for id := range f.acks {
+		acks = append(acks, id)
+	}
+	sort.Slice(acks, func(i, j int) bool {
+		return acks[i] < acks[j]
+	})
+	return acks
+}
+
+// Wait blocks until the request is done, returning its error, or until
+// the context ends, returning the context's error.
+func (f *Future) Wait(ctx context.Context) error {
+	select {
+	case <-f.doneC:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Submit assigns the next request number to the data, and proposes it
+// to the replicas in the background.  Cancelling the context abandons
+// the request, failing the returned Future.
+func (c *Client) Submit(ctx context.Context, data []byte) (*Future, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.nextReqNo == nil {
+		reqNo, err := c.initialReqNo()
+		if err != nil {
+			return nil, err
+		}
+		c.nextReqNo = &reqNo
+	}
+
+	f := &Future{
+		ReqNo:   *c.nextReqNo,
+		mutex:   &c.mutex,
+		doneC:   make(chan struct{}),
+		acks:    map[uint64]struct{}{},
+		commits: map[uint64][]byte{},
+	}
+	*c.nextReqNo++
+
+	if c.config.Hasher != nil {
+		h := c.config.Hasher.New()
+		h.Write(data)
+		f.digest = h.Sum(nil)
+	}
+
+	c.pending[f.ReqNo] = f
+
+	go c.propose(ctx, f, data)
+
+	return f, nil
+}
+
+// initialReqNo must be called with the mutex held.  Replicas may lag behind
+// one another, so the highest next request number among them is used.
+func (c *Client) initialReqNo() (uint64, error) {
+	var highest uint64
+	var lastErr error
+	succeeded := false
+	for _, id := range c.replicaIDs() {
+		reqNo, err := c.config.Replicas[id].NextReqNo()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		succeeded = true
+		if reqNo > highest {
+			highest = reqNo
+		}
+	}
+
+	if !succeeded {
+		return 0, errors.WithMessage(lastErr, "no replica could supply the next request number")
+	}
+
+	return highest, nil
+}
+
+func (c *Client) replicaIDs() []uint64 {
+	ids := make([]uint64, 0, len(c.config.Replicas))
+	for id := range c.config.Replicas {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// targets returns the replicas to propose to on the given attempt.
+func (c *Client) targets(reqNo uint64, attempt int) []uint64 {
+	ids := c.replicaIDs()
+	if attempt > 0 || c.config.Targets <= 0 || c.config.Targets >= len(ids) {
+		return ids
+	}
+
+	targets := make([]uint64, c.config.Targets)
+	for i := range targets {
+		targets[i] = ids[(int(reqNo%uint64(len(ids)))+i)%len(ids)]
+	}
+	return targets
+}
+
+func (c *Client) propose(ctx context.Context, f *Future, data []byte) {
+	timeout := c.config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	maxAttempts := c.config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+
+		for _, id := range c.targets(f.ReqNo, attempt) {
+			if err := c.config.Replicas[id].Propose(attemptCtx, f.ReqNo, data); err != nil {
+				lastErr = errors.WithMessagef(err, "replica %d rejected request", id)
+				continue
+			}
+
+			c.mutex.Lock()
+			f.acks[id] = struct{}{}
+			c.mutex.Unlock()
+		}
+
+		select {
+		case <-f.doneC:
+			cancel()
+			return
+		case <-attemptCtx.Done():
+			cancel()
+		}
+
+		if ctx.Err() != nil {
+			c.resolve(f, ctx.Err())
+			return
+		}
+	}
+
+	if lastErr != nil {
+		c.resolve(f, errors.WithMessage(ErrTimeout, lastErr.Error()))
+		return
+	}
+
+	c.resolve(f, ErrTimeout)
+}
+
+// resolve completes the future, if it is still pending.
+func (c *Client) resolve(f *Future, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.pending[f.ReqNo] != f {
+		return
+	}
+
+	delete(c.pending, f.ReqNo)
+	f.err = err
+	close(f.doneC)
+}
+
+// Committed resolves the futures of any of this client's requests
+// in the committed QEntry.  Only the first commit reported by each
+// replica for a request is considered.
+func (c *Client) Committed(source uint64, qEntry *msgs.QEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, ack := range qEntry.Requests {
+		if ack.ClientId != c.config.ClientID {
+			continue
+		}
+
+		f, ok := c.pending[ack.ReqNo]
+		if !ok {
+			continue
+		}
+
+		if _, ok := f.commits[source]; ok {
+			continue
+		}
+		f.commits[source] = ack.Digest
+
+		done, err := c.checkCommits(f)
+		if !done {
+			continue
+		}
+
+		delete(c.pending, f.ReqNo)
+		f.err = err
+		close(f.doneC)
+	}
+}
+
+// checkCommits must be called with the mutex held.  It returns whether the
+// future is done, and if so, whether it failed.  The request has committed
+// once enough replicas report the same digest.  If the submitted data is
+// known, the future fails if that digest is not the submitted one, or if
+// too few replicas remain to report the submitted digest.  Otherwise, it
+// fails only once too few replicas remain for any digest to be reported
+// enough times.
+func (c *Client) checkCommits(f *Future) (bool, error) {
+	requiredCommits := c.config.RequiredCommits
+	if requiredCommits <= 0 {
+		requiredCommits = 1
+	}
+
+	remaining := 0
+	for id := range c.config.Replicas {
+		if _, ok := f.commits[id]; !ok {
+			remaining++
+		}
+	}
+
+	counts := map[string]int{}
+	for _, digest := range f.commits {
+		counts[string(digest)]++
+	}
+
+	// other is the most reported digest other than the submitted one.
+	var other []byte
+	otherCount := 0
+	for _, digest := range f.commits {
+		if f.digest != nil && bytes.Equal(digest, f.digest) {
+			continue
+		}
+		if counts[string(digest)] > otherCount {
+			other, otherCount = digest, counts[string(digest)]
+		}
+	}
+
+	if f.digest == nil {
+		switch {
+		case otherCount >= requiredCommits:
+			return true, nil
+		case otherCount+remaining < requiredCommits:
+			return true, errors.Errorf("request committed with conflicting digests, none reported by %d replicas", requiredCommits)
+		default:
+			return false, nil
+		}
+	}
+
+	switch {
+	case counts[string(f.digest)] >= requiredCommits:
+		return true, nil
+	case otherCount >= requiredCommits, counts[string(f.digest)]+remaining < requiredCommits:
+		return true, errors.Errorf("request committed with digest %x, but submitted data has digest %x", other, f.digest)
+	default:
+		return false, nil
+	}
+}
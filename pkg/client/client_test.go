@@ -0,0 +1,202 @@
+package client_test
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/client"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+)
+
+type fakeProposer struct {
+	mutex     sync.Mutex
+	nextReqNo uint64
+	proposals []uint64
+	err       error
+}
+
+func (fp *fakeProposer) NextReqNo() (uint64, error) {
+	return fp.nextReqNo, nil
+}
+
+func (fp *fakeProposer) Propose(ctx context.Context, reqNo uint64, data []byte) error {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+	fp.proposals = append(fp.proposals, reqNo)
+	return fp.err
+}
+
+func (fp *fakeProposer) Proposals() []uint64 {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+	return append([]uint64{}, fp.proposals...)
+}
+
+func digest(data []byte) []byte {
+	h := crypto.SHA256.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func qEntry(clientID, reqNo uint64, digest []byte) *msgs.QEntry {
+	return &msgs.QEntry{
+		Requests: []*msgs.RequestAck{
+			{
+				ClientId: clientID,
+				ReqNo:    reqNo,
+				Digest:   digest,
+			},
+		},
+	}
+}
+
+var _ = Describe("Client", func() {
+	var (
+		proposers []*fakeProposer
+		config    *client.Config
+		c         *client.Client
+		ctx       context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		proposers = []*fakeProposer{
+			{nextReqNo: 3},
+			{nextReqNo: 5},
+			{nextReqNo: 4},
+			{nextReqNo: 5},
+		}
+
+		config = &client.Config{
+			ClientID:    7,
+			Replicas:    map[uint64]client.Proposer{},
+			Targets:     2,
+			Timeout:     50 * time.Millisecond,
+			MaxAttempts: 2,
+			Hasher:      crypto.SHA256,
+		}
+		for i, p := range proposers {
+			config.Replicas[uint64(i)] = p
+		}
+	})
+
+	JustBeforeEach(func() {
+		c = client.New(config)
+	})
+
+	It("proposes to the targets and resolves on commit", func() {
+		f, err := c.Submit(ctx, []byte("data"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.ReqNo).To(Equal(uint64(5)))
+
+		// Request 5 is sent first to replicas 1 and 2.
+		Eventually(f.Acks).Should(Equal([]uint64{1, 2}))
+		Expect(proposers[0].Proposals()).To(BeEmpty())
+		Expect(proposers[3].Proposals()).To(BeEmpty())
+
+		c.Committed(2, qEntry(8, 5, digest([]byte("data"))))
+		Consistently(f.Done(), 10*time.Millisecond).ShouldNot(BeClosed())
+
+		c.Committed(2, qEntry(7, 5, digest([]byte("data"))))
+		Eventually(f.Done()).Should(BeClosed())
+		Expect(f.Err()).NotTo(HaveOccurred())
+
+		f, err = c.Submit(ctx, []byte("more data"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.ReqNo).To(Equal(uint64(6)))
+	})
+
+	It("proposes again to every replica when the request does not commit", func() {
+		f, err := c.Submit(ctx, []byte("data"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(f.Acks).Should(Equal([]uint64{0, 1, 2, 3}))
+		Eventually(f.Done()).Should(BeClosed())
+		Expect(f.Err()).To(Equal(client.ErrTimeout))
+		Expect(proposers[1].Proposals()).To(Equal([]uint64{5, 5}))
+		Expect(proposers[0].Proposals()).To(Equal([]uint64{5}))
+	})
+
+	It("fails requests committed with a different digest", func() {
+		f, err := c.Submit(ctx, []byte("data"))
+		Expect(err).NotTo(HaveOccurred())
+
+		c.Committed(0, qEntry(7, 5, digest([]byte("other data"))))
+		Eventually(f.Done()).Should(BeClosed())
+		Expect(f.Err()).To(MatchError(ContainSubstring("request committed with digest")))
+	})
+
+	It("fails requests when the context is cancelled", func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		f, err := c.Submit(ctx, []byte("data"))
+		Expect(err).NotTo(HaveOccurred())
+
+		cancel()
+		Expect(f.Wait(context.Background())).To(Equal(context.Canceled))
+	})
+
+	When("multiple commits are required", func() {
+		BeforeEach(func() {
+			config.RequiredCommits = 2
+			config.Timeout = time.Second
+		})
+
+		It("waits for commits from distinct replicas", func() {
+			f, err := c.Submit(ctx, []byte("data"))
+			Expect(err).NotTo(HaveOccurred())
+
+			c.Committed(1, qEntry(7, 5, digest([]byte("data"))))
+			c.Committed(1, qEntry(7, 5, digest([]byte("data"))))
+			Consistently(f.Done(), 10*time.Millisecond).ShouldNot(BeClosed())
+
+			c.Committed(3, qEntry(7, 5, digest([]byte("data"))))
+			Eventually(f.Done()).Should(BeClosed())
+			Expect(f.Err()).NotTo(HaveOccurred())
+		})
+
+		It("tolerates a replica reporting a different digest", func() {
+			f, err := c.Submit(ctx, []byte("data"))
+			Expect(err).NotTo(HaveOccurred())
+
+			c.Committed(0, qEntry(7, 5, digest([]byte("other data"))))
+			c.Committed(1, qEntry(7, 5, digest([]byte("data"))))
+			Consistently(f.Done(), 10*time.Millisecond).ShouldNot(BeClosed())
+
+			c.Committed(2, qEntry(7, 5, digest([]byte("data"))))
+			Eventually(f.Done()).Should(BeClosed())
+			Expect(f.Err()).NotTo(HaveOccurred())
+		})
+
+		It("fails once too few replicas remain to report the submitted digest", func() {
+			f, err := c.Submit(ctx, []byte("data"))
+			Expect(err).NotTo(HaveOccurred())
+
+			c.Committed(0, qEntry(7, 5, digest([]byte("other data"))))
+			c.Committed(1, qEntry(7, 5, digest([]byte("more data"))))
+			Consistently(f.Done(), 10*time.Millisecond).ShouldNot(BeClosed())
+
+			c.Committed(2, qEntry(7, 5, digest([]byte("yet more data"))))
+			Eventually(f.Done()).Should(BeClosed())
+			Expect(f.Err()).To(MatchError(ContainSubstring("request committed with digest")))
+		})
+
+		It("fails once enough replicas report a different digest", func() {
+			f, err := c.Submit(ctx, []byte("data"))
+			Expect(err).NotTo(HaveOccurred())
+
+			c.Committed(0, qEntry(7, 5, digest([]byte("other data"))))
+			Consistently(f.Done(), 10*time.Millisecond).ShouldNot(BeClosed())
+
+			c.Committed(1, qEntry(7, 5, digest([]byte("other data"))))
+			Eventually(f.Done()).Should(BeClosed())
+			Expect(f.Err()).To(MatchError(fmt.Sprintf("request committed with digest %x, but submitted data has digest %x", digest([]byte("other data")), digest([]byte("data")))))
+		})
+	})
+})
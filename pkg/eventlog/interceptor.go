@@ -19,6 +19,7 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
 )
@@ -37,7 +38,15 @@ func TimeSourceOpt(source func() int64) RecorderOpt {
 	return timeSourceOpt(source)
 }
 
-type retainRequestDataOpt struct{}
+// RequestStore is the subset of the processor's request store which
+// the recorder uses to look up request payloads.
+type RequestStore interface {
+	GetRequest(requestAck *msgs.RequestAck) ([]byte, error)
+}
+
+type retainRequestDataOpt struct {
+	requestStore RequestStore
+}
 
 // RetainRequestDataOpt indicates that the full request data should be
 // embedded into the logs.  Usually, this option is undesirable since although
@@ -45,10 +54,40 @@ type retainRequestDataOpt struct{}
 // increases the size of the log substantially and the request data
 // may be considered sensitive so is therefore unsuitable for
 // debug/service.  However, for debugging application code, sometimes,
-// having the complete logs is available, so this option may be set
-// to true.
-func RetainRequestDataOpt() RecorderOpt {
-	return retainRequestDataOpt{}
+// having the complete logs is available, so this option may be set.
+// The payloads are read from the supplied request store whenever a
+// request is persisted, forwarded in a batch, or preprepared for commit.
+// When this option is not set, request data carried by forwarded requests
+// is stripped before the event is written.
+func RetainRequestDataOpt(requestStore RequestStore) RecorderOpt {
+	return retainRequestDataOpt{
+		requestStore: requestStore,
+	}
+}
+
+// Redactor scrubs application sensitive bytes from recorded events.
+// The recorder invokes the redactor on a copy of each event, so
+// implementations may freely modify their arguments in place.
+type Redactor interface {
+	// RedactMsg is invoked for each message stepped into the
+	// state machine, before RedactEvent is invoked on the event.
+	RedactMsg(msg *msgs.Msg)
+
+	// RedactEvent is invoked for every event before it is written.
+	RedactEvent(event *state.Event)
+}
+
+type redactorOpt struct {
+	redactor Redactor
+}
+
+// RedactorOpt installs a redactor which is applied to every event
+// before it is written to the stream.  Note that request data embedded
+// because of RetainRequestDataOpt is not passed to the redactor.
+func RedactorOpt(redactor Redactor) RecorderOpt {
+	return redactorOpt{
+		redactor: redactor,
+	}
 }
 
 type compressionLevelOpt int
@@ -82,19 +121,34 @@ func BufferSizeOpt(size int) RecorderOpt {
 // mirbft.EventInterceptor interface.  It receives state events,
 // serializes them, compresses them, and writes them to a stream.
 type Recorder struct {
-	nodeID            uint64
-	timeSource        func() int64
-	compressionLevel  int
-	retainRequestData bool
-	eventC            chan eventTime
-	doneC             chan struct{}
-	exitC             chan struct{}
+	nodeID           uint64
+	timeSource       func() int64
+	compressionLevel int
+	requestStore     RequestStore
+	redactor         Redactor
+	eventC           chan eventTime
+	doneC            chan struct{}
+	exitC            chan struct{}
 
 	exitErr      error
 	exitErrMutex sync.Mutex
 }
 
 func NewRecorder(nodeID uint64, dest io.Writer, opts ...RecorderOpt) *Recorder {
+	i := newRecorder(nodeID, opts)
+
+	go i.run(func() (eventWriter, error) {
+		gzWriter, err := gzip.NewWriterLevel(dest, i.compressionLevel)
+		if err != nil {
+			return nil, err
+		}
+		return streamWriter{gzWriter: gzWriter}, nil
+	})
+
+	return i
+}
+
+func newRecorder(nodeID uint64, opts []RecorderOpt) *Recorder {
 	startTime := time.Now()
 
 	i := &Recorder{
@@ -113,7 +167,9 @@ func NewRecorder(nodeID uint64, dest io.Writer, opts ...RecorderOpt) *Recorder {
 		case timeSourceOpt:
 			i.timeSource = v
 		case retainRequestDataOpt:
-			i.retainRequestData = true
+			i.requestStore = v.requestStore
+		case redactorOpt:
+			i.redactor = v.redactor
 		case compressionLevelOpt:
 			i.compressionLevel = int(v)
 		case bufferSizeOpt:
@@ -121,11 +177,28 @@ func NewRecorder(nodeID uint64, dest io.Writer, opts ...RecorderOpt) *Recorder {
 		}
 	}
 
-	go i.run(dest)
-
 	return i
 }
 
+// eventWriter is the destination the recorder drains its buffer into.
+type eventWriter interface {
+	write(event *recording.Event) error
+	Close() error
+}
+
+// streamWriter writes all events into a single gzip stream.
+type streamWriter struct {
+	gzWriter *gzip.Writer
+}
+
+func (sw streamWriter) write(event *recording.Event) error {
+	return WriteRecordedEvent(sw.gzWriter, event)
+}
+
+func (sw streamWriter) Close() error {
+	return sw.gzWriter.Close()
+}
+
 type eventTime struct {
 	event *state.Event
 	time  int64
@@ -165,7 +238,7 @@ func (i *Recorder) Stop() error {
 
 var errStopped = fmt.Errorf("interceptor stopped at caller request")
 
-func (i *Recorder) run(dest io.Writer) (exitErr error) {
+func (i *Recorder) run(newWriter func() (eventWriter, error)) (exitErr error) {
 	defer func() {
 		i.exitErrMutex.Lock()
 		i.exitErr = exitErr
@@ -173,17 +246,23 @@ func (i *Recorder) run(dest io.Writer) (exitErr error) {
 		close(i.exitC)
 	}()
 
-	gzWriter, err := gzip.NewWriterLevel(dest, i.compressionLevel)
+	writer, err := newWriter()
 	if err != nil {
 		return err
 	}
-	defer gzWriter.Close()
+	defer writer.Close()
 
 	write := func(eventTime eventTime) error {
-		return WriteRecordedEvent(gzWriter, &recording.Event{
-			NodeId:     i.nodeID,
-			Time:       eventTime.time,
-			StateEvent: eventTime.event,
+		requestData, err := i.requestData(eventTime.event)
+		if err != nil {
+			return err
+		}
+
+		return writer.write(&recording.Event{
+			NodeId:      i.nodeID,
+			Time:        eventTime.time,
+			StateEvent:  i.redact(eventTime.event),
+			RequestData: requestData,
 		})
 	}
 
@@ -209,6 +288,83 @@ func (i *Recorder) run(dest io.Writer) (exitErr error) {
 	}
 }
 
+// requestData returns the payloads of the requests referenced by the
+// event, if the recorder is retaining request data.  Requests which are
+// not (or no longer) in the request store are skipped.
+func (i *Recorder) requestData(event *state.Event) ([]*recording.RequestData, error) {
+	if i.requestStore == nil {
+		return nil, nil
+	}
+
+	var acks []*msgs.RequestAck
+	switch et := event.Type.(type) {
+	case *state.Event_RequestPersisted:
+		acks = []*msgs.RequestAck{et.RequestPersisted.RequestAck}
+	case *state.Event_Step:
+		switch mt := et.Step.Msg.Type.(type) {
+		case *msgs.Msg_Preprepare:
+			acks = mt.Preprepare.Batch
+		case *msgs.Msg_ForwardBatch:
+			acks = mt.ForwardBatch.RequestAcks
+		}
+	}
+
+	var result []*recording.RequestData
+	for _, ack := range acks {
+		data, err := i.requestStore.GetRequest(ack)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not get request data for client_id=%d req_no=%d", ack.ClientId, ack.ReqNo)
+		}
+
+		if data == nil {
+			continue
+		}
+
+		result = append(result, &recording.RequestData{
+			RequestAck: ack,
+			Data:       data,
+		})
+	}
+
+	return result, nil
+}
+
+// redact returns the event as it should be written.  Unless request data is
+// retained, forwarded request payloads are stripped, and if a redactor is
+// configured, it is applied.  The original event is never modified, as it
+// is shared with the state machine.
+func (i *Recorder) redact(event *state.Event) *state.Event {
+	step, isStep := event.Type.(*state.Event_Step)
+	var forward *msgs.Msg_ForwardRequest
+	if isStep {
+		forward, _ = step.Step.Msg.Type.(*msgs.Msg_ForwardRequest)
+	}
+
+	stripRequestData := i.requestStore == nil && forward != nil && forward.ForwardRequest.RequestData != nil
+	if i.redactor == nil && !stripRequestData {
+		return event
+	}
+
+	event = proto.Clone(event).(*state.Event)
+
+	if isStep {
+		msg := event.Type.(*state.Event_Step).Step.Msg
+		if stripRequestData {
+			msg.Type.(*msgs.Msg_ForwardRequest).ForwardRequest.RequestData = nil
+		}
+
+		if i.redactor != nil {
+			i.redactor.RedactMsg(msg)
+		}
+	}
+
+	if i.redactor != nil {
+		i.redactor.RedactEvent(event)
+	}
+
+	return event
+}
+
 func WriteRecordedEvent(writer io.Writer, event *recording.Event) error {
 	return writeSizePrefixedProto(writer, event)
 }
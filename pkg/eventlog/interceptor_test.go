@@ -16,6 +16,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
 )
@@ -49,6 +50,144 @@ var _ = Describe("Recorder", func() {
 		Expect(output.Len()).To(Equal(46))
 	})
 
+	Describe("request data", func() {
+		var (
+			ack1, ack2         *msgs.RequestAck
+			requestStore       fakeRequestStore
+			persistedEvent     *state.Event
+			preprepareEvent    *state.Event
+			forwardEvent       *state.Event
+			originalForwardMsg *state.Event
+		)
+
+		BeforeEach(func() {
+			ack1 = &msgs.RequestAck{ClientId: 1, ReqNo: 3, Digest: []byte("digest1")}
+			ack2 = &msgs.RequestAck{ClientId: 2, ReqNo: 4, Digest: []byte("digest2")}
+			requestStore = fakeRequestStore{
+				string(ack1.Digest): []byte("data1"),
+			}
+
+			persistedEvent = &state.Event{
+				Type: &state.Event_RequestPersisted{
+					RequestPersisted: &state.EventRequestPersisted{
+						RequestAck: ack1,
+					},
+				},
+			}
+
+			preprepareEvent = &state.Event{
+				Type: &state.Event_Step{
+					Step: &state.EventStep{
+						Source: 2,
+						Msg: &msgs.Msg{
+							Type: &msgs.Msg_Preprepare{
+								Preprepare: &msgs.Preprepare{
+									SeqNo: 1,
+									Batch: []*msgs.RequestAck{ack1, ack2},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			forwardEvent = &state.Event{
+				Type: &state.Event_Step{
+					Step: &state.EventStep{
+						Source: 2,
+						Msg: &msgs.Msg{
+							Type: &msgs.Msg_ForwardRequest{
+								ForwardRequest: &msgs.ForwardRequest{
+									RequestAck:  ack2,
+									RequestData: []byte("data2"),
+								},
+							},
+						},
+					},
+				},
+			}
+			originalForwardMsg = proto.Clone(forwardEvent).(*state.Event)
+		})
+
+		readAll := func() []*recording.Event {
+			reader, err := eventlog.NewReader(output)
+			Expect(err).NotTo(HaveOccurred())
+			var result []*recording.Event
+			for {
+				event, err := reader.ReadEvent()
+				if err == io.EOF {
+					return result
+				}
+				Expect(err).NotTo(HaveOccurred())
+				result = append(result, event)
+			}
+		}
+
+		It("embeds the available request data when retained", func() {
+			interceptor := eventlog.NewRecorder(
+				1,
+				output,
+				eventlog.RetainRequestDataOpt(requestStore),
+			)
+			interceptor.Intercept(persistedEvent)
+			interceptor.Intercept(preprepareEvent)
+			interceptor.Intercept(forwardEvent)
+			interceptor.Intercept(tickEvent)
+			Expect(interceptor.Stop()).To(Succeed())
+
+			events := readAll()
+			Expect(events).To(HaveLen(4))
+			expectedData := []*recording.RequestData{
+				{RequestAck: ack1, Data: []byte("data1")},
+			}
+			Expect(events[0].RequestData).To(HaveLen(1))
+			Expect(proto.Equal(events[0].RequestData[0], expectedData[0])).To(BeTrue())
+			Expect(events[1].RequestData).To(HaveLen(1))
+			Expect(proto.Equal(events[1].RequestData[0], expectedData[0])).To(BeTrue())
+			Expect(proto.Equal(events[2].StateEvent, forwardEvent)).To(BeTrue())
+			Expect(events[3].RequestData).To(BeEmpty())
+		})
+
+		It("strips forwarded request data when not retained", func() {
+			interceptor := eventlog.NewRecorder(1, output)
+			interceptor.Intercept(persistedEvent)
+			interceptor.Intercept(forwardEvent)
+			Expect(interceptor.Stop()).To(Succeed())
+
+			events := readAll()
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].RequestData).To(BeEmpty())
+			forward := events[1].StateEvent.Type.(*state.Event_Step).Step.Msg.Type.(*msgs.Msg_ForwardRequest).ForwardRequest
+			Expect(forward.RequestData).To(BeNil())
+			Expect(proto.Equal(forward.RequestAck, ack2)).To(BeTrue())
+			Expect(proto.Equal(forwardEvent, originalForwardMsg)).To(BeTrue())
+		})
+
+		It("applies the redactor to a copy of each event", func() {
+			redactor := &digestRedactor{}
+			interceptor := eventlog.NewRecorder(
+				1,
+				output,
+				eventlog.RedactorOpt(redactor),
+			)
+			interceptor.Intercept(preprepareEvent)
+			interceptor.Intercept(tickEvent)
+			Expect(interceptor.Stop()).To(Succeed())
+
+			Expect(redactor.msgs).To(Equal(1))
+			Expect(redactor.events).To(Equal(2))
+
+			events := readAll()
+			Expect(events).To(HaveLen(2))
+			batch := events[0].StateEvent.Type.(*state.Event_Step).Step.Msg.Type.(*msgs.Msg_Preprepare).Preprepare.Batch
+			Expect(batch).To(HaveLen(2))
+			Expect(batch[0].Digest).To(BeEmpty())
+			Expect(batch[1].Digest).To(BeEmpty())
+			Expect(ack1.Digest).To(Equal([]byte("digest1")))
+			Expect(proto.Equal(events[1].StateEvent, tickEvent)).To(BeTrue())
+		})
+	})
+
 	// TODO, add tests with write failures, write blocking, etc. generate mock
 })
 
@@ -104,3 +243,28 @@ var _ = Describe("Reader", func() {
 		})
 	})
 })
+
+type fakeRequestStore map[string][]byte
+
+func (frs fakeRequestStore) GetRequest(requestAck *msgs.RequestAck) ([]byte, error) {
+	return frs[string(requestAck.Digest)], nil
+}
+
+// digestRedactor scrubs the request digests from preprepares.
+type digestRedactor struct {
+	msgs   int
+	events int
+}
+
+func (dr *digestRedactor) RedactMsg(msg *msgs.Msg) {
+	dr.msgs++
+	if preprepare, ok := msg.Type.(*msgs.Msg_Preprepare); ok {
+		for _, ack := range preprepare.Preprepare.Batch {
+			ack.Digest = nil
+		}
+	}
+}
+
+func (dr *digestRedactor) RedactEvent(event *state.Event) {
+	dr.events++
+}
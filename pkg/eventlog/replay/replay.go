@@ -0,0 +1,601 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package replay applies recorded state events to fresh state machines and
+// verifies that the replayed state machines behave as the recorded ones did.
+//
+// The recording does not contain the actions the original state machine
+// produced, but it does contain the events which resulted from them.  Each
+// time a node's actions are handed off (the recorded ActionsReceived event),
+// the actions of the replayed state machine which yield a result event are
+// noted.  Every recorded result event (hash results, checkpoint results,
+// state transfer results, and steps of messages a node sent to itself)
+// must then correspond to one of these outstanding actions.
+//
+// The actions handed off are also checked against the recorded events of
+// the other nodes, and of later incarnations of the same node.  Each message
+// a node steps from a replayed peer must be one the peer sent it.  The
+// entries a restarted node loads must be the entries it wrote to its WAL
+// before the restart, though unsynced writes may be lost, and truncated
+// entries may remain.  And no two replayed nodes, nor two incarnations of
+// one node, may commit different batches for the same sequence number.
+// Note that messages altered by a redactor will not match those sent.
+//
+// If any of these checks fail, or if the state machine panics, the replay
+// has diverged.
+package replay
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+type PlayerOpt interface{}
+
+type loggerOpt func(nodeID uint64) statemachine.Logger
+
+// LoggerOpt supplies the logger for the state machine of each node.
+// By default, the state machines log errors to the console.
+func LoggerOpt(logger func(nodeID uint64) statemachine.Logger) PlayerOpt {
+	return loggerOpt(logger)
+}
+
+type hasherOpt struct {
+	hasher processor.Hasher
+}
+
+// HasherOpt causes the player to verify that the digest of each recorded
+// hash result is the digest of the data the replayed state machine requested
+// to be hashed.  It must be the hasher used when the events were recorded.
+func HasherOpt(hasher processor.Hasher) PlayerOpt {
+	return hasherOpt{
+		hasher: hasher,
+	}
+}
+
+// EventReader is implemented by the readers of the eventlog package.
+type EventReader interface {
+	ReadEvent() (*recording.Event, error)
+}
+
+// Divergence is returned when a replayed state machine does not
+// behave as the state machine which was recorded.
+type Divergence struct {
+	// Index is the one-based index of the event in the replayed stream.
+	Index uint64
+
+	// Event is the recorded event at which the divergence was detected.
+	Event *recording.Event
+
+	// Reason describes the divergence.
+	Reason string
+
+	// Outstanding are the actions of the replayed state machine which
+	// were awaiting a result event when the divergence was detected.
+	Outstanding []*state.Action
+
+	// Status is the status of the replayed state machine when the
+	// divergence was detected.  This is before the event was applied if
+	// the event itself diverged, and after it was applied if the actions
+	// handed off diverged.  If the state machine panicked, it reflects
+	// the partially applied event.  It is nil if the status could not be
+	// computed.
+	Status *status.StateMachine
+}
+
+func (d *Divergence) Error() string {
+	return fmt.Sprintf("node %d diverged at event %d: %s", d.Event.NodeId, d.Index, d.Reason)
+}
+
+// Pretty returns a multi-line description of the divergence including
+// the event, the outstanding actions and the state machine status.
+func (d *Divergence) Pretty() string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "%s\n\nEvent:\n  %v\n\nOutstanding actions:\n", d.Error(), d.Event)
+	for _, action := range d.Outstanding {
+		fmt.Fprintf(&buffer, "  %v\n", action)
+	}
+	if d.Status != nil {
+		fmt.Fprintf(&buffer, "\nStatus:\n%s", d.Status.Pretty())
+	}
+	return buffer.String()
+}
+
+// Node is the replayed state of a single node.
+type Node struct {
+	StateMachine *statemachine.StateMachine
+
+	// ExecutionTime is the total time spent applying events
+	// to the state machine.
+	ExecutionTime time.Duration
+
+	// pending are the actions produced since the last ActionsReceived.
+	pending *statemachine.ActionList
+
+	// outstanding are the handed off actions still awaiting their results.
+	outstanding []*state.Action
+
+	// wal holds the entries at or above walLow which the node loaded, or
+	// wrote to its WAL, by index.
+	wal    map[uint64]*msgs.Persistent
+	walLow uint64
+
+	// priorWAL and priorWALLow are the WAL of the previous incarnation of
+	// the node, which is checked against the entries loaded on restart.
+	priorWAL    map[uint64]*msgs.Persistent
+	priorWALLow uint64
+
+	// stateApplied is the sequence number of the last state applied.
+	stateApplied uint64
+}
+
+// Outstanding returns the actions of the node which were handed
+// off but for which no result event has yet been replayed.
+func (n *Node) Outstanding() []*state.Action {
+	return n.outstanding
+}
+
+// duplicateWindow is the number of events for which a stepped message may
+// be stepped again, as the network may deliver a message more than once.
+const duplicateWindow = 10000
+
+// link is the direction of the messages sent from one node to another.
+type link struct {
+	source uint64
+	target uint64
+}
+
+// linkMsgs are the messages sent over a link, by their MsgKey.
+type linkMsgs struct {
+	// unstepped counts the sends of each message not yet stepped.
+	unstepped map[[sha256.Size]byte]int
+
+	// stepped holds the index of the last step of each message which
+	// was sent as often as it was stepped.
+	stepped map[[sha256.Size]byte]uint64
+}
+
+// commit is the batch first committed for a sequence number.
+type commit struct {
+	nodeID uint64
+	digest []byte
+}
+
+// Player replays recorded events, maintaining a state machine per node.
+type Player struct {
+	logger func(nodeID uint64) statemachine.Logger
+	hasher processor.Hasher
+	nodes  map[uint64]*Node
+	index  uint64
+
+	// sent holds the messages handed off to be sent over each link
+	// and not yet stepped, or only stepped recently.
+	sent map[link]*linkMsgs
+
+	// commits holds the batches committed by any node, by sequence
+	// number, above the state last applied by every node.
+	commits map[uint64]commit
+}
+
+func NewPlayer(opts ...PlayerOpt) *Player {
+	p := &Player{
+		logger: func(uint64) statemachine.Logger {
+			return statemachine.ConsoleErrorLogger
+		},
+		nodes:   map[uint64]*Node{},
+		sent:    map[link]*linkMsgs{},
+		commits: map[uint64]commit{},
+	}
+
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case loggerOpt:
+			p.logger = v
+		case hasherOpt:
+			p.hasher = v.hasher
+		}
+	}
+
+	return p
+}
+
+// Index returns the number of events applied so far.
+func (p *Player) Index() uint64 {
+	return p.index
+}
+
+// Node returns the replayed state of the given node, or nil
+// if the node has not yet been initialized.
+func (p *Player) Node(nodeID uint64) *Node {
+	return p.nodes[nodeID]
+}
+
+// NodeIDs returns the IDs of all initialized nodes in ascending order.
+func (p *Player) NodeIDs() []uint64 {
+	nodeIDs := make([]uint64, 0, len(p.nodes))
+	for nodeID := range p.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		return nodeIDs[i] < nodeIDs[j]
+	})
+	return nodeIDs
+}
+
+// Play applies every event from the reader until it is exhausted,
+// returning the first divergence, if any.
+func (p *Player) Play(reader EventReader) error {
+	for {
+		event, err := reader.ReadEvent()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.WithMessage(err, "failed reading input")
+		}
+
+		if _, err := p.Apply(event); err != nil {
+			return err
+		}
+	}
+}
+
+// Apply applies the event to the state machine of its node.  For an
+// ActionsReceived event, it returns all actions produced since the
+// previous ActionsReceived event for the node, otherwise it returns
+// the actions produced by the event.  If the event indicates that the
+// replay has diverged, the error is a *Divergence.
+func (p *Player) Apply(event *recording.Event) (result *statemachine.ActionList, err error) {
+	p.index++
+
+	var node *Node
+	if _, ok := event.StateEvent.Type.(*state.Event_Initialize); ok {
+		// Whether this is the first initialization or a restart, the
+		// node begins afresh, and results of prior actions are discarded.
+		node = &Node{
+			StateMachine: &statemachine.StateMachine{
+				Logger: p.logger(event.NodeId),
+			},
+			pending: &statemachine.ActionList{},
+			wal:     map[uint64]*msgs.Persistent{},
+		}
+		if prior, ok := p.nodes[event.NodeId]; ok {
+			node.priorWAL = prior.wal
+			node.priorWALLow = prior.walLow
+		}
+		p.nodes[event.NodeId] = node
+	} else {
+		var ok bool
+		node, ok = p.nodes[event.NodeId]
+		if !ok {
+			return nil, errors.Errorf("malformed log: node %d attempted to apply event of type %T without initializing first", event.NodeId, event.StateEvent.Type)
+		}
+	}
+
+	if reason := p.verify(node, event); reason != "" {
+		return nil, p.divergence(node, event, reason)
+	}
+
+	switch et := event.StateEvent.Type.(type) {
+	case *state.Event_LoadPersistedEntry:
+		node.wal[et.LoadPersistedEntry.Index] = et.LoadPersistedEntry.Entry
+	case *state.Event_CompleteInitialization:
+		node.priorWAL = nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.divergence(node, event, fmt.Sprintf("state machine panicked: %v\n\n%s", r, debug.Stack()))
+		}
+	}()
+
+	start := time.Now()
+	actions := node.StateMachine.ApplyEvent(event.StateEvent)
+	node.ExecutionTime += time.Since(start)
+	node.pending.PushBackList(actions)
+
+	if _, ok := event.StateEvent.Type.(*state.Event_ActionsReceived); !ok {
+		return actions, nil
+	}
+
+	result = node.pending
+	node.pending = &statemachine.ActionList{}
+
+	iter := result.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		if yieldsResult(event.NodeId, action) {
+			node.outstanding = append(node.outstanding, action)
+		}
+
+		if reason := p.handOff(event.NodeId, node, action); reason != "" {
+			return result, p.divergence(node, event, reason)
+		}
+	}
+
+	return result, nil
+}
+
+// handOff records the effects of an action handed off by the node which
+// later events are checked against.  It returns the reason for the
+// divergence if the action conflicts with an earlier one, or an empty
+// string otherwise.
+func (p *Player) handOff(nodeID uint64, node *Node, action *state.Action) string {
+	switch t := action.Type.(type) {
+	case *state.Action_Send:
+		key := MsgKey(t.Send.Msg)
+		for _, target := range t.Send.Targets {
+			if target == nodeID {
+				continue
+			}
+
+			l := link{source: nodeID, target: target}
+			sent, ok := p.sent[l]
+			if !ok {
+				sent = &linkMsgs{
+					unstepped: map[[sha256.Size]byte]int{},
+					stepped:   map[[sha256.Size]byte]uint64{},
+				}
+				p.sent[l] = sent
+			}
+			sent.unstepped[key]++
+		}
+	case *state.Action_AppendWriteAhead:
+		node.wal[t.AppendWriteAhead.Index] = t.AppendWriteAhead.Data
+	case *state.Action_TruncateWriteAhead:
+		for index := range node.wal {
+			if index < t.TruncateWriteAhead.Index {
+				delete(node.wal, index)
+			}
+		}
+		node.walLow = t.TruncateWriteAhead.Index
+	case *state.Action_Commit:
+		batch := t.Commit.Batch
+		prior, ok := p.commits[batch.SeqNo]
+		if !ok {
+			p.commits[batch.SeqNo] = commit{
+				nodeID: nodeID,
+				digest: batch.Digest,
+			}
+			break
+		}
+
+		if !bytes.Equal(prior.digest, batch.Digest) {
+			return fmt.Sprintf("committed seq_no=%d with digest %x, but node %d committed it with digest %x", batch.SeqNo, batch.Digest, prior.nodeID, prior.digest)
+		}
+	case *state.Action_StateApplied:
+		node.stateApplied = t.StateApplied.SeqNo
+		p.pruneCommits()
+	}
+
+	return ""
+}
+
+// pruneCommits discards the commits at or below the state applied by
+// every node, as no node will commit those sequence numbers again.
+func (p *Player) pruneCommits() {
+	var low uint64
+	first := true
+	for _, node := range p.nodes {
+		if first || node.stateApplied < low {
+			low = node.stateApplied
+			first = false
+		}
+	}
+
+	for seqNo := range p.commits {
+		if seqNo <= low {
+			delete(p.commits, seqNo)
+		}
+	}
+}
+
+// MsgKey identifies a message as sent by a state machine.  The signature
+// is not part of the key, as it is only added once the message is sent, nor
+// is forwarded request data, as it is added by the processor.
+func MsgKey(msg *msgs.Msg) [sha256.Size]byte {
+	forward, isForward := msg.Type.(*msgs.Msg_ForwardRequest)
+	if msg.Signature != nil || (isForward && forward.ForwardRequest.RequestData != nil) {
+		msg = proto.Clone(msg).(*msgs.Msg)
+		msg.Signature = nil
+		if isForward {
+			msg.Type.(*msgs.Msg_ForwardRequest).ForwardRequest.RequestData = nil
+		}
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		panic(fmt.Sprintf("could not marshal message: %s", err))
+	}
+	return sha256.Sum256(data)
+}
+
+func (p *Player) divergence(node *Node, event *recording.Event, reason string) *Divergence {
+	d := &Divergence{
+		Index:       p.index,
+		Event:       event,
+		Reason:      reason,
+		Outstanding: node.outstanding,
+	}
+
+	func() {
+		// The status of a state machine in a bad state may itself panic.
+		defer func() {
+			recover()
+		}()
+		d.Status, _ = node.StateMachine.Status()
+	}()
+
+	return d
+}
+
+// yieldsResult returns whether the processor feeds a result
+// event back into the state machine for the action.
+func yieldsResult(nodeID uint64, action *state.Action) bool {
+	switch t := action.Type.(type) {
+	case *state.Action_Hash, *state.Action_Checkpoint, *state.Action_StateTransfer:
+		return true
+	case *state.Action_Send:
+		for _, target := range t.Send.Targets {
+			if target == nodeID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verify checks that a result event corresponds to an outstanding action,
+// and removes that action.  It returns the reason for the divergence if
+// there is no such action, or an empty string otherwise.
+func (p *Player) verify(node *Node, event *recording.Event) string {
+	var matches func(*state.Action) bool
+	var description string
+
+	switch et := event.StateEvent.Type.(type) {
+	case *state.Event_HashResult:
+		description = "hash result"
+		matches = func(action *state.Action) bool {
+			hash, ok := action.Type.(*state.Action_Hash)
+			return ok && proto.Equal(hash.Hash.Origin, et.HashResult.Origin)
+		}
+	case *state.Event_CheckpointResult:
+		description = "checkpoint result"
+		matches = func(action *state.Action) bool {
+			checkpoint, ok := action.Type.(*state.Action_Checkpoint)
+			return ok && checkpoint.Checkpoint.SeqNo == et.CheckpointResult.SeqNo
+		}
+	case *state.Event_StateTransferComplete:
+		description = "state transfer completion"
+		matches = func(action *state.Action) bool {
+			transfer, ok := action.Type.(*state.Action_StateTransfer)
+			return ok &&
+				transfer.StateTransfer.SeqNo == et.StateTransferComplete.SeqNo &&
+				bytes.Equal(transfer.StateTransfer.Value, et.StateTransferComplete.CheckpointValue)
+		}
+	case *state.Event_StateTransferFailed:
+		description = "state transfer failure"
+		matches = func(action *state.Action) bool {
+			transfer, ok := action.Type.(*state.Action_StateTransfer)
+			return ok &&
+				transfer.StateTransfer.SeqNo == et.StateTransferFailed.SeqNo &&
+				bytes.Equal(transfer.StateTransfer.Value, et.StateTransferFailed.CheckpointValue)
+		}
+	case *state.Event_LoadPersistedEntry:
+		load := et.LoadPersistedEntry
+		if node.priorWAL == nil || load.Index < node.priorWALLow {
+			return ""
+		}
+
+		written, ok := node.priorWAL[load.Index]
+		if !ok {
+			return fmt.Sprintf("loaded WAL entry at index %d which was never written", load.Index)
+		}
+
+		if !proto.Equal(written, load.Entry) {
+			return fmt.Sprintf("loaded WAL entry at index %d which differs from the entry written", load.Index)
+		}
+
+		return ""
+	case *state.Event_Step:
+		if et.Step.Source != event.NodeId {
+			return p.verifyPeerStep(event.NodeId, et.Step)
+		}
+		description = fmt.Sprintf("step of a %T sent to itself", et.Step.Msg.Type)
+		matches = func(action *state.Action) bool {
+			send, ok := action.Type.(*state.Action_Send)
+			return ok && proto.Equal(send.Send.Msg, et.Step.Msg)
+		}
+	default:
+		return ""
+	}
+
+	for i, action := range node.outstanding {
+		if !matches(action) {
+			continue
+		}
+
+		node.outstanding = append(node.outstanding[:i:i], node.outstanding[i+1:]...)
+
+		if hashResult, ok := event.StateEvent.Type.(*state.Event_HashResult); ok && p.hasher != nil {
+			h := p.hasher.New()
+			for _, data := range action.Type.(*state.Action_Hash).Hash.Data {
+				h.Write(data)
+			}
+			if digest := h.Sum(nil); !bytes.Equal(digest, hashResult.HashResult.Digest) {
+				return fmt.Sprintf("recorded hash result digest %x does not match the digest %x of the requested data", hashResult.HashResult.Digest, digest)
+			}
+		}
+
+		return ""
+	}
+
+	return fmt.Sprintf("recorded %s does not correspond to any outstanding action", description)
+}
+
+// verifyPeerStep checks that a message stepped from a replayed peer was
+// sent by that peer.  Forwarded requests are sent by the processor rather
+// than the state machine, so they are not checked.
+func (p *Player) verifyPeerStep(nodeID uint64, step *state.EventStep) string {
+	if _, ok := p.nodes[step.Source]; !ok {
+		return ""
+	}
+
+	if _, ok := step.Msg.Type.(*msgs.Msg_ForwardRequest); ok {
+		return ""
+	}
+
+	if p.index%duplicateWindow == 0 {
+		p.pruneStepped()
+	}
+
+	sent, ok := p.sent[link{source: step.Source, target: nodeID}]
+	if ok {
+		key := MsgKey(step.Msg)
+		if count, ok := sent.unstepped[key]; ok {
+			if count == 1 {
+				delete(sent.unstepped, key)
+			} else {
+				sent.unstepped[key] = count - 1
+			}
+			sent.stepped[key] = p.index
+			return ""
+		}
+
+		if _, ok := sent.stepped[key]; ok {
+			sent.stepped[key] = p.index
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("recorded step of a %T from node %d does not correspond to any message it sent", step.Msg.Type, step.Source)
+}
+
+// pruneStepped discards the messages last stepped more than the
+// duplicate window ago.
+func (p *Player) pruneStepped() {
+	for _, sent := range p.sent {
+		for key, index := range sent.stepped {
+			if index+duplicateWindow < p.index {
+				delete(sent.stepped, key)
+			}
+		}
+	}
+}
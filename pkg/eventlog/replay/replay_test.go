@@ -0,0 +1,282 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog/replay"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+)
+
+type sliceReader []*recording.Event
+
+func (sr *sliceReader) ReadEvent() (*recording.Event, error) {
+	if len(*sr) == 0 {
+		return nil, io.EOF
+	}
+	event := (*sr)[0]
+	*sr = (*sr)[1:]
+	return event, nil
+}
+
+var _ = Describe("Player", func() {
+	var (
+		hasher processor.Hasher
+		events []*recording.Event
+		player *replay.Player
+	)
+
+	// record replaces the events with a recording of the spec.
+	record := func(spec *testengine.Spec) {
+		logBytes := &bytes.Buffer{}
+		gzWriter := gzip.NewWriter(logBytes)
+
+		recorder := spec.Recorder()
+		hasher = recorder.Hasher
+
+		recording, err := recorder.Recording(gzWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = recording.DrainClients(5000)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gzWriter.Close()).To(Succeed())
+
+		reader, err := eventlog.NewReader(logBytes)
+		Expect(err).NotTo(HaveOccurred())
+
+		events = nil
+		for {
+			event, err := reader.ReadEvent()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).NotTo(HaveOccurred())
+			events = append(events, event)
+		}
+	}
+
+	BeforeEach(func() {
+		record(&testengine.Spec{
+			NodeCount:     4,
+			ClientCount:   4,
+			ReqsPerClient: 20,
+		})
+
+		player = replay.NewPlayer(replay.HasherOpt(hasher))
+	})
+
+	// firstIndex returns the position of the first event of the given type.
+	firstIndex := func(match func(*state.Event) bool) int {
+		for i, event := range events {
+			if match(event.StateEvent) {
+				return i
+			}
+		}
+		Fail("no matching event in the recording")
+		return 0
+	}
+
+	It("replays the recording without diverging", func() {
+		reader := sliceReader(events)
+		Expect(player.Play(&reader)).To(Succeed())
+		Expect(player.Index()).To(Equal(uint64(len(events))))
+		Expect(player.NodeIDs()).To(Equal([]uint64{0, 1, 2, 3}))
+		Expect(player.Node(0).StateMachine).NotTo(BeNil())
+	})
+
+	When("a recorded result does not correspond to an action", func() {
+		var index int
+
+		BeforeEach(func() {
+			index = firstIndex(func(event *state.Event) bool {
+				_, ok := event.Type.(*state.Event_CheckpointResult)
+				return ok
+			})
+			events[index].StateEvent.Type.(*state.Event_CheckpointResult).CheckpointResult.SeqNo += 1000
+		})
+
+		It("reports the divergence with its context", func() {
+			reader := sliceReader(events)
+			err := player.Play(&reader)
+			Expect(err).To(HaveOccurred())
+
+			divergence, ok := err.(*replay.Divergence)
+			Expect(ok).To(BeTrue())
+			Expect(divergence.Index).To(Equal(uint64(index + 1)))
+			Expect(divergence.Event).To(Equal(events[index]))
+			Expect(divergence.Reason).To(Equal("recorded checkpoint result does not correspond to any outstanding action"))
+			Expect(divergence.Outstanding).NotTo(BeEmpty())
+			Expect(divergence.Status).NotTo(BeNil())
+			Expect(divergence.Pretty()).To(ContainSubstring("Outstanding actions:"))
+		})
+	})
+
+	When("a recorded hash digest does not match the requested data", func() {
+		BeforeEach(func() {
+			index := firstIndex(func(event *state.Event) bool {
+				_, ok := event.Type.(*state.Event_HashResult)
+				return ok
+			})
+			events[index].StateEvent.Type.(*state.Event_HashResult).HashResult.Digest = []byte("bad-digest")
+		})
+
+		It("reports the divergence", func() {
+			reader := sliceReader(events)
+			err := player.Play(&reader)
+			Expect(err).To(MatchError(ContainSubstring("recorded hash result digest 6261642d646967657374 does not match the digest")))
+		})
+
+		It("does not verify digests without a hasher", func() {
+			reader := sliceReader(events)
+			Expect(replay.NewPlayer().Play(&reader)).To(Succeed())
+		})
+	})
+
+	When("a stepped message was not sent by its source", func() {
+		var index int
+
+		BeforeEach(func() {
+			index = -1
+			for i, event := range events {
+				step, ok := event.StateEvent.Type.(*state.Event_Step)
+				if ok && step.Step.Source != event.NodeId {
+					index = i
+					break
+				}
+			}
+			Expect(index).NotTo(Equal(-1))
+
+			events[index].StateEvent.Type.(*state.Event_Step).Step.Msg = &msgs.Msg{
+				Type: &msgs.Msg_Suspect{
+					Suspect: &msgs.Suspect{
+						Epoch: 1000,
+					},
+				},
+			}
+		})
+
+		It("reports the divergence", func() {
+			reader := sliceReader(events)
+			err := player.Play(&reader)
+			Expect(err).To(HaveOccurred())
+
+			divergence, ok := err.(*replay.Divergence)
+			Expect(ok).To(BeTrue())
+			Expect(divergence.Index).To(Equal(uint64(index + 1)))
+			Expect(divergence.Reason).To(Equal(fmt.Sprintf(
+				"recorded step of a *msgs.Msg_Suspect from node %d does not correspond to any message it sent",
+				events[index].StateEvent.Type.(*state.Event_Step).Step.Source,
+			)))
+		})
+	})
+
+	When("the recorded steps are signed and delivered more than once", func() {
+		BeforeEach(func() {
+			var duplicated []*recording.Event
+			for _, event := range events {
+				duplicated = append(duplicated, event)
+
+				step, ok := event.StateEvent.Type.(*state.Event_Step)
+				if !ok || step.Step.Source == event.NodeId {
+					continue
+				}
+
+				step.Step.Msg.Signature = []byte(fmt.Sprintf("signed-by-%d", step.Step.Source))
+				duplicated = append(duplicated, proto.Clone(event).(*recording.Event))
+			}
+			events = duplicated
+		})
+
+		It("replays the recording without diverging", func() {
+			reader := sliceReader(events)
+			Expect(player.Play(&reader)).To(Succeed())
+		})
+	})
+
+	When("a node restarts", func() {
+		var (
+			restartIndex int
+			loads        []*state.EventLoadPersistedEntry
+		)
+
+		// Node 0 restarts as soon as it has initialized, loading the
+		// same entries it loaded at startup.
+		BeforeEach(func() {
+			var startup []*recording.Event
+			for i, event := range events {
+				if event.NodeId != 0 {
+					continue
+				}
+
+				startup = append(startup, proto.Clone(event).(*recording.Event))
+				if _, ok := event.StateEvent.Type.(*state.Event_CompleteInitialization); ok {
+					restartIndex = i + 1
+					break
+				}
+			}
+
+			loads = nil
+			for _, event := range startup {
+				if load, ok := event.StateEvent.Type.(*state.Event_LoadPersistedEntry); ok {
+					loads = append(loads, load.LoadPersistedEntry)
+				}
+			}
+			Expect(loads).NotTo(BeEmpty())
+
+			events = append(events[:restartIndex:restartIndex], append(startup, events[restartIndex:]...)...)
+		})
+
+		It("replays the recording without diverging", func() {
+			reader := sliceReader(events)
+			Expect(player.Play(&reader)).To(Succeed())
+		})
+
+		It("reports a loaded entry which differs from the entry written", func() {
+			load := loads[len(loads)-1]
+			load.Entry = &msgs.Persistent{
+				Type: &msgs.Persistent_Suspect{
+					Suspect: &msgs.Suspect{
+						Epoch: 1000,
+					},
+				},
+			}
+
+			reader := sliceReader(events)
+			err := player.Play(&reader)
+			Expect(err).To(MatchError(fmt.Sprintf("node 0 diverged at event %d: loaded WAL entry at index %d which differs from the entry written", restartIndex+len(loads)+1, load.Index)))
+		})
+
+		It("reports a loaded entry which was never written", func() {
+			load := loads[len(loads)-1]
+			load.Index += 1000
+
+			reader := sliceReader(events)
+			err := player.Play(&reader)
+			Expect(err).To(MatchError(fmt.Sprintf("node 0 diverged at event %d: loaded WAL entry at index %d which was never written", restartIndex+len(loads)+1, load.Index)))
+		})
+	})
+
+	When("an event precedes the initialization of its node", func() {
+		It("returns an error", func() {
+			_, err := player.Apply(events[len(events)-1])
+			Expect(err).To(MatchError(ContainSubstring("without initializing first")))
+		})
+	})
+})
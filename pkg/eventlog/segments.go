@@ -0,0 +1,615 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventlog
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+)
+
+const (
+	segmentPrefix      = "segment-"
+	segmentEventsExt   = ".gz"
+	segmentIndexExt    = ".idx"
+	segmentNameDigits  = 8
+	segmentFilePerm    = 0644
+	segmentDirFilePerm = 0755
+)
+
+// DefaultSegmentSize is the approximate number of compressed bytes
+// after which the file recorder begins a new segment.
+const DefaultSegmentSize = 64 * 1024 * 1024
+
+// DefaultIndexInterval is the number of events between index entries
+// in a segment.  Seeking to an event requires decompressing at most
+// this many events.
+const DefaultIndexInterval = 1000
+
+type segmentSizeOpt int64
+
+// SegmentSizeOpt overrides the approximate number of compressed bytes
+// after which the file recorder rotates to a new segment.
+func SegmentSizeOpt(size int64) RecorderOpt {
+	return segmentSizeOpt(size)
+}
+
+type segmentTimeOpt int64
+
+// SegmentTimeOpt causes the file recorder to rotate to a new segment once
+// the events in the current segment span the given duration.  The duration
+// is expressed in the units of the time source, which by default is
+// milliseconds.  By default, segments are only rotated by size.
+func SegmentTimeOpt(span int64) RecorderOpt {
+	return segmentTimeOpt(span)
+}
+
+type retainSegmentsOpt int
+
+// RetainSegmentsOpt causes the file recorder to delete all but the most
+// recent count segments whenever it rotates.  By default, all segments
+// are retained.
+func RetainSegmentsOpt(count int) RecorderOpt {
+	return retainSegmentsOpt(count)
+}
+
+type indexIntervalOpt uint64
+
+// IndexIntervalOpt overrides the number of events between index entries.
+func IndexIntervalOpt(interval uint64) RecorderOpt {
+	return indexIntervalOpt(interval)
+}
+
+// NewFileRecorder creates a recorder which writes its events to a series of
+// segment files in dir, rotating them by size or time.  Alongside each segment
+// it writes an index which maps event numbers and times to offsets in the
+// segment so that the log may be read starting from an arbitrary event without
+// decompressing the events which precede it.  If dir already contains segments,
+// the recorder begins a new segment and continues the event numbering.
+func NewFileRecorder(nodeID uint64, dir string, opts ...RecorderOpt) (*Recorder, error) {
+	sw := &segmentWriter{
+		dir:             dir,
+		maxSegmentBytes: DefaultSegmentSize,
+		indexInterval:   DefaultIndexInterval,
+	}
+
+	for _, opt := range opts {
+		switch v := opt.(type) {
+		case segmentSizeOpt:
+			sw.maxSegmentBytes = int64(v)
+		case segmentTimeOpt:
+			sw.maxSegmentTime = int64(v)
+		case retainSegmentsOpt:
+			sw.retainSegments = int(v)
+		case indexIntervalOpt:
+			sw.indexInterval = uint64(v)
+		}
+	}
+
+	if sw.indexInterval == 0 {
+		return nil, errors.Errorf("index interval must be positive")
+	}
+
+	if err := os.MkdirAll(dir, segmentDirFilePerm); err != nil {
+		return nil, errors.WithMessage(err, "could not create log directory")
+	}
+
+	index, err := OpenSegmentIndex(dir)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not read existing segments")
+	}
+
+	for _, segment := range index.segments {
+		sw.segments = append(sw.segments, segment.seqNo)
+	}
+
+	if len(index.segments) > 0 {
+		sw.nextSeqNo = index.segments[len(index.segments)-1].seqNo + 1
+		sw.eventNo, err = index.EndEventNo()
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not determine last event number")
+		}
+	}
+
+	i := newRecorder(nodeID, opts)
+	sw.compressionLevel = i.compressionLevel
+
+	go i.run(func() (eventWriter, error) {
+		return sw, nil
+	})
+
+	return i, nil
+}
+
+// segmentWriter writes events into rotating segment files and maintains
+// an index for each segment.  Each index entry marks the start of a new
+// gzip member in the segment, so that decompression may begin there.
+type segmentWriter struct {
+	dir              string
+	compressionLevel int
+	maxSegmentBytes  int64
+	maxSegmentTime   int64
+	retainSegments   int
+	indexInterval    uint64
+
+	segments  []uint64
+	nextSeqNo uint64
+	eventNo   uint64
+
+	// The remaining fields describe the current segment, if any.
+	eventsFile   *os.File
+	indexFile    *os.File
+	counter      *countingWriter
+	gzWriter     *gzip.Writer
+	startTime    int64
+	memberEvents uint64
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.writer.Write(p)
+	cw.count += int64(n)
+	return n, err
+}
+
+func segmentPath(dir string, seqNo uint64, ext string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%0*d%s", segmentPrefix, segmentNameDigits, seqNo, ext))
+}
+
+func (sw *segmentWriter) write(event *recording.Event) error {
+	switch {
+	case sw.eventsFile == nil:
+		if err := sw.rotate(event); err != nil {
+			return err
+		}
+	case sw.counter.count >= sw.maxSegmentBytes,
+		sw.maxSegmentTime > 0 && event.Time-sw.startTime >= sw.maxSegmentTime:
+		if err := sw.rotate(event); err != nil {
+			return err
+		}
+	case sw.memberEvents >= sw.indexInterval:
+		if err := sw.startMember(event); err != nil {
+			return err
+		}
+	}
+
+	if err := WriteRecordedEvent(sw.gzWriter, event); err != nil {
+		return err
+	}
+
+	sw.eventNo++
+	sw.memberEvents++
+
+	return nil
+}
+
+// rotate closes the current segment, if any, opens the next one
+// and removes any segments which should no longer be retained.
+func (sw *segmentWriter) rotate(event *recording.Event) error {
+	if err := sw.closeSegment(); err != nil {
+		return errors.WithMessage(err, "could not close segment")
+	}
+
+	seqNo := sw.nextSeqNo
+	sw.nextSeqNo++
+
+	eventsFile, err := os.OpenFile(segmentPath(sw.dir, seqNo, segmentEventsExt), os.O_CREATE|os.O_EXCL|os.O_WRONLY, segmentFilePerm)
+	if err != nil {
+		return errors.WithMessage(err, "could not create segment")
+	}
+
+	indexFile, err := os.OpenFile(segmentPath(sw.dir, seqNo, segmentIndexExt), os.O_CREATE|os.O_EXCL|os.O_WRONLY, segmentFilePerm)
+	if err != nil {
+		eventsFile.Close()
+		return errors.WithMessage(err, "could not create segment index")
+	}
+
+	sw.eventsFile = eventsFile
+	sw.indexFile = indexFile
+	sw.counter = &countingWriter{writer: eventsFile}
+	sw.startTime = event.Time
+	sw.segments = append(sw.segments, seqNo)
+
+	if err := sw.startMember(event); err != nil {
+		return err
+	}
+
+	if sw.retainSegments <= 0 {
+		return nil
+	}
+
+	for len(sw.segments) > sw.retainSegments {
+		oldSeqNo := sw.segments[0]
+		sw.segments = sw.segments[1:]
+		// The index is removed first, so that an interrupted removal
+		// leaves at most an unindexed segment, which readers ignore.
+		if err := os.Remove(segmentPath(sw.dir, oldSeqNo, segmentIndexExt)); err != nil && !os.IsNotExist(err) {
+			return errors.WithMessage(err, "could not remove old segment index")
+		}
+		if err := os.Remove(segmentPath(sw.dir, oldSeqNo, segmentEventsExt)); err != nil && !os.IsNotExist(err) {
+			return errors.WithMessage(err, "could not remove old segment")
+		}
+	}
+
+	return nil
+}
+
+// startMember finishes the current gzip member, if any, and begins a new
+// one, recording its offset in the segment index.
+func (sw *segmentWriter) startMember(event *recording.Event) error {
+	if sw.gzWriter != nil {
+		if err := sw.gzWriter.Close(); err != nil {
+			return errors.WithMessage(err, "could not finish gzip member")
+		}
+	}
+
+	err := writeSizePrefixedProto(sw.indexFile, &recording.IndexEntry{
+		EventNo: sw.eventNo,
+		Time:    event.Time,
+		Offset:  sw.counter.count,
+	})
+	if err != nil {
+		return errors.WithMessage(err, "could not write index entry")
+	}
+
+	gzWriter, err := gzip.NewWriterLevel(sw.counter, sw.compressionLevel)
+	if err != nil {
+		return err
+	}
+
+	sw.gzWriter = gzWriter
+	sw.memberEvents = 0
+
+	return nil
+}
+
+func (sw *segmentWriter) closeSegment() error {
+	if sw.eventsFile == nil {
+		return nil
+	}
+
+	gzErr := sw.gzWriter.Close()
+	eventsErr := sw.eventsFile.Close()
+	indexErr := sw.indexFile.Close()
+
+	sw.eventsFile = nil
+	sw.indexFile = nil
+	sw.counter = nil
+	sw.gzWriter = nil
+
+	switch {
+	case gzErr != nil:
+		return gzErr
+	case eventsErr != nil:
+		return eventsErr
+	default:
+		return indexErr
+	}
+}
+
+func (sw *segmentWriter) Close() error {
+	return sw.closeSegment()
+}
+
+// SegmentIndex describes the segments written to a directory by
+// a file recorder, and allows reading from an arbitrary event.
+type SegmentIndex struct {
+	dir      string
+	segments []*segmentIndex
+}
+
+type segmentIndex struct {
+	seqNo   uint64
+	entries []*recording.IndexEntry
+}
+
+// OpenSegmentIndex reads the indices of all of the segments in dir.
+func OpenSegmentIndex(dir string) (*SegmentIndex, error) {
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not list log directory")
+	}
+
+	si := &SegmentIndex{
+		dir: dir,
+	}
+
+	for _, fileInfo := range fileInfos {
+		name := fileInfo.Name()
+		if !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentIndexExt) {
+			continue
+		}
+
+		seqNo, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentIndexExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		entries, err := readIndexEntries(filepath.Join(dir, name))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not read index %s", name)
+		}
+
+		if len(entries) == 0 {
+			// The recorder stopped before writing the first event.
+			continue
+		}
+
+		si.segments = append(si.segments, &segmentIndex{
+			seqNo:   seqNo,
+			entries: entries,
+		})
+	}
+
+	sort.Slice(si.segments, func(i, j int) bool {
+		return si.segments[i].seqNo < si.segments[j].seqNo
+	})
+
+	return si, nil
+}
+
+func readIndexEntries(path string) ([]*recording.IndexEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	buffer := &bytes.Buffer{}
+	var entries []*recording.IndexEntry
+	for {
+		entry := &recording.IndexEntry{}
+		err := readSizePrefixedProto(reader, entry, buffer)
+		switch errors.Cause(err) {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			// A truncated final entry is the result of an unclean
+			// shutdown, and the entry is simply ignored.
+			return entries, nil
+		default:
+			return nil, err
+		}
+		buffer.Reset()
+		entries = append(entries, entry)
+	}
+}
+
+// Empty returns whether the directory contains no events.
+func (si *SegmentIndex) Empty() bool {
+	return len(si.segments) == 0
+}
+
+// FirstEventNo returns the number of the oldest retained event.
+func (si *SegmentIndex) FirstEventNo() uint64 {
+	if si.Empty() {
+		return 0
+	}
+	return si.segments[0].entries[0].EventNo
+}
+
+// EndEventNo returns the number one past the newest event.  It
+// decompresses the events following the last index entry to count them.
+func (si *SegmentIndex) EndEventNo() (uint64, error) {
+	if si.Empty() {
+		return 0, nil
+	}
+
+	lastSegment := si.segments[len(si.segments)-1]
+	lastEntry := lastSegment.entries[len(lastSegment.entries)-1]
+	eventNo := lastEntry.EventNo
+	reader, err := si.openSegment(len(si.segments)-1, len(lastSegment.entries)-1)
+	if err != nil {
+		// After an unclean shutdown, the last indexed gzip
+		// member may not have been written at all.
+		return eventNo, nil
+	}
+	defer reader.Close()
+
+	for {
+		if _, err := reader.reader.ReadEvent(); err != nil {
+			// Any error indicates the end of the usable portion
+			// of the segment, possibly because of an unclean shutdown.
+			return eventNo, nil
+		}
+		eventNo++
+	}
+}
+
+// ReaderAt returns a reader whose first event is event number eventNo.
+func (si *SegmentIndex) ReaderAt(eventNo uint64) (*SegmentReader, error) {
+	if si.Empty() {
+		return nil, errors.Errorf("log contains no events")
+	}
+
+	if eventNo < si.FirstEventNo() {
+		return nil, errors.Errorf("event %d precedes the oldest retained event %d", eventNo, si.FirstEventNo())
+	}
+
+	segment, entry := si.find(func(entry *recording.IndexEntry) bool {
+		return entry.EventNo <= eventNo
+	})
+
+	sr, err := si.openSegment(segment, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	for skip := eventNo - si.segments[segment].entries[entry].EventNo; skip > 0; skip-- {
+		if _, err := sr.ReadEvent(); err != nil {
+			sr.Close()
+			if err == io.EOF {
+				return nil, errors.Errorf("event %d is beyond the end of the log", eventNo)
+			}
+			return nil, err
+		}
+	}
+
+	return sr, nil
+}
+
+// ReaderAtTime returns a reader whose first event is the first
+// event recorded at or after the given time.
+func (si *SegmentIndex) ReaderAtTime(time int64) (*SegmentReader, error) {
+	if si.Empty() {
+		return nil, errors.Errorf("log contains no events")
+	}
+
+	segment, entry := si.find(func(entry *recording.IndexEntry) bool {
+		return entry.Time < time
+	})
+
+	sr, err := si.openSegment(segment, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		event, err := sr.peek()
+		if err != nil {
+			sr.Close()
+			if err == io.EOF {
+				return nil, errors.Errorf("no event at or after time %d", time)
+			}
+			return nil, err
+		}
+
+		if event.Time >= time {
+			return sr, nil
+		}
+
+		sr.next = nil
+	}
+}
+
+// find returns the position of the last index entry satisfying before,
+// or the first entry if none do.  Entries are assumed to be ordered
+// such that before holds for some prefix of them.
+func (si *SegmentIndex) find(before func(*recording.IndexEntry) bool) (int, int) {
+	segment := sort.Search(len(si.segments), func(i int) bool {
+		return !before(si.segments[i].entries[0])
+	}) - 1
+	if segment < 0 {
+		return 0, 0
+	}
+
+	entries := si.segments[segment].entries
+	entry := sort.Search(len(entries), func(i int) bool {
+		return !before(entries[i])
+	}) - 1
+
+	return segment, entry
+}
+
+func (si *SegmentIndex) openSegment(segment, entry int) (*SegmentReader, error) {
+	sr := &SegmentReader{
+		index:   si,
+		segment: segment,
+	}
+
+	if err := sr.open(si.segments[segment].entries[entry].Offset); err != nil {
+		return nil, err
+	}
+
+	return sr, nil
+}
+
+// SegmentReader reads the events of a segmented log in order,
+// continuing from one segment to the next.
+type SegmentReader struct {
+	index   *SegmentIndex
+	segment int
+	file    *os.File
+	reader  *Reader
+	next    *recording.Event
+}
+
+func (sr *SegmentReader) open(offset int64) error {
+	path := segmentPath(sr.index.dir, sr.index.segments[sr.segment].seqNo, segmentEventsExt)
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.WithMessage(err, "could not open segment")
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return errors.WithMessage(err, "could not seek in segment")
+	}
+
+	reader, err := NewReader(file)
+	if err != nil {
+		file.Close()
+		return errors.WithMessagef(err, "could not read segment %s", path)
+	}
+
+	sr.file = file
+	sr.reader = reader
+
+	return nil
+}
+
+func (sr *SegmentReader) peek() (*recording.Event, error) {
+	if sr.next != nil {
+		return sr.next, nil
+	}
+
+	for {
+		event, err := sr.reader.ReadEvent()
+		if err == nil {
+			sr.next = event
+			return event, nil
+		}
+
+		if err != io.EOF {
+			return nil, err
+		}
+
+		if sr.segment+1 >= len(sr.index.segments) {
+			return nil, io.EOF
+		}
+
+		sr.file.Close()
+		sr.segment++
+		if err := sr.open(0); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ReadEvent returns the next event of the log, or io.EOF
+// once the events of the last segment are exhausted.
+func (sr *SegmentReader) ReadEvent() (*recording.Event, error) {
+	event, err := sr.peek()
+	if err != nil {
+		return nil, err
+	}
+
+	sr.next = nil
+
+	return event, nil
+}
+
+// Close releases the file held open by the reader.
+func (sr *SegmentReader) Close() error {
+	return sr.file.Close()
+}
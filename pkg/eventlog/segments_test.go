@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventlog_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+)
+
+var _ = Describe("FileRecorder", func() {
+	var (
+		dir  string
+		now  int64
+		opts []eventlog.RecorderOpt
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "eventlog.*")
+		Expect(err).NotTo(HaveOccurred())
+
+		now = 0
+		opts = []eventlog.RecorderOpt{
+			eventlog.TimeSourceOpt(func() int64 { return now }),
+			eventlog.IndexIntervalOpt(10),
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	// record writes count tick events, advancing the time by one for each.
+	record := func(count int, extraOpts ...eventlog.RecorderOpt) {
+		recorder, err := eventlog.NewFileRecorder(1, dir, append(opts, extraOpts...)...)
+		Expect(err).NotTo(HaveOccurred())
+		for i := 0; i < count; i++ {
+			now++
+			Expect(recorder.Intercept(tickEvent)).To(Succeed())
+		}
+		Expect(recorder.Stop()).To(Succeed())
+	}
+
+	segments := func() []string {
+		matches, err := filepath.Glob(filepath.Join(dir, "segment-*.gz"))
+		Expect(err).NotTo(HaveOccurred())
+		return matches
+	}
+
+	expectEvents := func(reader *eventlog.SegmentReader, firstTime, lastTime int64) {
+		defer reader.Close()
+		for time := firstTime; time <= lastTime; time++ {
+			event, err := reader.ReadEvent()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.Time).To(Equal(time))
+		}
+		_, err := reader.ReadEvent()
+		Expect(err).To(Equal(io.EOF))
+	}
+
+	It("can be read from an arbitrary event", func() {
+		record(95)
+		Expect(segments()).To(HaveLen(1))
+
+		index, err := eventlog.OpenSegmentIndex(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.FirstEventNo()).To(Equal(uint64(0)))
+		Expect(index.EndEventNo()).To(Equal(uint64(95)))
+
+		reader, err := index.ReaderAt(0)
+		Expect(err).NotTo(HaveOccurred())
+		expectEvents(reader, 1, 95)
+
+		reader, err = index.ReaderAt(47)
+		Expect(err).NotTo(HaveOccurred())
+		expectEvents(reader, 48, 95)
+
+		_, err = index.ReaderAt(96)
+		Expect(err).To(MatchError("event 96 is beyond the end of the log"))
+	})
+
+	It("can be read from an arbitrary time", func() {
+		record(35, eventlog.SegmentTimeOpt(10))
+
+		index, err := eventlog.OpenSegmentIndex(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		reader, err := index.ReaderAtTime(22)
+		Expect(err).NotTo(HaveOccurred())
+		expectEvents(reader, 22, 35)
+
+		_, err = index.ReaderAtTime(36)
+		Expect(err).To(MatchError("no event at or after time 36"))
+	})
+
+	It("rotates segments by time", func() {
+		record(35, eventlog.SegmentTimeOpt(10))
+		Expect(segments()).To(HaveLen(4))
+
+		index, err := eventlog.OpenSegmentIndex(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		reader, err := index.ReaderAt(8)
+		Expect(err).NotTo(HaveOccurred())
+		expectEvents(reader, 9, 35)
+	})
+
+	It("rotates segments by size and retains only the newest", func() {
+		record(200, eventlog.SegmentSizeOpt(1), eventlog.RetainSegmentsOpt(3))
+		Expect(segments()).To(HaveLen(3))
+
+		index, err := eventlog.OpenSegmentIndex(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.FirstEventNo()).To(Equal(uint64(197)))
+
+		_, err = index.ReaderAt(196)
+		Expect(err).To(MatchError("event 196 precedes the oldest retained event 197"))
+
+		reader, err := index.ReaderAt(198)
+		Expect(err).NotTo(HaveOccurred())
+		expectEvents(reader, 199, 200)
+	})
+
+	It("continues the event numbering when restarted", func() {
+		record(25)
+		record(25)
+		Expect(segments()).To(HaveLen(2))
+
+		index, err := eventlog.OpenSegmentIndex(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(index.EndEventNo()).To(Equal(uint64(50)))
+
+		reader, err := index.ReaderAt(30)
+		Expect(err).NotTo(HaveOccurred())
+		expectEvents(reader, 31, 50)
+	})
+
+	It("writes the recorded events", func() {
+		record(1)
+
+		index, err := eventlog.OpenSegmentIndex(dir)
+		Expect(err).NotTo(HaveOccurred())
+		reader, err := index.ReaderAt(0)
+		Expect(err).NotTo(HaveOccurred())
+		defer reader.Close()
+
+		event, err := reader.ReadEvent()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(event.NodeId).To(Equal(uint64(1)))
+		Expect(event.StateEvent.Type).To(BeAssignableToTypeOf(&state.Event_TickElapsed{}))
+	})
+})
@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package logging adapts popular structured logging libraries to the
+// mirbft.Logger interface.  The key/value pairs of each entry are passed to
+// the library as structured fields, rather than formatted into the message.
+package logging
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// MissingValue is the value given to a trailing key without a value.
+const MissingValue = "%MISSING%"
+
+// fieldValue renders byte slices as hex, as the console loggers do, and
+// passes every other value through unmodified.
+func fieldValue(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		return hex.EncodeToString(b)
+	}
+	return value
+}
+
+// eachField invokes f for each key/value pair of the log details.
+func eachField(args []interface{}, f func(key string, value interface{})) {
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok {
+			key = fmt.Sprint(args[i])
+		}
+
+		if i+1 < len(args) {
+			f(key, fieldValue(args[i+1]))
+		} else {
+			f(key, MissingValue)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+)
+
+var _ = Describe("Zap", func() {
+	It("passes the key/value pairs as fields", func() {
+		core, logs := observer.New(zapcore.InfoLevel)
+		logger := logging.NewZap(zap.New(core))
+
+		logger.Log(mirbft.LevelDebug, "hidden")
+		logger.Log(mirbft.LevelWarn, "committed", "seq_no", uint64(7), "digest", []byte{0xab, 0xcd}, "dangling")
+
+		Expect(logs.Len()).To(Equal(1))
+		entry := logs.All()[0]
+		Expect(entry.Level).To(Equal(zapcore.WarnLevel))
+		Expect(entry.Message).To(Equal("committed"))
+		Expect(entry.ContextMap()).To(Equal(map[string]interface{}{
+			"seq_no":   uint64(7),
+			"digest":   "abcd",
+			"dangling": logging.MissingValue,
+		}))
+	})
+})
+
+var _ = Describe("Logrus", func() {
+	It("passes the key/value pairs as fields", func() {
+		base, hook := logrustest.NewNullLogger()
+		logger := logging.NewLogrus(base)
+
+		logger.Log(mirbft.LevelDebug, "hidden")
+		logger.Log(mirbft.LevelError, "failed", "epoch_no", 3, "digest", []byte{0x01})
+
+		Expect(hook.AllEntries()).To(HaveLen(1))
+		entry := hook.LastEntry()
+		Expect(entry.Level).To(Equal(logrus.ErrorLevel))
+		Expect(entry.Message).To(Equal("failed"))
+		Expect(entry.Data).To(Equal(logrus.Fields{
+			"epoch_no": 3,
+			"digest":   "01",
+		}))
+	})
+})
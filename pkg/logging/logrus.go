@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/hyperledger-labs/mirbft"
+)
+
+// Logrus implements mirbft.Logger on top of a logrus.FieldLogger,
+// such as a *logrus.Logger or *logrus.Entry.
+type Logrus struct {
+	Logger logrus.FieldLogger
+}
+
+// NewLogrus adapts the given logrus.FieldLogger.
+func NewLogrus(logger logrus.FieldLogger) *Logrus {
+	return &Logrus{
+		Logger: logger,
+	}
+}
+
+func (l *Logrus) Log(level mirbft.LogLevel, text string, args ...interface{}) {
+	fields := make(logrus.Fields, (len(args)+1)/2)
+	eachField(args, func(key string, value interface{}) {
+		fields[key] = value
+	})
+
+	entry := l.Logger.WithFields(fields)
+	switch level {
+	case mirbft.LevelDebug:
+		entry.Debug(text)
+	case mirbft.LevelInfo:
+		entry.Info(text)
+	case mirbft.LevelWarn:
+		entry.Warn(text)
+	default:
+		entry.Error(text)
+	}
+}
@@ -0,0 +1,55 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hyperledger-labs/mirbft"
+)
+
+// Slog implements mirbft.Logger on top of a slog.Logger.
+type Slog struct {
+	Logger *slog.Logger
+}
+
+// NewSlog adapts the given slog.Logger.
+func NewSlog(logger *slog.Logger) *Slog {
+	return &Slog{
+		Logger: logger,
+	}
+}
+
+func (s *Slog) Log(level mirbft.LogLevel, text string, args ...interface{}) {
+	var slogLevel slog.Level
+	switch level {
+	case mirbft.LevelDebug:
+		slogLevel = slog.LevelDebug
+	case mirbft.LevelInfo:
+		slogLevel = slog.LevelInfo
+	case mirbft.LevelWarn:
+		slogLevel = slog.LevelWarn
+	default:
+		slogLevel = slog.LevelError
+	}
+
+	ctx := context.Background()
+	if !s.Logger.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, (len(args)+1)/2)
+	eachField(args, func(key string, value interface{}) {
+		attrs = append(attrs, slog.Any(key, value))
+	})
+
+	s.Logger.LogAttrs(ctx, slogLevel, text, attrs...)
+}
@@ -0,0 +1,45 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/logging"
+)
+
+var _ = Describe("Slog", func() {
+	It("passes the key/value pairs as attributes", func() {
+		buffer := &bytes.Buffer{}
+		logger := logging.NewSlog(slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{
+			Level: slog.LevelInfo,
+		})))
+
+		logger.Log(mirbft.LevelDebug, "hidden")
+		logger.Log(mirbft.LevelInfo, "started", "component", "epoch_tracker", "epoch_no", 2, "digest", []byte{0xff})
+
+		var entry map[string]interface{}
+		Expect(json.Unmarshal(buffer.Bytes(), &entry)).To(Succeed())
+		delete(entry, "time")
+		Expect(entry).To(Equal(map[string]interface{}{
+			"level":     "INFO",
+			"msg":       "started",
+			"component": "epoch_tracker",
+			"epoch_no":  float64(2),
+			"digest":    "ff",
+		}))
+	})
+})
@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logging
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/hyperledger-labs/mirbft"
+)
+
+// Zap implements mirbft.Logger on top of a zap.Logger.
+type Zap struct {
+	Logger *zap.Logger
+}
+
+// NewZap adapts the given zap.Logger.
+func NewZap(logger *zap.Logger) *Zap {
+	return &Zap{
+		Logger: logger,
+	}
+}
+
+func (z *Zap) Log(level mirbft.LogLevel, text string, args ...interface{}) {
+	var log func(string, ...zap.Field)
+	switch level {
+	case mirbft.LevelDebug:
+		log = z.Logger.Debug
+	case mirbft.LevelInfo:
+		log = z.Logger.Info
+	case mirbft.LevelWarn:
+		log = z.Logger.Warn
+	default:
+		log = z.Logger.Error
+	}
+
+	fields := make([]zap.Field, 0, (len(args)+1)/2)
+	eachField(args, func(key string, value interface{}) {
+		fields = append(fields, zap.Any(key, value))
+	})
+
+	log(text, fields...)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metrics provides implementations of processor.Metrics.  The
+// Prometheus implementation accumulates the measurements in memory and
+// serves them over HTTP in the Prometheus text exposition format, so that
+// they may be scraped without any further dependencies.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+)
+
+// DefaultWALSyncBuckets are the upper bounds, in seconds, of the buckets
+// of the WAL sync latency histogram.
+var DefaultWALSyncBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+var _ processor.Metrics = &Prometheus{}
+
+// Prometheus implements processor.Metrics, and is an http.Handler which
+// serves the metrics to a Prometheus scraper.
+type Prometheus struct {
+	namespace string
+
+	mutex             sync.Mutex
+	queueDepths       map[string]float64
+	walSync           *histogram
+	hashRequests      float64
+	hashedBytes       float64
+	committedBatches  float64
+	committedRequests float64
+	epochChanges      float64
+	epoch             float64
+	checkpointLag     float64
+	bufferedMsgs      map[string]float64
+	droppedMsgs       map[string]float64
+}
+
+// NewPrometheus creates a new set of metrics, whose names are prefixed by
+// the namespace, which defaults to "mirbft".
+func NewPrometheus(namespace string) *Prometheus {
+	if namespace == "" {
+		namespace = "mirbft"
+	}
+
+	return &Prometheus{
+		namespace:    namespace,
+		queueDepths:  map[string]float64{},
+		walSync:      newHistogram(DefaultWALSyncBuckets),
+		bufferedMsgs: map[string]float64{},
+		droppedMsgs:  map[string]float64{},
+	}
+}
+
+func (p *Prometheus) QueueDepth(category string, depth int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.queueDepths[category] = float64(depth)
+}
+
+func (p *Prometheus) WALSynced(duration time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.walSync.observe(duration.Seconds())
+}
+
+func (p *Prometheus) Hashed(requests, bytes int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.hashRequests += float64(requests)
+	p.hashedBytes += float64(bytes)
+}
+
+func (p *Prometheus) Committed(requests int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.committedBatches++
+	p.committedRequests += float64(requests)
+}
+
+func (p *Prometheus) EpochStarted(epoch uint64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.epochChanges++
+	p.epoch = float64(epoch)
+}
+
+func (p *Prometheus) CheckpointLag(seqNos uint64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.checkpointLag = float64(seqNos)
+}
+
+func (p *Prometheus) MsgsBuffered(source uint64, msgs int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.bufferedMsgs[strconv.FormatUint(source, 10)] = float64(msgs)
+}
+
+func (p *Prometheus) MsgDropped(source uint64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.droppedMsgs[strconv.FormatUint(source, 10)]++
+}
+
+// ServeHTTP writes the current value of every metric.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the current value of every metric in the Prometheus
+// text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	ew := &exposition{
+		namespace: p.namespace,
+		w:         w,
+	}
+
+	ew.labeled("work_queue_depth", "gauge", "The number of items pending in each category of work.", "category", p.queueDepths)
+	ew.histogram("wal_sync_seconds", "The time taken to sync the WAL.", p.walSync)
+	ew.single("hash_requests_total", "counter", "The number of hash requests computed.", p.hashRequests)
+	ew.single("hashed_bytes_total", "counter", "The number of bytes hashed.", p.hashedBytes)
+	ew.single("committed_batches_total", "counter", "The number of batches committed to the application.", p.committedBatches)
+	ew.single("committed_requests_total", "counter", "The number of requests committed to the application.", p.committedRequests)
+	ew.single("epoch_changes_total", "counter", "The number of new epochs started.", p.epochChanges)
+	ew.single("epoch", "gauge", "The number of the most recently started epoch.", p.epoch)
+	ew.single("checkpoint_lag_seq_nos", "gauge", "The sequence numbers between the latest local checkpoint and the latest stable checkpoint.", p.checkpointLag)
+	ew.labeled("buffered_msgs", "gauge", "The number of messages from each node held for later processing.", "node", p.bufferedMsgs)
+	ew.labeled("dropped_msgs_total", "counter", "The number of messages from each node which were discarded.", "node", p.droppedMsgs)
+
+	return ew.written, ew.err
+}
+
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(value float64) {
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// exposition writes metrics in the text format, remembering
+// the first error encountered.
+type exposition struct {
+	namespace string
+	w         io.Writer
+	written   int64
+	err       error
+}
+
+func (e *exposition) printf(format string, args ...interface{}) {
+	if e.err != nil {
+		return
+	}
+
+	n, err := fmt.Fprintf(e.w, format, args...)
+	e.written += int64(n)
+	e.err = err
+}
+
+func (e *exposition) header(name, metricType, help string) string {
+	fullName := e.namespace + "_" + name
+	e.printf("# HELP %s %s\n", fullName, help)
+	e.printf("# TYPE %s %s\n", fullName, metricType)
+	return fullName
+}
+
+func (e *exposition) single(name, metricType, help string, value float64) {
+	fullName := e.header(name, metricType, help)
+	e.printf("%s %s\n", fullName, formatFloat(value))
+}
+
+func (e *exposition) labeled(name, metricType, help, label string, values map[string]float64) {
+	fullName := e.header(name, metricType, help)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		e.printf("%s{%s=%q} %s\n", fullName, label, key, formatFloat(values[key]))
+	}
+}
+
+func (e *exposition) histogram(name, help string, h *histogram) {
+	fullName := e.header(name, "histogram", help)
+	for i, bound := range h.bounds {
+		e.printf("%s_bucket{le=\"%s\"} %d\n", fullName, formatFloat(bound), h.counts[i])
+	}
+	e.printf("%s_bucket{le=\"+Inf\"} %d\n", fullName, h.count)
+	e.printf("%s_sum %s\n", fullName, formatFloat(h.sum))
+	e.printf("%s_count %d\n", fullName, h.count)
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/metrics"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+)
+
+var _ = Describe("Prometheus", func() {
+	var (
+		prometheus *metrics.Prometheus
+		server     *httptest.Server
+	)
+
+	scrape := func() string {
+		resp, err := http.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/plain"))
+
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(body)
+	}
+
+	BeforeEach(func() {
+		prometheus = metrics.NewPrometheus("")
+		server = httptest.NewServer(prometheus)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("serves counters and gauges", func() {
+		prometheus.QueueDepth(processor.WorkWAL, 3)
+		prometheus.QueueDepth(processor.WorkHash, 7)
+		prometheus.Hashed(2, 100)
+		prometheus.Hashed(1, 50)
+		prometheus.Committed(5)
+		prometheus.Committed(0)
+		prometheus.EpochStarted(4)
+		prometheus.CheckpointLag(20)
+		prometheus.MsgsBuffered(1, 12)
+		prometheus.MsgDropped(2)
+		prometheus.MsgDropped(2)
+
+		body := scrape()
+		Expect(body).To(ContainSubstring("# TYPE mirbft_work_queue_depth gauge\n"))
+		Expect(body).To(ContainSubstring("mirbft_work_queue_depth{category=\"hash\"} 7\n"))
+		Expect(body).To(ContainSubstring("mirbft_work_queue_depth{category=\"wal\"} 3\n"))
+		Expect(body).To(ContainSubstring("mirbft_hash_requests_total 3\n"))
+		Expect(body).To(ContainSubstring("mirbft_hashed_bytes_total 150\n"))
+		Expect(body).To(ContainSubstring("mirbft_committed_batches_total 2\n"))
+		Expect(body).To(ContainSubstring("mirbft_committed_requests_total 5\n"))
+		Expect(body).To(ContainSubstring("mirbft_epoch_changes_total 1\n"))
+		Expect(body).To(ContainSubstring("mirbft_epoch 4\n"))
+		Expect(body).To(ContainSubstring("mirbft_checkpoint_lag_seq_nos 20\n"))
+		Expect(body).To(ContainSubstring("mirbft_buffered_msgs{node=\"1\"} 12\n"))
+		Expect(body).To(ContainSubstring("# TYPE mirbft_dropped_msgs_total counter\n"))
+		Expect(body).To(ContainSubstring("mirbft_dropped_msgs_total{node=\"2\"} 2\n"))
+	})
+
+	It("serves the WAL sync latency as a cumulative histogram", func() {
+		prometheus.WALSynced(2 * time.Millisecond)
+		prometheus.WALSynced(20 * time.Millisecond)
+		prometheus.WALSynced(10 * time.Second)
+
+		body := scrape()
+		Expect(body).To(ContainSubstring("# TYPE mirbft_wal_sync_seconds histogram\n"))
+		Expect(body).To(ContainSubstring("mirbft_wal_sync_seconds_bucket{le=\"0.001\"} 0\n"))
+		Expect(body).To(ContainSubstring("mirbft_wal_sync_seconds_bucket{le=\"0.0025\"} 1\n"))
+		Expect(body).To(ContainSubstring("mirbft_wal_sync_seconds_bucket{le=\"0.025\"} 2\n"))
+		Expect(body).To(ContainSubstring("mirbft_wal_sync_seconds_bucket{le=\"2.5\"} 2\n"))
+		Expect(body).To(ContainSubstring("mirbft_wal_sync_seconds_bucket{le=\"+Inf\"} 3\n"))
+		Expect(body).To(ContainSubstring("mirbft_wal_sync_seconds_sum 10.022\n"))
+		Expect(body).To(ContainSubstring("mirbft_wal_sync_seconds_count 3\n"))
+	})
+
+	It("prefixes the metrics with the namespace", func() {
+		prometheus = metrics.NewPrometheus("replica")
+		server.Config.Handler = prometheus
+
+		Expect(scrape()).To(ContainSubstring("replica_epoch 0\n"))
+	})
+})
@@ -115,6 +115,8 @@ type Reconfiguration struct {
 	//	*Reconfiguration_NewClient_
 	//	*Reconfiguration_RemoveClient
 	//	*Reconfiguration_NewConfig
+	//	*Reconfiguration_AddNode
+	//	*Reconfiguration_RemoveNode
 	Type isReconfiguration_Type `protobuf_oneof:"type"`
 }
 
@@ -178,6 +180,20 @@ func (x *Reconfiguration) GetNewConfig() *NetworkState_Config {
 	return nil
 }
 
+func (x *Reconfiguration) GetAddNode() uint64 {
+	if x, ok := x.GetType().(*Reconfiguration_AddNode); ok {
+		return x.AddNode
+	}
+	return 0
+}
+
+func (x *Reconfiguration) GetRemoveNode() uint64 {
+	if x, ok := x.GetType().(*Reconfiguration_RemoveNode); ok {
+		return x.RemoveNode
+	}
+	return 0
+}
+
 type isReconfiguration_Type interface {
 	isReconfiguration_Type()
 }
@@ -194,12 +210,24 @@ type Reconfiguration_NewConfig struct {
 	NewConfig *NetworkState_Config `protobuf:"bytes,3,opt,name=new_config,json=newConfig,proto3,oneof"`
 }
 
+type Reconfiguration_AddNode struct {
+	AddNode uint64 `protobuf:"varint,4,opt,name=add_node,json=addNode,proto3,oneof"`
+}
+
+type Reconfiguration_RemoveNode struct {
+	RemoveNode uint64 `protobuf:"varint,5,opt,name=remove_node,json=removeNode,proto3,oneof"`
+}
+
 func (*Reconfiguration_NewClient_) isReconfiguration_Type() {}
 
 func (*Reconfiguration_RemoveClient) isReconfiguration_Type() {}
 
 func (*Reconfiguration_NewConfig) isReconfiguration_Type() {}
 
+func (*Reconfiguration_AddNode) isReconfiguration_Type() {}
+
+func (*Reconfiguration_RemoveNode) isReconfiguration_Type() {}
+
 // Persistent contains data that should be persited by lib user
 type Persistent struct {
 	state         protoimpl.MessageState
@@ -782,7 +810,13 @@ type Msg struct {
 	//	*Msg_FetchRequest
 	//	*Msg_ForwardRequest
 	//	*Msg_RequestAck
+	//	*Msg_FetchCheckpointChunk
+	//	*Msg_CheckpointChunk
 	Type isMsg_Type `protobuf_oneof:"type"`
+	// signature is set by the sender when a Signer is configured, and
+	// covers the deterministic serialization of this message with the
+	// signature field unset.
+	Signature []byte `protobuf:"bytes,18,opt,name=signature,proto3" json:"signature,omitempty"`
 }
 
 func (x *Msg) Reset() {
@@ -929,6 +963,27 @@ func (x *Msg) GetRequestAck() *RequestAck {
 	return nil
 }
 
+func (x *Msg) GetFetchCheckpointChunk() *FetchCheckpointChunk {
+	if x, ok := x.GetType().(*Msg_FetchCheckpointChunk); ok {
+		return x.FetchCheckpointChunk
+	}
+	return nil
+}
+
+func (x *Msg) GetCheckpointChunk() *CheckpointChunk {
+	if x, ok := x.GetType().(*Msg_CheckpointChunk); ok {
+		return x.CheckpointChunk
+	}
+	return nil
+}
+
+func (x *Msg) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
 type isMsg_Type interface {
 	isMsg_Type()
 }
@@ -993,6 +1048,14 @@ type Msg_RequestAck struct {
 	RequestAck *RequestAck `protobuf:"bytes,15,opt,name=request_ack,json=requestAck,proto3,oneof"`
 }
 
+type Msg_FetchCheckpointChunk struct {
+	FetchCheckpointChunk *FetchCheckpointChunk `protobuf:"bytes,16,opt,name=fetch_checkpoint_chunk,json=fetchCheckpointChunk,proto3,oneof"`
+}
+
+type Msg_CheckpointChunk struct {
+	CheckpointChunk *CheckpointChunk `protobuf:"bytes,17,opt,name=checkpoint_chunk,json=checkpointChunk,proto3,oneof"`
+}
+
 func (*Msg_Preprepare) isMsg_Type() {}
 
 func (*Msg_Prepare) isMsg_Type() {}
@@ -1023,6 +1086,10 @@ func (*Msg_ForwardRequest) isMsg_Type() {}
 
 func (*Msg_RequestAck) isMsg_Type() {}
 
+func (*Msg_FetchCheckpointChunk) isMsg_Type() {}
+
+func (*Msg_CheckpointChunk) isMsg_Type() {}
+
 type FetchBatch struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1196,6 +1263,152 @@ func (x *ForwardRequest) GetRequestData() []byte {
 	return nil
 }
 
+// FetchCheckpointChunk is sent by a node performing state transfer to request
+// a piece of the checkpoint data which hashes to the agreed checkpoint value.
+type FetchCheckpointChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SeqNo uint64 `protobuf:"varint,1,opt,name=seq_no,json=seqNo,proto3" json:"seq_no,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Index uint64 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+}
+
+func (x *FetchCheckpointChunk) Reset() {
+	*x = FetchCheckpointChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msgs_msgs_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchCheckpointChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchCheckpointChunk) ProtoMessage() {}
+
+func (x *FetchCheckpointChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_msgs_msgs_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchCheckpointChunk.ProtoReflect.Descriptor instead.
+func (*FetchCheckpointChunk) Descriptor() ([]byte, []int) {
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FetchCheckpointChunk) GetSeqNo() uint64 {
+	if x != nil {
+		return x.SeqNo
+	}
+	return 0
+}
+
+func (x *FetchCheckpointChunk) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *FetchCheckpointChunk) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+// CheckpointChunk is sent in response to a FetchCheckpointChunk.  If the sender
+// does not have the requested checkpoint data, total_chunks is zero.
+type CheckpointChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SeqNo       uint64 `protobuf:"varint,1,opt,name=seq_no,json=seqNo,proto3" json:"seq_no,omitempty"`
+	Value       []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Index       uint64 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	TotalChunks uint64 `protobuf:"varint,4,opt,name=total_chunks,json=totalChunks,proto3" json:"total_chunks,omitempty"`
+	Data        []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *CheckpointChunk) Reset() {
+	*x = CheckpointChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msgs_msgs_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckpointChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckpointChunk) ProtoMessage() {}
+
+func (x *CheckpointChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_msgs_msgs_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckpointChunk.ProtoReflect.Descriptor instead.
+func (*CheckpointChunk) Descriptor() ([]byte, []int) {
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CheckpointChunk) GetSeqNo() uint64 {
+	if x != nil {
+		return x.SeqNo
+	}
+	return 0
+}
+
+func (x *CheckpointChunk) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *CheckpointChunk) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CheckpointChunk) GetTotalChunks() uint64 {
+	if x != nil {
+		return x.TotalChunks
+	}
+	return 0
+}
+
+func (x *CheckpointChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 type Request struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1209,7 +1422,7 @@ type Request struct {
 func (x *Request) Reset() {
 	*x = Request{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[14]
+		mi := &file_msgs_msgs_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1222,7 +1435,7 @@ func (x *Request) String() string {
 func (*Request) ProtoMessage() {}
 
 func (x *Request) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[14]
+	mi := &file_msgs_msgs_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1235,7 +1448,7 @@ func (x *Request) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Request.ProtoReflect.Descriptor instead.
 func (*Request) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{14}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *Request) GetClientId() uint64 {
@@ -1272,7 +1485,7 @@ type RequestAck struct {
 func (x *RequestAck) Reset() {
 	*x = RequestAck{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[15]
+		mi := &file_msgs_msgs_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1285,7 +1498,7 @@ func (x *RequestAck) String() string {
 func (*RequestAck) ProtoMessage() {}
 
 func (x *RequestAck) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[15]
+	mi := &file_msgs_msgs_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1298,7 +1511,7 @@ func (x *RequestAck) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestAck.ProtoReflect.Descriptor instead.
 func (*RequestAck) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{15}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *RequestAck) GetClientId() uint64 {
@@ -1335,7 +1548,7 @@ type Preprepare struct {
 func (x *Preprepare) Reset() {
 	*x = Preprepare{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[16]
+		mi := &file_msgs_msgs_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1348,7 +1561,7 @@ func (x *Preprepare) String() string {
 func (*Preprepare) ProtoMessage() {}
 
 func (x *Preprepare) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[16]
+	mi := &file_msgs_msgs_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1361,7 +1574,7 @@ func (x *Preprepare) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Preprepare.ProtoReflect.Descriptor instead.
 func (*Preprepare) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{16}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *Preprepare) GetSeqNo() uint64 {
@@ -1398,7 +1611,7 @@ type Prepare struct {
 func (x *Prepare) Reset() {
 	*x = Prepare{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[17]
+		mi := &file_msgs_msgs_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1411,7 +1624,7 @@ func (x *Prepare) String() string {
 func (*Prepare) ProtoMessage() {}
 
 func (x *Prepare) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[17]
+	mi := &file_msgs_msgs_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1424,7 +1637,7 @@ func (x *Prepare) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Prepare.ProtoReflect.Descriptor instead.
 func (*Prepare) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{17}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *Prepare) GetSeqNo() uint64 {
@@ -1461,7 +1674,7 @@ type Commit struct {
 func (x *Commit) Reset() {
 	*x = Commit{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[18]
+		mi := &file_msgs_msgs_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1474,7 +1687,7 @@ func (x *Commit) String() string {
 func (*Commit) ProtoMessage() {}
 
 func (x *Commit) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[18]
+	mi := &file_msgs_msgs_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1487,7 +1700,7 @@ func (x *Commit) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Commit.ProtoReflect.Descriptor instead.
 func (*Commit) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{18}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *Commit) GetSeqNo() uint64 {
@@ -1523,7 +1736,7 @@ type Checkpoint struct {
 func (x *Checkpoint) Reset() {
 	*x = Checkpoint{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[19]
+		mi := &file_msgs_msgs_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1536,7 +1749,7 @@ func (x *Checkpoint) String() string {
 func (*Checkpoint) ProtoMessage() {}
 
 func (x *Checkpoint) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[19]
+	mi := &file_msgs_msgs_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1549,7 +1762,7 @@ func (x *Checkpoint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Checkpoint.ProtoReflect.Descriptor instead.
 func (*Checkpoint) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{19}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *Checkpoint) GetSeqNo() uint64 {
@@ -1577,7 +1790,7 @@ type Suspect struct {
 func (x *Suspect) Reset() {
 	*x = Suspect{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[20]
+		mi := &file_msgs_msgs_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1590,7 +1803,7 @@ func (x *Suspect) String() string {
 func (*Suspect) ProtoMessage() {}
 
 func (x *Suspect) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[20]
+	mi := &file_msgs_msgs_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1603,7 +1816,7 @@ func (x *Suspect) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Suspect.ProtoReflect.Descriptor instead.
 func (*Suspect) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{20}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *Suspect) GetEpoch() uint64 {
@@ -1638,7 +1851,7 @@ type EpochChange struct {
 func (x *EpochChange) Reset() {
 	*x = EpochChange{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[21]
+		mi := &file_msgs_msgs_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1651,7 +1864,7 @@ func (x *EpochChange) String() string {
 func (*EpochChange) ProtoMessage() {}
 
 func (x *EpochChange) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[21]
+	mi := &file_msgs_msgs_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1664,7 +1877,7 @@ func (x *EpochChange) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EpochChange.ProtoReflect.Descriptor instead.
 func (*EpochChange) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{21}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *EpochChange) GetNewEpoch() uint64 {
@@ -1715,7 +1928,7 @@ type EpochChangeAck struct {
 func (x *EpochChangeAck) Reset() {
 	*x = EpochChangeAck{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[22]
+		mi := &file_msgs_msgs_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1728,7 +1941,7 @@ func (x *EpochChangeAck) String() string {
 func (*EpochChangeAck) ProtoMessage() {}
 
 func (x *EpochChangeAck) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[22]
+	mi := &file_msgs_msgs_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1741,7 +1954,7 @@ func (x *EpochChangeAck) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EpochChangeAck.ProtoReflect.Descriptor instead.
 func (*EpochChangeAck) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{22}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *EpochChangeAck) GetOriginator() uint64 {
@@ -1772,7 +1985,7 @@ type EpochConfig struct {
 func (x *EpochConfig) Reset() {
 	*x = EpochConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[23]
+		mi := &file_msgs_msgs_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1785,7 +1998,7 @@ func (x *EpochConfig) String() string {
 func (*EpochConfig) ProtoMessage() {}
 
 func (x *EpochConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[23]
+	mi := &file_msgs_msgs_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1798,7 +2011,7 @@ func (x *EpochConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EpochConfig.ProtoReflect.Descriptor instead.
 func (*EpochConfig) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{23}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *EpochConfig) GetNumber() uint64 {
@@ -1839,7 +2052,7 @@ type NewEpochConfig struct {
 func (x *NewEpochConfig) Reset() {
 	*x = NewEpochConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[24]
+		mi := &file_msgs_msgs_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1852,7 +2065,7 @@ func (x *NewEpochConfig) String() string {
 func (*NewEpochConfig) ProtoMessage() {}
 
 func (x *NewEpochConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[24]
+	mi := &file_msgs_msgs_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1865,7 +2078,7 @@ func (x *NewEpochConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NewEpochConfig.ProtoReflect.Descriptor instead.
 func (*NewEpochConfig) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{24}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *NewEpochConfig) GetConfig() *EpochConfig {
@@ -1913,7 +2126,7 @@ type NewEpoch struct {
 func (x *NewEpoch) Reset() {
 	*x = NewEpoch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[25]
+		mi := &file_msgs_msgs_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1926,7 +2139,7 @@ func (x *NewEpoch) String() string {
 func (*NewEpoch) ProtoMessage() {}
 
 func (x *NewEpoch) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[25]
+	mi := &file_msgs_msgs_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1939,7 +2152,7 @@ func (x *NewEpoch) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NewEpoch.ProtoReflect.Descriptor instead.
 func (*NewEpoch) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{25}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *NewEpoch) GetNewConfig() *NewEpochConfig {
@@ -2015,7 +2228,7 @@ type NetworkState_Config struct {
 func (x *NetworkState_Config) Reset() {
 	*x = NetworkState_Config{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[26]
+		mi := &file_msgs_msgs_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2028,7 +2241,7 @@ func (x *NetworkState_Config) String() string {
 func (*NetworkState_Config) ProtoMessage() {}
 
 func (x *NetworkState_Config) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[26]
+	mi := &file_msgs_msgs_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2111,7 +2324,7 @@ type NetworkState_Client struct {
 func (x *NetworkState_Client) Reset() {
 	*x = NetworkState_Client{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[27]
+		mi := &file_msgs_msgs_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2124,7 +2337,7 @@ func (x *NetworkState_Client) String() string {
 func (*NetworkState_Client) ProtoMessage() {}
 
 func (x *NetworkState_Client) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[27]
+	mi := &file_msgs_msgs_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2187,7 +2400,7 @@ type Reconfiguration_NewClient struct {
 func (x *Reconfiguration_NewClient) Reset() {
 	*x = Reconfiguration_NewClient{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[28]
+		mi := &file_msgs_msgs_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2200,7 +2413,7 @@ func (x *Reconfiguration_NewClient) String() string {
 func (*Reconfiguration_NewClient) ProtoMessage() {}
 
 func (x *Reconfiguration_NewClient) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[28]
+	mi := &file_msgs_msgs_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2243,7 +2456,7 @@ type EpochChange_SetEntry struct {
 func (x *EpochChange_SetEntry) Reset() {
 	*x = EpochChange_SetEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[29]
+		mi := &file_msgs_msgs_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2256,7 +2469,7 @@ func (x *EpochChange_SetEntry) String() string {
 func (*EpochChange_SetEntry) ProtoMessage() {}
 
 func (x *EpochChange_SetEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[29]
+	mi := &file_msgs_msgs_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2269,7 +2482,7 @@ func (x *EpochChange_SetEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EpochChange_SetEntry.ProtoReflect.Descriptor instead.
 func (*EpochChange_SetEntry) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{21, 0}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{23, 0}
 }
 
 func (x *EpochChange_SetEntry) GetEpoch() uint64 {
@@ -2305,7 +2518,7 @@ type NewEpoch_RemoteEpochChange struct {
 func (x *NewEpoch_RemoteEpochChange) Reset() {
 	*x = NewEpoch_RemoteEpochChange{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msgs_msgs_proto_msgTypes[30]
+		mi := &file_msgs_msgs_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2318,7 +2531,7 @@ func (x *NewEpoch_RemoteEpochChange) String() string {
 func (*NewEpoch_RemoteEpochChange) ProtoMessage() {}
 
 func (x *NewEpoch_RemoteEpochChange) ProtoReflect() protoreflect.Message {
-	mi := &file_msgs_msgs_proto_msgTypes[30]
+	mi := &file_msgs_msgs_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2331,7 +2544,7 @@ func (x *NewEpoch_RemoteEpochChange) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NewEpoch_RemoteEpochChange.ProtoReflect.Descriptor instead.
 func (*NewEpoch_RemoteEpochChange) Descriptor() ([]byte, []int) {
-	return file_msgs_msgs_proto_rawDescGZIP(), []int{25, 0}
+	return file_msgs_msgs_proto_rawDescGZIP(), []int{27, 0}
 }
 
 func (x *NewEpoch_RemoteEpochChange) GetNodeId() uint64 {
@@ -2390,8 +2603,8 @@ var file_msgs_msgs_proto_rawDesc = []byte{
 	0x61, 0x72, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6c, 0x6f, 0x77, 0x57, 0x61,
 	0x74, 0x65, 0x72, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x69,
 	0x74, 0x74, 0x65, 0x64, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x22, 0xf1,
-	0x01, 0x0a, 0x0f, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x22, 0xb1,
+	0x02, 0x0a, 0x0f, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
 	0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x0a, 0x6e, 0x65, 0x77, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x52, 0x65,
 	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x4e, 0x65,
@@ -2402,7 +2615,11 @@ var file_msgs_msgs_proto_rawDesc = []byte{
 	0x65, 0x77, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x19, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x74,
 	0x61, 0x74, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x09, 0x6e, 0x65,
-	0x77, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x1a, 0x31, 0x0a, 0x09, 0x4e, 0x65, 0x77, 0x43, 0x6c,
+	0x77, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1b, 0x0a, 0x08, 0x61, 0x64, 0x64, 0x5f, 0x6e,
+	0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x07, 0x61, 0x64, 0x64,
+	0x4e, 0x6f, 0x64, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x6e,
+	0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x1a, 0x31, 0x0a, 0x09, 0x4e, 0x65, 0x77, 0x43, 0x6c,
 	0x69, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
 	0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x0d, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79,
@@ -2463,7 +2680,7 @@ var file_msgs_msgs_proto_rawDesc = []byte{
 	0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x12, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53,
 	0x74, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x22, 0xb9, 0x06, 0x0a, 0x03, 0x4d, 0x73, 0x67, 0x12, 0x32, 0x0a, 0x0a, 0x70, 0x72,
+	0x74, 0x65, 0x22, 0xef, 0x07, 0x0a, 0x03, 0x4d, 0x73, 0x67, 0x12, 0x32, 0x0a, 0x0a, 0x70, 0x72,
 	0x65, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
 	0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65,
 	0x48, 0x00, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x12, 0x29,
@@ -2514,115 +2731,142 @@ var file_msgs_msgs_proto_rawDesc = []byte{
 	0x74, 0x12, 0x33, 0x0a, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x6b,
 	0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x3b,
-	0x0a, 0x0a, 0x46, 0x65, 0x74, 0x63, 0x68, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x15, 0x0a, 0x06,
-	0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65,
-	0x71, 0x4e, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x72, 0x0a, 0x0c, 0x46,
-	0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x15, 0x0a, 0x06, 0x73,
-	0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71,
-	0x4e, 0x6f, 0x12, 0x33, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x63,
-	0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x52, 0x0b, 0x72, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
-	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22,
-	0x66, 0x0a, 0x0e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x31, 0x0a, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x6b,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x52, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x41, 0x63, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f,
-	0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x72, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x22, 0x51, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12,
-	0x15, 0x0a, 0x06, 0x72, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x05, 0x72, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x58, 0x0a, 0x0a, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x6c, 0x69,
-	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x15, 0x0a, 0x06, 0x72, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x72, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x16, 0x0a, 0x06,
-	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69,
-	0x67, 0x65, 0x73, 0x74, 0x22, 0x61, 0x0a, 0x0a, 0x50, 0x72, 0x65, 0x70, 0x72, 0x65, 0x70, 0x61,
-	0x72, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f,
-	0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12,
-	0x26, 0x0a, 0x05, 0x62, 0x61, 0x74, 0x63, 0x68, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10,
+	0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x12, 0x52, 0x0a, 0x16, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x46, 0x65,
+	0x74, 0x63, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x48, 0x00, 0x52, 0x14, 0x66, 0x65, 0x74, 0x63, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x42, 0x0a, 0x10, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x11,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x00, 0x52, 0x0f, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x06, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x22, 0x3b, 0x0a, 0x0a, 0x46, 0x65, 0x74, 0x63, 0x68, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x22, 0x72, 0x0a, 0x0c, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x33, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10,
 	0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b,
-	0x52, 0x05, 0x62, 0x61, 0x74, 0x63, 0x68, 0x22, 0x4e, 0x0a, 0x07, 0x50, 0x72, 0x65, 0x70, 0x61,
-	0x72, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f,
-	0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12,
-	0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x4d, 0x0a, 0x06, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
-	0x74, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x66, 0x0a, 0x0e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x5f, 0x61, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d,
+	0x73, 0x67, 0x73, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x52, 0x0a,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x61, 0x74, 0x61, 0x22, 0x59, 0x0a,
+	0x14, 0x46, 0x65, 0x74, 0x63, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x8b, 0x01, 0x0a, 0x0f, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x15, 0x0a, 0x06,
+	0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65,
+	0x71, 0x4e, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x51, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x15,
+	0x0a, 0x06, 0x72, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05,
+	0x72, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x58, 0x0a, 0x0a, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x49, 0x64, 0x12, 0x15, 0x0a, 0x06, 0x72, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x72, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x22, 0x61, 0x0a, 0x0a, 0x50, 0x72, 0x65, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72,
+	0x65, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x26,
+	0x0a, 0x05, 0x62, 0x61, 0x74, 0x63, 0x68, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x6d, 0x73, 0x67, 0x73, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x52,
+	0x05, 0x62, 0x61, 0x74, 0x63, 0x68, 0x22, 0x4e, 0x0a, 0x07, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72,
+	0x65, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63,
 	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x16,
 	0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
-	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x39, 0x0a, 0x0a, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70,
-	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x22, 0x1f, 0x0a, 0x07, 0x53, 0x75, 0x73, 0x70, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05,
-	0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f,
-	0x63, 0x68, 0x22, 0x91, 0x02, 0x0a, 0x0b, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e,
-	0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x5f, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6e, 0x65, 0x77, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12,
-	0x32, 0x0a, 0x0b, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x43, 0x68, 0x65, 0x63,
-	0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x0b, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69,
-	0x6e, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x70, 0x5f, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x03,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x4d, 0x0a, 0x06, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74,
+	0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x16, 0x0a,
+	0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x39, 0x0a, 0x0a, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x22, 0x1f, 0x0a, 0x07, 0x53, 0x75, 0x73, 0x70, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63,
+	0x68, 0x22, 0x91, 0x02, 0x0a, 0x0b, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x5f, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6e, 0x65, 0x77, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x32,
+	0x0a, 0x0b, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x0b, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x70, 0x5f, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x70,
+	0x53, 0x65, 0x74, 0x12, 0x2f, 0x0a, 0x05, 0x71, 0x5f, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x03,
 	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43,
 	0x68, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04,
-	0x70, 0x53, 0x65, 0x74, 0x12, 0x2f, 0x0a, 0x05, 0x71, 0x5f, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68,
-	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x04, 0x71, 0x53, 0x65, 0x74, 0x1a, 0x4f, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e,
-	0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x16,
-	0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
-	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x66, 0x0a, 0x0e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43,
-	0x68, 0x61, 0x6e, 0x67, 0x65, 0x41, 0x63, 0x6b, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x72, 0x69, 0x67,
-	0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6f, 0x72,
-	0x69, 0x67, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x34, 0x0a, 0x0c, 0x65, 0x70, 0x6f, 0x63,
-	0x68, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
-	0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67,
-	0x65, 0x52, 0x0b, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x22, 0x6e,
-	0x0a, 0x0b, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a,
-	0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6e,
-	0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12,
-	0x2d, 0x0a, 0x12, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x70, 0x6c, 0x61,
-	0x6e, 0x6e, 0x65, 0x64, 0x45, 0x78, 0x70, 0x69, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xab,
-	0x01, 0x0a, 0x0e, 0x4e, 0x65, 0x77, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x12, 0x29, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x41, 0x0a, 0x13,
-	0x73, 0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f,
-	0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x73, 0x67, 0x73,
-	0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x12, 0x73, 0x74, 0x61,
-	0x72, 0x74, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12,
-	0x2b, 0x0a, 0x11, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x65, 0x70, 0x72, 0x65, 0x70,
-	0x61, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x10, 0x66, 0x69, 0x6e, 0x61,
-	0x6c, 0x50, 0x72, 0x65, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x73, 0x22, 0xcc, 0x01, 0x0a,
-	0x08, 0x4e, 0x65, 0x77, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x33, 0x0a, 0x0a, 0x6e, 0x65, 0x77,
-	0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
-	0x6d, 0x73, 0x67, 0x73, 0x2e, 0x4e, 0x65, 0x77, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x52, 0x09, 0x6e, 0x65, 0x77, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x45,
-	0x0a, 0x0d, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x4e, 0x65, 0x77,
-	0x45, 0x70, 0x6f, 0x63, 0x68, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x70, 0x6f, 0x63,
-	0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0c, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68,
-	0x61, 0x6e, 0x67, 0x65, 0x73, 0x1a, 0x44, 0x0a, 0x11, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45,
-	0x70, 0x6f, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f,
-	0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6e, 0x6f, 0x64,
-	0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x42, 0x23, 0x5a, 0x21, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x49, 0x42, 0x4d, 0x2f, 0x6d, 0x69,
-	0x72, 0x62, 0x66, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x2f, 0x6d, 0x73, 0x67, 0x73,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x71, 0x53, 0x65, 0x74, 0x1a, 0x4f, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x65, 0x71, 0x5f, 0x6e, 0x6f,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x73, 0x65, 0x71, 0x4e, 0x6f, 0x12, 0x16, 0x0a,
+	0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x66, 0x0a, 0x0e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x41, 0x63, 0x6b, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x34, 0x0a, 0x0c, 0x65, 0x70, 0x6f, 0x63, 0x68,
+	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x6d, 0x73, 0x67, 0x73, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x52, 0x0b, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x22, 0x6e, 0x0a,
+	0x0b, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x2d,
+	0x0a, 0x12, 0x70, 0x6c, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x70, 0x6c, 0x61, 0x6e,
+	0x6e, 0x65, 0x64, 0x45, 0x78, 0x70, 0x69, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xab, 0x01,
+	0x0a, 0x0e, 0x4e, 0x65, 0x77, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x29, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x41, 0x0a, 0x13, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x12, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x2b,
+	0x0a, 0x11, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x65, 0x70, 0x72, 0x65, 0x70, 0x61,
+	0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c,
+	0x50, 0x72, 0x65, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x73, 0x22, 0xcc, 0x01, 0x0a, 0x08,
+	0x4e, 0x65, 0x77, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x33, 0x0a, 0x0a, 0x6e, 0x65, 0x77, 0x5f,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d,
+	0x73, 0x67, 0x73, 0x2e, 0x4e, 0x65, 0x77, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x09, 0x6e, 0x65, 0x77, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x45, 0x0a,
+	0x0d, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6d, 0x73, 0x67, 0x73, 0x2e, 0x4e, 0x65, 0x77, 0x45,
+	0x70, 0x6f, 0x63, 0x68, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x70, 0x6f, 0x63, 0x68,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0c, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x1a, 0x44, 0x0a, 0x11, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x70,
+	0x6f, 0x63, 0x68, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65,
+	0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x68, 0x79, 0x70, 0x65, 0x72, 0x6c, 0x65,
+	0x64, 0x67, 0x65, 0x72, 0x2d, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x6d, 0x69, 0x72, 0x62, 0x66, 0x74,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x2f, 0x6d, 0x73, 0x67, 0x73, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2637,7 +2881,7 @@ func file_msgs_msgs_proto_rawDescGZIP() []byte {
 	return file_msgs_msgs_proto_rawDescData
 }
 
-var file_msgs_msgs_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_msgs_msgs_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_msgs_msgs_proto_goTypes = []interface{}{
 	(*NetworkState)(nil),               // 0: msgs.NetworkState
 	(*Reconfiguration)(nil),            // 1: msgs.Reconfiguration
@@ -2653,30 +2897,32 @@ var file_msgs_msgs_proto_goTypes = []interface{}{
 	(*FetchBatch)(nil),                 // 11: msgs.FetchBatch
 	(*ForwardBatch)(nil),               // 12: msgs.ForwardBatch
 	(*ForwardRequest)(nil),             // 13: msgs.ForwardRequest
-	(*Request)(nil),                    // 14: msgs.Request
-	(*RequestAck)(nil),                 // 15: msgs.RequestAck
-	(*Preprepare)(nil),                 // 16: msgs.Preprepare
-	(*Prepare)(nil),                    // 17: msgs.Prepare
-	(*Commit)(nil),                     // 18: msgs.Commit
-	(*Checkpoint)(nil),                 // 19: msgs.Checkpoint
-	(*Suspect)(nil),                    // 20: msgs.Suspect
-	(*EpochChange)(nil),                // 21: msgs.EpochChange
-	(*EpochChangeAck)(nil),             // 22: msgs.EpochChangeAck
-	(*EpochConfig)(nil),                // 23: msgs.EpochConfig
-	(*NewEpochConfig)(nil),             // 24: msgs.NewEpochConfig
-	(*NewEpoch)(nil),                   // 25: msgs.NewEpoch
-	(*NetworkState_Config)(nil),        // 26: msgs.NetworkState.Config
-	(*NetworkState_Client)(nil),        // 27: msgs.NetworkState.Client
-	(*Reconfiguration_NewClient)(nil),  // 28: msgs.Reconfiguration.NewClient
-	(*EpochChange_SetEntry)(nil),       // 29: msgs.EpochChange.SetEntry
-	(*NewEpoch_RemoteEpochChange)(nil), // 30: msgs.NewEpoch.RemoteEpochChange
+	(*FetchCheckpointChunk)(nil),       // 14: msgs.FetchCheckpointChunk
+	(*CheckpointChunk)(nil),            // 15: msgs.CheckpointChunk
+	(*Request)(nil),                    // 16: msgs.Request
+	(*RequestAck)(nil),                 // 17: msgs.RequestAck
+	(*Preprepare)(nil),                 // 18: msgs.Preprepare
+	(*Prepare)(nil),                    // 19: msgs.Prepare
+	(*Commit)(nil),                     // 20: msgs.Commit
+	(*Checkpoint)(nil),                 // 21: msgs.Checkpoint
+	(*Suspect)(nil),                    // 22: msgs.Suspect
+	(*EpochChange)(nil),                // 23: msgs.EpochChange
+	(*EpochChangeAck)(nil),             // 24: msgs.EpochChangeAck
+	(*EpochConfig)(nil),                // 25: msgs.EpochConfig
+	(*NewEpochConfig)(nil),             // 26: msgs.NewEpochConfig
+	(*NewEpoch)(nil),                   // 27: msgs.NewEpoch
+	(*NetworkState_Config)(nil),        // 28: msgs.NetworkState.Config
+	(*NetworkState_Client)(nil),        // 29: msgs.NetworkState.Client
+	(*Reconfiguration_NewClient)(nil),  // 30: msgs.Reconfiguration.NewClient
+	(*EpochChange_SetEntry)(nil),       // 31: msgs.EpochChange.SetEntry
+	(*NewEpoch_RemoteEpochChange)(nil), // 32: msgs.NewEpoch.RemoteEpochChange
 }
 var file_msgs_msgs_proto_depIdxs = []int32{
-	26, // 0: msgs.NetworkState.config:type_name -> msgs.NetworkState.Config
-	27, // 1: msgs.NetworkState.clients:type_name -> msgs.NetworkState.Client
+	28, // 0: msgs.NetworkState.config:type_name -> msgs.NetworkState.Config
+	29, // 1: msgs.NetworkState.clients:type_name -> msgs.NetworkState.Client
 	1,  // 2: msgs.NetworkState.pending_reconfigurations:type_name -> msgs.Reconfiguration
-	28, // 3: msgs.Reconfiguration.new_client:type_name -> msgs.Reconfiguration.NewClient
-	26, // 4: msgs.Reconfiguration.new_config:type_name -> msgs.NetworkState.Config
+	30, // 3: msgs.Reconfiguration.new_client:type_name -> msgs.Reconfiguration.NewClient
+	28, // 4: msgs.Reconfiguration.new_config:type_name -> msgs.NetworkState.Config
 	7,  // 5: msgs.Persistent.q_entry:type_name -> msgs.QEntry
 	8,  // 6: msgs.Persistent.p_entry:type_name -> msgs.PEntry
 	9,  // 7: msgs.Persistent.c_entry:type_name -> msgs.CEntry
@@ -2684,42 +2930,44 @@ var file_msgs_msgs_proto_depIdxs = []int32{
 	4,  // 9: msgs.Persistent.f_entry:type_name -> msgs.FEntry
 	5,  // 10: msgs.Persistent.e_c_entry:type_name -> msgs.ECEntry
 	6,  // 11: msgs.Persistent.t_entry:type_name -> msgs.TEntry
-	20, // 12: msgs.Persistent.suspect:type_name -> msgs.Suspect
-	23, // 13: msgs.NEntry.epoch_config:type_name -> msgs.EpochConfig
-	23, // 14: msgs.FEntry.ends_epoch_config:type_name -> msgs.EpochConfig
-	15, // 15: msgs.QEntry.requests:type_name -> msgs.RequestAck
+	22, // 12: msgs.Persistent.suspect:type_name -> msgs.Suspect
+	25, // 13: msgs.NEntry.epoch_config:type_name -> msgs.EpochConfig
+	25, // 14: msgs.FEntry.ends_epoch_config:type_name -> msgs.EpochConfig
+	17, // 15: msgs.QEntry.requests:type_name -> msgs.RequestAck
 	0,  // 16: msgs.CEntry.network_state:type_name -> msgs.NetworkState
-	16, // 17: msgs.Msg.preprepare:type_name -> msgs.Preprepare
-	17, // 18: msgs.Msg.prepare:type_name -> msgs.Prepare
-	18, // 19: msgs.Msg.commit:type_name -> msgs.Commit
-	19, // 20: msgs.Msg.checkpoint:type_name -> msgs.Checkpoint
-	20, // 21: msgs.Msg.suspect:type_name -> msgs.Suspect
-	21, // 22: msgs.Msg.epoch_change:type_name -> msgs.EpochChange
-	22, // 23: msgs.Msg.epoch_change_ack:type_name -> msgs.EpochChangeAck
-	25, // 24: msgs.Msg.new_epoch:type_name -> msgs.NewEpoch
-	24, // 25: msgs.Msg.new_epoch_echo:type_name -> msgs.NewEpochConfig
-	24, // 26: msgs.Msg.new_epoch_ready:type_name -> msgs.NewEpochConfig
+	18, // 17: msgs.Msg.preprepare:type_name -> msgs.Preprepare
+	19, // 18: msgs.Msg.prepare:type_name -> msgs.Prepare
+	20, // 19: msgs.Msg.commit:type_name -> msgs.Commit
+	21, // 20: msgs.Msg.checkpoint:type_name -> msgs.Checkpoint
+	22, // 21: msgs.Msg.suspect:type_name -> msgs.Suspect
+	23, // 22: msgs.Msg.epoch_change:type_name -> msgs.EpochChange
+	24, // 23: msgs.Msg.epoch_change_ack:type_name -> msgs.EpochChangeAck
+	27, // 24: msgs.Msg.new_epoch:type_name -> msgs.NewEpoch
+	26, // 25: msgs.Msg.new_epoch_echo:type_name -> msgs.NewEpochConfig
+	26, // 26: msgs.Msg.new_epoch_ready:type_name -> msgs.NewEpochConfig
 	11, // 27: msgs.Msg.fetch_batch:type_name -> msgs.FetchBatch
 	12, // 28: msgs.Msg.forward_batch:type_name -> msgs.ForwardBatch
-	15, // 29: msgs.Msg.fetch_request:type_name -> msgs.RequestAck
+	17, // 29: msgs.Msg.fetch_request:type_name -> msgs.RequestAck
 	13, // 30: msgs.Msg.forward_request:type_name -> msgs.ForwardRequest
-	15, // 31: msgs.Msg.request_ack:type_name -> msgs.RequestAck
-	15, // 32: msgs.ForwardBatch.request_acks:type_name -> msgs.RequestAck
-	15, // 33: msgs.ForwardRequest.request_ack:type_name -> msgs.RequestAck
-	15, // 34: msgs.Preprepare.batch:type_name -> msgs.RequestAck
-	19, // 35: msgs.EpochChange.checkpoints:type_name -> msgs.Checkpoint
-	29, // 36: msgs.EpochChange.p_set:type_name -> msgs.EpochChange.SetEntry
-	29, // 37: msgs.EpochChange.q_set:type_name -> msgs.EpochChange.SetEntry
-	21, // 38: msgs.EpochChangeAck.epoch_change:type_name -> msgs.EpochChange
-	23, // 39: msgs.NewEpochConfig.config:type_name -> msgs.EpochConfig
-	19, // 40: msgs.NewEpochConfig.starting_checkpoint:type_name -> msgs.Checkpoint
-	24, // 41: msgs.NewEpoch.new_config:type_name -> msgs.NewEpochConfig
-	30, // 42: msgs.NewEpoch.epoch_changes:type_name -> msgs.NewEpoch.RemoteEpochChange
-	43, // [43:43] is the sub-list for method output_type
-	43, // [43:43] is the sub-list for method input_type
-	43, // [43:43] is the sub-list for extension type_name
-	43, // [43:43] is the sub-list for extension extendee
-	0,  // [0:43] is the sub-list for field type_name
+	17, // 31: msgs.Msg.request_ack:type_name -> msgs.RequestAck
+	14, // 32: msgs.Msg.fetch_checkpoint_chunk:type_name -> msgs.FetchCheckpointChunk
+	15, // 33: msgs.Msg.checkpoint_chunk:type_name -> msgs.CheckpointChunk
+	17, // 34: msgs.ForwardBatch.request_acks:type_name -> msgs.RequestAck
+	17, // 35: msgs.ForwardRequest.request_ack:type_name -> msgs.RequestAck
+	17, // 36: msgs.Preprepare.batch:type_name -> msgs.RequestAck
+	21, // 37: msgs.EpochChange.checkpoints:type_name -> msgs.Checkpoint
+	31, // 38: msgs.EpochChange.p_set:type_name -> msgs.EpochChange.SetEntry
+	31, // 39: msgs.EpochChange.q_set:type_name -> msgs.EpochChange.SetEntry
+	23, // 40: msgs.EpochChangeAck.epoch_change:type_name -> msgs.EpochChange
+	25, // 41: msgs.NewEpochConfig.config:type_name -> msgs.EpochConfig
+	21, // 42: msgs.NewEpochConfig.starting_checkpoint:type_name -> msgs.Checkpoint
+	26, // 43: msgs.NewEpoch.new_config:type_name -> msgs.NewEpochConfig
+	32, // 44: msgs.NewEpoch.epoch_changes:type_name -> msgs.NewEpoch.RemoteEpochChange
+	45, // [45:45] is the sub-list for method output_type
+	45, // [45:45] is the sub-list for method input_type
+	45, // [45:45] is the sub-list for extension type_name
+	45, // [45:45] is the sub-list for extension extendee
+	0,  // [0:45] is the sub-list for field type_name
 }
 
 func init() { file_msgs_msgs_proto_init() }
@@ -2897,7 +3145,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Request); i {
+			switch v := v.(*FetchCheckpointChunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2909,7 +3157,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RequestAck); i {
+			switch v := v.(*CheckpointChunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2921,7 +3169,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Preprepare); i {
+			switch v := v.(*Request); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2933,7 +3181,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Prepare); i {
+			switch v := v.(*RequestAck); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2945,7 +3193,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Commit); i {
+			switch v := v.(*Preprepare); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2957,7 +3205,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Checkpoint); i {
+			switch v := v.(*Prepare); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2969,7 +3217,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Suspect); i {
+			switch v := v.(*Commit); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2981,7 +3229,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EpochChange); i {
+			switch v := v.(*Checkpoint); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2993,7 +3241,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EpochChangeAck); i {
+			switch v := v.(*Suspect); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3005,7 +3253,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EpochConfig); i {
+			switch v := v.(*EpochChange); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3017,7 +3265,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NewEpochConfig); i {
+			switch v := v.(*EpochChangeAck); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3029,7 +3277,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NewEpoch); i {
+			switch v := v.(*EpochConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3041,7 +3289,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NetworkState_Config); i {
+			switch v := v.(*NewEpochConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3053,7 +3301,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NetworkState_Client); i {
+			switch v := v.(*NewEpoch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3065,7 +3313,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Reconfiguration_NewClient); i {
+			switch v := v.(*NetworkState_Config); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3077,7 +3325,7 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EpochChange_SetEntry); i {
+			switch v := v.(*NetworkState_Client); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3089,6 +3337,30 @@ func file_msgs_msgs_proto_init() {
 			}
 		}
 		file_msgs_msgs_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Reconfiguration_NewClient); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msgs_msgs_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EpochChange_SetEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msgs_msgs_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NewEpoch_RemoteEpochChange); i {
 			case 0:
 				return &v.state
@@ -3105,6 +3377,8 @@ func file_msgs_msgs_proto_init() {
 		(*Reconfiguration_NewClient_)(nil),
 		(*Reconfiguration_RemoveClient)(nil),
 		(*Reconfiguration_NewConfig)(nil),
+		(*Reconfiguration_AddNode)(nil),
+		(*Reconfiguration_RemoveNode)(nil),
 	}
 	file_msgs_msgs_proto_msgTypes[2].OneofWrappers = []interface{}{
 		(*Persistent_QEntry)(nil),
@@ -3132,6 +3406,8 @@ func file_msgs_msgs_proto_init() {
 		(*Msg_FetchRequest)(nil),
 		(*Msg_ForwardRequest)(nil),
 		(*Msg_RequestAck)(nil),
+		(*Msg_FetchCheckpointChunk)(nil),
+		(*Msg_CheckpointChunk)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -3139,7 +3415,7 @@ func file_msgs_msgs_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_msgs_msgs_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   31,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
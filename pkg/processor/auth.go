@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+)
+
+// Signer signs the serialized form of outgoing messages, so that
+// their receivers may authenticate them.
+type Signer interface {
+	Sign(data []byte) ([]byte, error)
+}
+
+// Verifier authenticates the serialized form of messages received
+// from other nodes, returning an error if the signature is not a valid
+// signature by the given source.
+type Verifier interface {
+	Verify(source uint64, data []byte, signature []byte) error
+}
+
+func signedPayload(msg *msgs.Msg) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(&msgs.Msg{
+		Type: msg.Type,
+	})
+}
+
+// signMsg returns a copy of the message with its signature set.  The
+// original message is not modified, as it may be referenced elsewhere.
+func signMsg(signer Signer, msg *msgs.Msg) (*msgs.Msg, error) {
+	payload, err := signedPayload(msg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not serialize message for signing")
+	}
+
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not sign message")
+	}
+
+	return &msgs.Msg{
+		Type:      msg.Type,
+		Signature: signature,
+	}, nil
+}
+
+func verifyMsg(verifier Verifier, source uint64, msg *msgs.Msg) error {
+	if len(msg.Signature) == 0 {
+		return errors.Errorf("message is not signed")
+	}
+
+	payload, err := signedPayload(msg)
+	if err != nil {
+		return errors.WithMessage(err, "could not serialize message for verification")
+	}
+
+	return verifier.Verify(source, payload, msg.Signature)
+}
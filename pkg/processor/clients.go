@@ -9,6 +9,7 @@ package processor
 import (
 	"bytes"
 	"container/list"
+	"math"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -20,6 +21,15 @@ import (
 
 var ErrClientNotExist error = errors.New("client does not exist")
 
+// ErrReqNoOutOfWindow is returned by Propose for request numbers beyond
+// the client's current request window.  They may be proposed again once
+// earlier requests commit and the window advances.
+var ErrReqNoOutOfWindow error = errors.New("request number outside of client window")
+
+// errInvalidForward is returned for requests forwarded by a replica which
+// do not match their digest, or which fail validation.
+var errInvalidForward error = errors.New("invalid forwarded request")
+
 func (cs *Clients) Client(clientID uint64) *Client {
 	cs.mutex.Lock()
 	defer cs.mutex.Unlock()
@@ -29,15 +39,41 @@ func (cs *Clients) Client(clientID uint64) *Client {
 
 	c, ok := cs.clients[clientID]
 	if !ok {
-		c = newClient(clientID, cs.Hasher, cs.RequestStore)
+		c = newClient(clientID, cs.Hasher, cs.RequestStore, cs.RequestValidator, cs.RateLimit)
 		cs.clients[clientID] = c
 	}
 	return c
 }
 
+// existingClient returns the client if the state machine has allocated
+// its requests, as it does for every client in the network configuration,
+// or nil otherwise.  Unlike Client, it never adds a client.
+func (cs *Clients) existingClient(clientID uint64) *Client {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	c, ok := cs.clients[clientID]
+	if !ok {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.requests.Len() == 0 {
+		return nil
+	}
+
+	return c
+}
+
 type Clients struct {
-	Hasher       Hasher
-	RequestStore RequestStore
+	Hasher           Hasher
+	RequestStore     RequestStore
+	RequestValidator RequestValidator
+
+	// RateLimit, if set, bounds the rate at which each
+	// client may propose request data.
+	RateLimit *RateLimit
 
 	mutex   sync.Mutex
 	clients map[uint64]*Client
@@ -51,7 +87,7 @@ func (c *Clients) ProcessClientActions(actions *statemachine.ActionList) (*state
 		case *state.Action_AllocatedRequest:
 			r := t.AllocatedRequest
 			client := c.Client(r.ClientId)
-			digest, err := client.allocate(r.ReqNo)
+			digest, size, err := client.allocate(r.ReqNo)
 			if err != nil {
 				return nil, err
 			}
@@ -64,16 +100,28 @@ func (c *Clients) ProcessClientActions(actions *statemachine.ActionList) (*state
 				ClientId: r.ClientId,
 				ReqNo:    r.ReqNo,
 				Digest:   digest,
-			})
+			}, size)
 		case *state.Action_CorrectRequest:
 			client := c.Client(t.CorrectRequest.ClientId)
 			err := client.addCorrectDigest(t.CorrectRequest.ReqNo, t.CorrectRequest.Digest)
+			if errors.Is(err, ErrClientNotExist) {
+				// The client was removed by the checkpoint we last applied, but
+				// the state machine does not release it until the checkpoint is
+				// stable.
+				continue
+			}
 			if err != nil {
 				return nil, err
 			}
 		case *state.Action_StateApplied:
 			for _, client := range t.StateApplied.NetworkState.Clients {
-				c.Client(client.Id).stateApplied(client)
+				if err := c.Client(client.Id).stateApplied(client); err != nil {
+					return nil, err
+				}
+			}
+
+			if err := c.removeClients(t.StateApplied.NetworkState.Clients); err != nil {
+				return nil, err
 			}
 		default:
 			return nil, errors.Errorf("unexpected type for client action: %T", action.Type)
@@ -82,6 +130,34 @@ func (c *Clients) ProcessClientActions(actions *statemachine.ActionList) (*state
 	return events, nil
 }
 
+// removeClients releases every allocated client which is absent from the
+// given client states.  Any outstanding handle to a removed client returns
+// ErrClientNotExist, so, should the client be added again, a new handle
+// must be obtained.
+func (c *Clients) removeClients(clientStates []*msgs.NetworkState_Client) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	present := map[uint64]struct{}{}
+	for _, clientState := range clientStates {
+		present[clientState.Id] = struct{}{}
+	}
+
+	for id, client := range c.clients {
+		if _, ok := present[id]; ok {
+			continue
+		}
+
+		if err := client.remove(); err != nil {
+			return err
+		}
+
+		delete(c.clients, id)
+	}
+
+	return nil
+}
+
 // TODO, client needs to be updated based on the state applied events, to give it a low watermark
 // minimally and to clean up the reqNoMap
 type Client struct {
@@ -90,27 +166,57 @@ type Client struct {
 	clientID     uint64
 	nextReqNo    uint64
 	requestStore RequestStore
+	validator    RequestValidator
+	gcWatermark  uint64
 	requests     *list.List
 	reqNoMap     map[uint64]*list.Element
+	limiter      *tokenBucket
+
+	// lowWatermark and width are the request window as of
+	// the last applied state, width is zero until then.
+	lowWatermark uint64
+	width        uint32
 }
 
-func newClient(clientID uint64, hasher Hasher, reqStore RequestStore) *Client {
+func newClient(clientID uint64, hasher Hasher, reqStore RequestStore, validator RequestValidator, rateLimit *RateLimit) *Client {
+	var limiter *tokenBucket
+	if rateLimit != nil {
+		limiter = newTokenBucket(rateLimit)
+	}
+
 	return &Client{
 		clientID:     clientID,
 		hasher:       hasher,
 		requestStore: reqStore,
+		validator:    validator,
 		requests:     list.New(),
 		reqNoMap:     map[uint64]*list.Element{},
+		limiter:      limiter,
+	}
+}
+
+// validate must be called without the mutex held, as validation
+// may be expensive.
+func (c *Client) validate(reqNo uint64, data []byte) error {
+	if c.validator == nil {
+		return nil
+	}
+
+	if err := c.validator.ValidateRequest(c.clientID, reqNo, data); err != nil {
+		return errors.WithMessagef(err, "request for client_id=%d req_no=%d failed validation", c.clientID, reqNo)
 	}
+
+	return nil
 }
 
 type clientRequest struct {
 	reqNo                 uint64
 	localAllocationDigest []byte
+	localAllocationSize   uint64
 	remoteCorrectDigests  [][]byte
 }
 
-func (c *Client) stateApplied(state *msgs.NetworkState_Client) {
+func (c *Client) stateApplied(state *msgs.NetworkState_Client) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	for reqNo, el := range c.reqNoMap {
@@ -122,15 +228,47 @@ func (c *Client) stateApplied(state *msgs.NetworkState_Client) {
 	if c.nextReqNo < state.LowWatermark {
 		c.nextReqNo = state.LowWatermark
 	}
+	c.lowWatermark = state.LowWatermark
+	c.width = state.Width
+
+	// Requests below the low watermark have been committed and
+	// applied, so there is no further need to store them.
+	if state.LowWatermark > c.gcWatermark {
+		if err := c.requestStore.GarbageCollect(c.clientID, state.LowWatermark); err != nil {
+			return errors.WithMessagef(err, "could not garbage collect requests for client_id=%d below req_no=%d", c.clientID, state.LowWatermark)
+		}
+		c.gcWatermark = state.LowWatermark
+	}
+
+	return nil
 }
 
-func (c *Client) allocate(reqNo uint64) ([]byte, error) {
+// remove releases the requests of a client, if it has any.
+func (c *Client) remove() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.requests.Len() == 0 {
+		return nil
+	}
+
+	c.requests.Init()
+	c.reqNoMap = map[uint64]*list.Element{}
+
+	if err := c.requestStore.GarbageCollect(c.clientID, math.MaxUint64); err != nil {
+		return errors.WithMessagef(err, "could not garbage collect requests for removed client_id=%d", c.clientID)
+	}
+
+	return nil
+}
+
+func (c *Client) allocate(reqNo uint64) ([]byte, uint64, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	el, ok := c.reqNoMap[reqNo]
 	if ok {
 		clientReq := el.Value.(*clientRequest)
-		return clientReq.localAllocationDigest, nil
+		return clientReq.localAllocationDigest, clientReq.localAllocationSize, nil
 	}
 
 	cr := &clientRequest{
@@ -141,12 +279,28 @@ func (c *Client) allocate(reqNo uint64) ([]byte, error) {
 
 	digest, err := c.requestStore.GetAllocation(c.clientID, reqNo)
 	if err != nil {
-		return nil, errors.WithMessagef(err, "could not get key for %d.%d", c.clientID, reqNo)
+		return nil, 0, errors.WithMessagef(err, "could not get key for %d.%d", c.clientID, reqNo)
+	}
+
+	if digest == nil {
+		return nil, 0, nil
+	}
+
+	// The request was stored before a restart, so we must
+	// read it back to learn its size.
+	data, err := c.requestStore.GetRequest(&msgs.RequestAck{
+		ClientId: c.clientID,
+		ReqNo:    reqNo,
+		Digest:   digest,
+	})
+	if err != nil {
+		return nil, 0, errors.WithMessagef(err, "could not get request for %d.%d", c.clientID, reqNo)
 	}
 
 	cr.localAllocationDigest = digest
+	cr.localAllocationSize = uint64(len(data))
 
-	return digest, nil
+	return digest, cr.localAllocationSize, nil
 }
 
 func (c *Client) addCorrectDigest(reqNo uint64, digest []byte) error {
@@ -187,6 +341,12 @@ func (c *Client) NextReqNo() (uint64, error) {
 }
 
 func (c *Client) Propose(reqNo uint64, data []byte) (*statemachine.EventList, error) {
+	// Validate before touching any client state, so that an invalid
+	// request is never allocated a slot, nor advances nextReqNo.
+	if err := c.validate(reqNo, data); err != nil {
+		return nil, err
+	}
+
 	h := c.hasher.New()
 	h.Write(data)
 	digest := h.Sum(nil)
@@ -197,10 +357,52 @@ func (c *Client) Propose(reqNo uint64, data []byte) (*statemachine.EventList, er
 		return nil, ErrClientNotExist
 	}
 
+	// Requests below the window have already committed, so, like any other
+	// request we already have, proposing them again is harmless.
 	if reqNo < c.nextReqNo {
 		return &statemachine.EventList{}, nil
 	}
 
+	if c.width > 0 && reqNo >= c.lowWatermark+uint64(c.width) {
+		return nil, errors.WithMessagef(ErrReqNoOutOfWindow, "client_id=%d req_no=%d is not within [%d, %d)", c.clientID, reqNo, c.lowWatermark, c.lowWatermark+uint64(c.width))
+	}
+
+	el, previouslyAllocated := c.reqNoMap[reqNo]
+	if previouslyAllocated {
+		cr := el.Value.(*clientRequest)
+		if cr.localAllocationDigest != nil && !bytes.Equal(cr.localAllocationDigest, digest) {
+			return nil, errors.Errorf("cannot store request with digest %x, already stored request with different digest %x", digest, cr.localAllocationDigest)
+		}
+
+		if cr.localAllocationDigest == nil && len(cr.remoteCorrectDigests) > 0 {
+			found := false
+			for _, rd := range cr.remoteCorrectDigests {
+				if bytes.Equal(rd, digest) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return nil, errors.New("other known correct digest exist for reqno")
+			}
+		}
+	}
+
+	if c.limiter != nil {
+		// Only data which passed the checks above, and which is yet
+		// to be stored, counts against the limit.
+		if !previouslyAllocated || el.Value.(*clientRequest).localAllocationDigest == nil {
+			if retryAfter, ok := c.limiter.take(uint64(len(data))); !ok {
+				return nil, &RateLimitedError{
+					ClientID:   c.clientID,
+					ReqNo:      reqNo,
+					RetryAfter: retryAfter,
+				}
+			}
+		}
+	}
+
 	if reqNo == c.nextReqNo {
 		for {
 			c.nextReqNo++
@@ -217,10 +419,7 @@ func (c *Client) Propose(reqNo uint64, data []byte) (*statemachine.EventList, er
 		}
 	}
 
-	el, ok := c.reqNoMap[reqNo]
-	previouslyAllocated := ok
-	if !ok {
-		// TODO, limit the distance ahead a client can allocate?
+	if !previouslyAllocated {
 		el = c.requests.PushBack(&clientRequest{
 			reqNo: reqNo,
 		})
@@ -230,25 +429,8 @@ func (c *Client) Propose(reqNo uint64, data []byte) (*statemachine.EventList, er
 	cr := el.Value.(*clientRequest)
 
 	if cr.localAllocationDigest != nil {
-		if bytes.Equal(cr.localAllocationDigest, digest) {
-			return &statemachine.EventList{}, nil
-		}
-
-		return nil, errors.Errorf("cannot store request with digest %x, already stored request with different digest %x", digest, cr.localAllocationDigest)
-	}
-
-	if len(cr.remoteCorrectDigests) > 0 {
-		found := false
-		for _, rd := range cr.remoteCorrectDigests {
-			if bytes.Equal(rd, digest) {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return nil, errors.New("other known correct digest exist for reqno")
-		}
+		// We already stored this same request.
+		return &statemachine.EventList{}, nil
 	}
 
 	ack := &msgs.RequestAck{
@@ -267,10 +449,87 @@ func (c *Client) Propose(reqNo uint64, data []byte) (*statemachine.EventList, er
 		return nil, err
 	}
 	cr.localAllocationDigest = digest
+	cr.localAllocationSize = uint64(len(data))
 
 	if previouslyAllocated {
-		return (&statemachine.EventList{}).RequestPersisted(ack), nil
+		return (&statemachine.EventList{}).RequestPersisted(ack, cr.localAllocationSize), nil
 	}
 
 	return &statemachine.EventList{}, nil
 }
+
+// forwardRequest validates and persists a request forwarded to us by
+// another replica.  We only accept forwarded requests whose data hashes
+// to the digest in the ack, and whose digest is already known to be
+// correct (ie, a weak quorum of replicas have acked it).  Otherwise, any
+// replica could cause us to store and then ack arbitrary requests.  A
+// request which does not match its digest or fails validation is rejected
+// with errInvalidForward, any other unwanted request is ignored.
+func (c *Client) forwardRequest(ack *msgs.RequestAck, data []byte) (*statemachine.EventList, error) {
+	h := c.hasher.New()
+	h.Write(data)
+	digest := h.Sum(nil)
+
+	if !bytes.Equal(ack.Digest, digest) {
+		return nil, errors.WithMessagef(errInvalidForward, "forwarded request for client_id=%d req_no=%d has digest %x but data hashes to %x", ack.ClientId, ack.ReqNo, ack.Digest, digest)
+	}
+
+	if err := c.validate(ack.ReqNo, data); err != nil {
+		return nil, errors.WithMessagef(errInvalidForward, "%s", err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	el, ok := c.reqNoMap[ack.ReqNo]
+	if !ok {
+		// Either the request has already committed, or it is
+		// not yet allocated, either way, we do not want it.
+		return &statemachine.EventList{}, nil
+	}
+
+	cr := el.Value.(*clientRequest)
+	if bytes.Equal(cr.localAllocationDigest, digest) {
+		// We already have this request stored
+		return &statemachine.EventList{}, nil
+	}
+
+	correct := false
+	for _, rd := range cr.remoteCorrectDigests {
+		if bytes.Equal(rd, digest) {
+			correct = true
+			break
+		}
+	}
+
+	if !correct {
+		return &statemachine.EventList{}, nil
+	}
+
+	// We may hold this request without having allocated it, should we
+	// have stored another request for this request number already.
+	stored, err := c.requestStore.GetRequest(ack)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not check for forwarded request")
+	}
+
+	if stored != nil {
+		return &statemachine.EventList{}, nil
+	}
+
+	err = c.requestStore.PutRequest(ack, data)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not store forwarded request")
+	}
+
+	if cr.localAllocationDigest == nil {
+		err = c.requestStore.PutAllocation(c.clientID, ack.ReqNo, digest)
+		if err != nil {
+			return nil, err
+		}
+		cr.localAllocationDigest = digest
+		cr.localAllocationSize = uint64(len(data))
+	}
+
+	return (&statemachine.EventList{}).RequestPersisted(ack, uint64(len(data))), nil
+}
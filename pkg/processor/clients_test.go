@@ -0,0 +1,428 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor_test
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+)
+
+var errInvalidRequest = fmt.Errorf("invalid request")
+
+// prefixValidator accepts only requests whose data has the given prefix.
+type prefixValidator []byte
+
+func (pv prefixValidator) ValidateRequest(clientID, reqNo uint64, data []byte) error {
+	if !bytes.HasPrefix(data, pv) {
+		return errInvalidRequest
+	}
+	return nil
+}
+
+func digest(data []byte) []byte {
+	h := crypto.SHA256.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func requestPersisted(events *statemachine.EventList) []*msgs.RequestAck {
+	var acks []*msgs.RequestAck
+	iter := events.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		if rp, ok := event.Type.(*state.Event_RequestPersisted); ok {
+			acks = append(acks, rp.RequestPersisted.RequestAck)
+		}
+	}
+	return acks
+}
+
+var _ = Describe("Clients", func() {
+	var (
+		reqStore *testengine.ReqStore
+		clients  *processor.Clients
+	)
+
+	BeforeEach(func() {
+		reqStore = testengine.NewReqStore()
+		clients = &processor.Clients{
+			Hasher:           crypto.SHA256,
+			RequestStore:     reqStore,
+			RequestValidator: prefixValidator("valid"),
+		}
+
+		_, err := clients.ProcessClientActions((&statemachine.ActionList{}).AllocateRequest(1, 0))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("validation of proposed requests", func() {
+		It("persists and acks a valid request", func() {
+			events, err := clients.Client(1).Propose(0, []byte("valid-0"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestPersisted(events)).To(Equal([]*msgs.RequestAck{
+				{
+					ClientId: 1,
+					ReqNo:    0,
+					Digest:   digest([]byte("valid-0")),
+				},
+			}))
+
+			nextReqNo, err := clients.Client(1).NextReqNo()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextReqNo).To(Equal(uint64(1)))
+		})
+
+		It("returns the rejection to the proposer without storing the request", func() {
+			events, err := clients.Client(1).Propose(0, []byte("bogus-0"))
+			Expect(err).To(MatchError("request for client_id=1 req_no=0 failed validation: invalid request"))
+			Expect(events).To(BeNil())
+
+			nextReqNo, err := clients.Client(1).NextReqNo()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextReqNo).To(Equal(uint64(0)))
+
+			allocation, err := reqStore.GetAllocation(1, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allocation).To(BeNil())
+
+			By("accepting a valid request for the same request number afterwards")
+			events, err = clients.Client(1).Propose(0, []byte("valid-0"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestPersisted(events)).To(HaveLen(1))
+		})
+	})
+
+	Describe("the request window", func() {
+		BeforeEach(func() {
+			_, err := clients.ProcessClientActions((&statemachine.ActionList{}).StateApplied(10, &msgs.NetworkState{
+				Clients: []*msgs.NetworkState_Client{
+					{
+						Id:    1,
+						Width: 2,
+					},
+				},
+			}))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects requests beyond the window until it advances", func() {
+			_, err := clients.Client(1).Propose(1, []byte("valid-1"))
+			Expect(err).NotTo(HaveOccurred())
+
+			events, err := clients.Client(1).Propose(2, []byte("valid-2"))
+			Expect(errors.Cause(err)).To(Equal(processor.ErrReqNoOutOfWindow))
+			Expect(err).To(MatchError("client_id=1 req_no=2 is not within [0, 2): request number outside of client window"))
+			Expect(events).To(BeNil())
+
+			_, err = clients.ProcessClientActions((&statemachine.ActionList{}).StateApplied(20, &msgs.NetworkState{
+				Clients: []*msgs.NetworkState_Client{
+					{
+						Id:           1,
+						LowWatermark: 1,
+						Width:        2,
+					},
+				},
+			}))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = clients.Client(1).Propose(2, []byte("valid-2"))
+			Expect(err).NotTo(HaveOccurred())
+
+			allocation, err := reqStore.GetAllocation(1, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allocation).To(Equal(digest([]byte("valid-2"))))
+		})
+	})
+
+	Describe("rate limiting", func() {
+		var now time.Time
+
+		propose := func(reqNo uint64, size int) error {
+			data := append([]byte("valid-"), make([]byte, size-len("valid-"))...)
+			_, err := clients.Client(2).Propose(reqNo, data)
+			return err
+		}
+
+		BeforeEach(func() {
+			now = time.Unix(0, 0)
+			clients.RateLimit = &processor.RateLimit{
+				BytesPerSecond: 10,
+				Burst:          20,
+				Now: func() time.Time {
+					return now
+				},
+			}
+
+			_, err := clients.ProcessClientActions((&statemachine.ActionList{}).AllocateRequest(2, 0))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("admits the burst and then the sustained rate", func() {
+			Expect(propose(0, 10)).To(Succeed())
+			Expect(propose(1, 10)).To(Succeed())
+
+			Expect(propose(2, 10)).To(Equal(&processor.RateLimitedError{
+				ClientID:   2,
+				ReqNo:      2,
+				RetryAfter: time.Second,
+			}))
+
+			now = now.Add(500 * time.Millisecond)
+			Expect(propose(2, 10)).To(Equal(&processor.RateLimitedError{
+				ClientID:   2,
+				ReqNo:      2,
+				RetryAfter: 500 * time.Millisecond,
+			}))
+
+			now = now.Add(500 * time.Millisecond)
+			Expect(propose(2, 10)).To(Succeed())
+
+			By("not charging again for requests already stored")
+			Expect(propose(1, 10)).To(Succeed())
+		})
+
+		It("admits a request larger than the burst once the bucket is full", func() {
+			Expect(propose(0, 30)).To(Succeed())
+
+			err := propose(1, 10)
+			Expect(err).To(BeAssignableToTypeOf(&processor.RateLimitedError{}))
+			Expect(err.(*processor.RateLimitedError).RetryAfter).To(Equal(2 * time.Second))
+
+			now = now.Add(2 * time.Second)
+			Expect(propose(1, 10)).To(Succeed())
+		})
+
+		It("never refills the bucket with a rate of zero", func() {
+			clients.RateLimit.BytesPerSecond = 0
+			_, err := clients.ProcessClientActions((&statemachine.ActionList{}).AllocateRequest(3, 0))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = clients.Client(3).Propose(0, []byte("valid-0123456789abc"))
+			Expect(err).NotTo(HaveOccurred())
+
+			now = now.Add(time.Hour)
+			_, err = clients.Client(3).Propose(1, []byte("valid-1"))
+			Expect(err).To(Equal(&processor.RateLimitedError{
+				ClientID:   3,
+				ReqNo:      1,
+				RetryAfter: time.Duration(math.MaxInt64),
+			}))
+		})
+
+		It("imposes no limit with the zero value", func() {
+			clients.RateLimit = &processor.RateLimit{}
+			_, err := clients.ProcessClientActions((&statemachine.ActionList{}).AllocateRequest(3, 0))
+			Expect(err).NotTo(HaveOccurred())
+
+			for reqNo := uint64(0); reqNo < 3; reqNo++ {
+				_, err = clients.Client(3).Propose(reqNo, append([]byte("valid-"), make([]byte, 100)...))
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("does not charge for requests which are rejected", func() {
+			_, err := clients.ProcessClientActions((&statemachine.ActionList{}).AllocateRequest(2, 1).CorrectRequest(&msgs.RequestAck{
+				ClientId: 2,
+				ReqNo:    1,
+				Digest:   digest([]byte("valid-other")),
+			}))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = propose(1, 10)
+			Expect(err).To(MatchError("other known correct digest exist for reqno"))
+
+			Expect(propose(0, 20)).To(Succeed())
+		})
+	})
+
+	Describe("removal of clients", func() {
+		applyState := func(clientIDs ...uint64) {
+			networkState := &msgs.NetworkState{}
+			for _, id := range clientIDs {
+				networkState.Clients = append(networkState.Clients, &msgs.NetworkState_Client{
+					Id:    id,
+					Width: 100,
+				})
+			}
+
+			_, err := clients.ProcessClientActions((&statemachine.ActionList{}).StateApplied(10, networkState))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("releases the requests of a client absent from the configuration", func() {
+			_, err := clients.Client(1).Propose(0, []byte("valid-0"))
+			Expect(err).NotTo(HaveOccurred())
+			removed := clients.Client(1)
+
+			applyState()
+			Expect(clients.Client(1)).NotTo(BeIdenticalTo(removed))
+
+			_, err = removed.NextReqNo()
+			Expect(err).To(Equal(processor.ErrClientNotExist))
+
+			allocation, err := reqStore.GetAllocation(1, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allocation).To(BeNil())
+		})
+
+		It("drops a client which holds no requests", func() {
+			unallocated := clients.Client(2)
+
+			applyState(1)
+			Expect(clients.Client(2)).NotTo(BeIdenticalTo(unallocated))
+		})
+
+		It("keeps clients present in the configuration", func() {
+			allocated := clients.Client(1)
+
+			applyState(1)
+			Expect(clients.Client(1)).To(BeIdenticalTo(allocated))
+		})
+	})
+
+	Describe("validation of forwarded requests", func() {
+		var replicas *processor.Replicas
+
+		forward := func(data []byte) (*statemachine.EventList, error) {
+			ack := &msgs.RequestAck{
+				ClientId: 1,
+				ReqNo:    0,
+				Digest:   digest(data),
+			}
+
+			_, err := clients.ProcessClientActions((&statemachine.ActionList{}).CorrectRequest(ack))
+			Expect(err).NotTo(HaveOccurred())
+
+			return replicas.Replica(2).Step(&msgs.Msg{
+				Type: &msgs.Msg_ForwardRequest{
+					ForwardRequest: &msgs.ForwardRequest{
+						RequestAck:  ack,
+						RequestData: data,
+					},
+				},
+			})
+		}
+
+		BeforeEach(func() {
+			replicas = &processor.Replicas{
+				Clients: clients,
+			}
+		})
+
+		It("persists a valid request", func() {
+			events, err := forward([]byte("valid-0"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestPersisted(events)).To(HaveLen(1))
+
+			data, err := reqStore.GetRequest(requestPersisted(events)[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal([]byte("valid-0")))
+		})
+
+		It("persists and allocates a request only once", func() {
+			events, err := forward([]byte("valid-0"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestPersisted(events)).To(HaveLen(1))
+
+			events, err = replicas.Replica(2).Step(&msgs.Msg{
+				Type: &msgs.Msg_ForwardRequest{
+					ForwardRequest: &msgs.ForwardRequest{
+						RequestAck:  requestPersisted(events)[0],
+						RequestData: []byte("valid-0"),
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestPersisted(events)).To(BeEmpty())
+
+			allocation, err := reqStore.GetAllocation(1, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(allocation).To(Equal(digest([]byte("valid-0"))))
+
+			By("recovering the forwarded request after a restart")
+			restarted := &processor.Clients{
+				Hasher:       crypto.SHA256,
+				RequestStore: reqStore,
+			}
+			events, err = restarted.ProcessClientActions((&statemachine.ActionList{}).AllocateRequest(1, 0))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requestPersisted(events)).To(Equal([]*msgs.RequestAck{
+				{
+					ClientId: 1,
+					ReqNo:    0,
+					Digest:   digest([]byte("valid-0")),
+				},
+			}))
+		})
+
+		It("drops an invalid request without storing it", func() {
+			events, err := forward([]byte("bogus-0"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events.Len()).To(Equal(0))
+			Expect(replicas.Replica(2).VerificationFailures()).To(Equal(uint64(1)))
+
+			data, err := reqStore.GetRequest(&msgs.RequestAck{
+				ClientId: 1,
+				ReqNo:    0,
+				Digest:   digest([]byte("bogus-0")),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(BeNil())
+		})
+
+		It("drops a request whose data does not match its digest", func() {
+			events, err := replicas.Replica(2).Step(&msgs.Msg{
+				Type: &msgs.Msg_ForwardRequest{
+					ForwardRequest: &msgs.ForwardRequest{
+						RequestAck: &msgs.RequestAck{
+							ClientId: 1,
+							ReqNo:    0,
+							Digest:   digest([]byte("valid-0")),
+						},
+						RequestData: []byte("valid-1"),
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events.Len()).To(Equal(0))
+			Expect(replicas.Replica(2).VerificationFailures()).To(Equal(uint64(1)))
+		})
+
+		It("ignores requests of clients outside of the network configuration", func() {
+			events, err := replicas.Replica(2).Step(&msgs.Msg{
+				Type: &msgs.Msg_ForwardRequest{
+					ForwardRequest: &msgs.ForwardRequest{
+						RequestAck: &msgs.RequestAck{
+							ClientId: 7,
+							ReqNo:    0,
+							Digest:   digest([]byte("valid-0")),
+						},
+						RequestData: []byte("valid-0"),
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events.Len()).To(Equal(0))
+			Expect(replicas.Replica(2).VerificationFailures()).To(Equal(uint64(0)))
+
+			_, err = clients.Client(7).NextReqNo()
+			Expect(err).To(Equal(processor.ErrClientNotExist))
+		})
+	})
+})
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+// The categories of work reported by Metrics.QueueDepth, one
+// for each of the queues in WorkItems.
+const (
+	WorkWAL         = "wal"
+	WorkNet         = "net"
+	WorkHash        = "hash"
+	WorkClient      = "client"
+	WorkApp         = "app"
+	WorkReqStore    = "reqstore"
+	WorkStateEvents = "state_events"
+)
+
+// Metrics is updated as a node processes its work.  Implementations must be
+// safe for concurrent use, as each of the work loops reports independently,
+// and should be cheap, as they are invoked on the hot path.
+type Metrics interface {
+	// QueueDepth reports the number of items pending in a category of work.
+	QueueDepth(category string, depth int)
+
+	// WALSynced reports the time taken by a sync of the WAL.
+	WALSynced(duration time.Duration)
+
+	// Hashed reports a completed set of hash requests, and the number
+	// of bytes hashed.
+	Hashed(requests, bytes int)
+
+	// Committed reports a batch committed to the application, and
+	// the number of requests in it.
+	Committed(requests int)
+
+	// EpochStarted reports that the node began a new epoch.
+	EpochStarted(epoch uint64)
+
+	// CheckpointLag reports the distance in sequence numbers between the
+	// latest checkpoint computed locally and the latest stable checkpoint.
+	CheckpointLag(seqNos uint64)
+
+	// MsgsBuffered reports the number of messages from a node held for
+	// later processing.
+	MsgsBuffered(source uint64, msgs int)
+
+	// MsgDropped reports a message from a node which was discarded.
+	MsgDropped(source uint64)
+}
+
+// WorkItemsMetrics reports the depth of each of the work queues.
+func WorkItemsMetrics(metrics Metrics, workItems *WorkItems) {
+	metrics.QueueDepth(WorkWAL, workItems.WALActions().Len())
+	metrics.QueueDepth(WorkNet, workItems.NetActions().Len())
+	metrics.QueueDepth(WorkHash, workItems.HashActions().Len())
+	metrics.QueueDepth(WorkClient, workItems.ClientActions().Len())
+	metrics.QueueDepth(WorkApp, workItems.AppActions().Len())
+	metrics.QueueDepth(WorkReqStore, workItems.ReqStoreEvents().Len())
+	metrics.QueueDepth(WorkStateEvents, workItems.ResultEvents().Len())
+}
+
+// WALActionsMetrics reports any new epochs persisted by the WAL actions.
+func WALActionsMetrics(metrics Metrics, actions *statemachine.ActionList) {
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		write, ok := action.Type.(*state.Action_AppendWriteAhead)
+		if !ok {
+			continue
+		}
+
+		if nEntry, ok := write.AppendWriteAhead.Data.Type.(*msgs.Persistent_NEntry); ok {
+			metrics.EpochStarted(nEntry.NEntry.EpochConfig.Number)
+		}
+	}
+}
+
+// HashActionsMetrics reports the requests and bytes hashed by the hash actions.
+func HashActionsMetrics(metrics Metrics, actions *statemachine.ActionList) {
+	bytes := 0
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		hashRequest, ok := action.Type.(*state.Action_Hash)
+		if !ok {
+			continue
+		}
+
+		for _, data := range hashRequest.Hash.Data {
+			bytes += len(data)
+		}
+	}
+
+	metrics.Hashed(actions.Len(), bytes)
+}
+
+// AppActionsMetrics reports the batches committed by the app actions.
+func AppActionsMetrics(metrics Metrics, actions *statemachine.ActionList) {
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		if commit, ok := action.Type.(*state.Action_Commit); ok {
+			metrics.Committed(len(commit.Commit.Batch.Requests))
+		}
+	}
+}
+
+// StatusMetrics reports the checkpoint lag and message buffers
+// from a status snapshot of the state machine.
+func StatusMetrics(metrics Metrics, s *status.StateMachine) {
+	var lag uint64
+	for _, cp := range s.Checkpoints {
+		if cp.LocalDecision && cp.SeqNo > s.LowWatermark && cp.SeqNo-s.LowWatermark > lag {
+			lag = cp.SeqNo - s.LowWatermark
+		}
+	}
+	metrics.CheckpointLag(lag)
+
+	for _, nodeBuffer := range s.NodeBuffers {
+		metrics.MsgsBuffered(nodeBuffer.ID, nodeBuffer.Msgs)
+	}
+}
+
+// TimedWAL wraps a WAL, reporting the duration of each sync.
+type TimedWAL struct {
+	WAL
+	Metrics Metrics
+}
+
+func (tw *TimedWAL) Sync() error {
+	start := time.Now()
+	err := tw.WAL.Sync()
+	tw.Metrics.WALSynced(time.Since(start))
+	return err
+}
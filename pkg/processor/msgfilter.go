@@ -52,6 +52,14 @@ func preProcess(outerMsg *msgs.Msg) error {
 		if innerMsg.ForwardRequest.RequestAck == nil {
 			return errors.Errorf("message of type ForwardRequest, but forward_request's request_ack field is nil")
 		}
+	case *msgs.Msg_FetchCheckpointChunk:
+		if innerMsg.FetchCheckpointChunk == nil {
+			return errors.Errorf("message of type FetchCheckpointChunk, but fetch_checkpoint_chunk field is nil")
+		}
+	case *msgs.Msg_CheckpointChunk:
+		if innerMsg.CheckpointChunk == nil {
+			return errors.Errorf("message of type CheckpointChunk, but checkpoint_chunk field is nil")
+		}
 	case *msgs.Msg_FetchBatch:
 		if innerMsg.FetchBatch == nil {
 			return errors.Errorf("message of type FetchBatch, but fetch_batch field is nil")
@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+// OddityReporter is notified of each message the state machine discarded
+// because the sending node violated the protocol.  Implementations must
+// be safe for concurrent use, and should not block.
+type OddityReporter interface {
+	ReportOddity(oddity *state.ActionOddity)
+}
+
+// ReportOddities reports any oddities among the state machine actions.
+func ReportOddities(reporter OddityReporter, actions *statemachine.ActionList) {
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		if oddity, ok := action.Type.(*state.Action_Oddity); ok {
+			reporter.ReportOddity(oddity.Oddity)
+		}
+	}
+}
+
+// OddityCounts is an OddityReporter which counts the oddities reported
+// for each node by the rule violated, from which misbehaviour scores
+// may be derived.
+type OddityCounts struct {
+	mutex  sync.Mutex
+	counts map[uint64]map[state.ActionOddity_Rule]uint64
+}
+
+func (oc *OddityCounts) ReportOddity(oddity *state.ActionOddity) {
+	oc.mutex.Lock()
+	defer oc.mutex.Unlock()
+
+	if oc.counts == nil {
+		oc.counts = map[uint64]map[state.ActionOddity_Rule]uint64{}
+	}
+
+	nodeCounts, ok := oc.counts[oddity.NodeId]
+	if !ok {
+		nodeCounts = map[state.ActionOddity_Rule]uint64{}
+		oc.counts[oddity.NodeId] = nodeCounts
+	}
+
+	nodeCounts[oddity.Rule]++
+}
+
+// Counts returns a copy of the number of oddities reported
+// for each node, by rule.
+func (oc *OddityCounts) Counts() map[uint64]map[state.ActionOddity_Rule]uint64 {
+	oc.mutex.Lock()
+	defer oc.mutex.Unlock()
+
+	result := make(map[uint64]map[state.ActionOddity_Rule]uint64, len(oc.counts))
+	for nodeID, nodeCounts := range oc.counts {
+		result[nodeID] = make(map[state.ActionOddity_Rule]uint64, len(nodeCounts))
+		for rule, count := range nodeCounts {
+			result[nodeID][rule] = count
+		}
+	}
+	return result
+}
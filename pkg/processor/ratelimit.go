@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// RateLimit bounds the rate at which each client may propose request data.
+// The zero value imposes no limit.
+type RateLimit struct {
+	// BytesPerSecond is the sustained rate at which each client
+	// may propose request data.  If zero, but the burst is not, the
+	// tokens are never refilled, so each client may propose only its
+	// burst.
+	BytesPerSecond uint64
+
+	// Burst is the number of bytes which a client may propose at once
+	// after being idle, it defaults to BytesPerSecond.
+	Burst uint64
+
+	// Now, if set, is used in place of time.Now, this is useful for testing.
+	Now func() time.Time
+}
+
+func (rl *RateLimit) now() time.Time {
+	if rl.Now != nil {
+		return rl.Now()
+	}
+	return time.Now()
+}
+
+func (rl *RateLimit) burst() float64 {
+	if rl.Burst != 0 {
+		return float64(rl.Burst)
+	}
+	return float64(rl.BytesPerSecond)
+}
+
+// RateLimitedError is returned by Propose when a client has exceeded its
+// rate limit.  The request was not stored, and may be proposed again once
+// RetryAfter has elapsed.
+type RateLimitedError struct {
+	ClientID   uint64
+	ReqNo      uint64
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("client_id=%d req_no=%d exceeded its rate limit, retry after %v", e.ClientID, e.ReqNo, e.RetryAfter)
+}
+
+// tokenBucket accumulates one token per byte at the configured rate, up
+// to the burst.  It must be protected by the mutex of its client.
+type tokenBucket struct {
+	rateLimit  *RateLimit
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(rateLimit *RateLimit) *tokenBucket {
+	return &tokenBucket{
+		rateLimit:  rateLimit,
+		tokens:     rateLimit.burst(),
+		lastRefill: rateLimit.now(),
+	}
+}
+
+// take consumes tokens for the given number of bytes, or, if too few
+// tokens are available, returns how long to wait until there will be.
+// Requests larger than the burst are admitted once the bucket is full,
+// leaving it in debt, so that they are not starved forever.
+func (tb *tokenBucket) take(size uint64) (time.Duration, bool) {
+	rate := float64(tb.rateLimit.BytesPerSecond)
+	burst := tb.rateLimit.burst()
+	if burst == 0 {
+		// Neither a rate nor a burst is set, so there is no limit.
+		return 0, true
+	}
+
+	now := tb.rateLimit.now()
+
+	tb.tokens += now.Sub(tb.lastRefill).Seconds() * rate
+	if tb.tokens > burst {
+		tb.tokens = burst
+	}
+	tb.lastRefill = now
+
+	required := float64(size)
+	if required > burst {
+		required = burst
+	}
+
+	if tb.tokens < required {
+		if rate == 0 {
+			// The tokens will never be refilled.
+			return time.Duration(math.MaxInt64), false
+		}
+		return time.Duration((required - tb.tokens) / rate * float64(time.Second)), false
+	}
+
+	tb.tokens -= float64(size)
+	return 0, true
+}
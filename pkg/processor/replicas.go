@@ -7,13 +7,23 @@ SPDX-License-Identifier: Apache-2.0
 package processor
 
 import (
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
 	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
 )
 
 type Replicas struct {
-	replicas map[uint64]*Replica
-	Clients  *Clients
+	replicas      map[uint64]*Replica
+	Clients       *Clients
+	StateTransfer *StateTransfer
+	Verifier      Verifier
+
+	// Metrics, if set, is notified of messages dropped
+	// because they failed verification.
+	Metrics Metrics
 }
 
 func (rs *Replicas) Replica(id uint64) *Replica {
@@ -24,15 +34,52 @@ func (rs *Replicas) Replica(id uint64) *Replica {
 	r, ok := rs.replicas[id]
 	if !ok {
 		r = &Replica{
-			id: id,
+			id:            id,
+			clients:       rs.Clients,
+			stateTransfer: rs.StateTransfer,
+			verifier:      rs.Verifier,
+			metrics:       rs.Metrics,
 		}
 		rs.replicas[id] = r
 	}
 	return r
 }
 
+// VerificationFailures returns, for each replica which has sent
+// us messages, the number of its messages which failed verification.
+func (rs *Replicas) VerificationFailures() map[uint64]uint64 {
+	result := make(map[uint64]uint64, len(rs.replicas))
+	for id, r := range rs.replicas {
+		result[id] = r.VerificationFailures()
+	}
+	return result
+}
+
 type Replica struct {
-	id uint64
+	// verificationFailures is accessed atomically, and so is
+	// kept first in the struct to ensure 64-bit alignment.
+	verificationFailures uint64
+
+	id            uint64
+	clients       *Clients
+	stateTransfer *StateTransfer
+	verifier      Verifier
+	metrics       Metrics
+}
+
+// VerificationFailures returns the number of messages from this
+// replica which have been dropped because they failed verification.
+func (r *Replica) VerificationFailures() uint64 {
+	return atomic.LoadUint64(&r.verificationFailures)
+}
+
+// drop counts a message from this replica which failed verification.
+func (r *Replica) drop() *statemachine.EventList {
+	atomic.AddUint64(&r.verificationFailures, 1)
+	if r.metrics != nil {
+		r.metrics.MsgDropped(r.id)
+	}
+	return &statemachine.EventList{}
 }
 
 func (r *Replica) Step(msg *msgs.Msg) (*statemachine.EventList, error) {
@@ -41,15 +88,44 @@ func (r *Replica) Step(msg *msgs.Msg) (*statemachine.EventList, error) {
 		return nil, err
 	}
 
+	switch msg.Type.(type) {
+	case *msgs.Msg_FetchCheckpointChunk, *msgs.Msg_CheckpointChunk:
+		// State transfer data is verified against the agreed checkpoint
+		// value, so these messages need not be authenticated.
+	default:
+		if r.verifier != nil {
+			if err := verifyMsg(r.verifier, r.id, msg); err != nil {
+				return r.drop(), nil
+			}
+		}
+	}
+
 	switch t := msg.Type.(type) {
 	case *msgs.Msg_ForwardRequest:
 		// We handle messages of type Forward specially, as we don't
 		// want to pass them into the state machine, but instead buffer them
-		// externally.  This will also let us do manual validation for apps
-		// which attach signatures to their txes.
-		_ = t.ForwardRequest
-		// TODO, implement
-		return &statemachine.EventList{}, nil
+		// externally.  The forwarded request is checked against the
+		// configured RequestValidator before it is stored or acked.
+		// Requests for clients outside of the network configuration, and
+		// requests which do not verify, are dropped.
+		requestAck := t.ForwardRequest.RequestAck
+		client := r.clients.existingClient(requestAck.ClientId)
+		if client == nil {
+			return &statemachine.EventList{}, nil
+		}
+
+		events, err := client.forwardRequest(requestAck, t.ForwardRequest.RequestData)
+		if errors.Cause(err) == errInvalidForward {
+			return r.drop(), nil
+		}
+		return events, err
+	case *msgs.Msg_FetchCheckpointChunk, *msgs.Msg_CheckpointChunk:
+		// State transfer messages are handled entirely outside of the
+		// state machine, by the built-in state transfer, if configured.
+		if r.stateTransfer == nil {
+			return nil, errors.Errorf("received state transfer message of type %T, but state transfer is not configured", msg.Type)
+		}
+		return r.stateTransfer.step(r.id, msg)
 	default:
 		return (&statemachine.EventList{}).Step(r.id, msg), nil
 	}
@@ -32,11 +32,23 @@ type App interface {
 	TransferTo(seqNo uint64, snap []byte) (*msgs.NetworkState, error)
 }
 
+// RequestValidator is consulted before a request is accepted, whether it
+// is proposed locally or forwarded by another replica.  This allows
+// applications, for instance, to check signatures which clients attach
+// to their requests.  Requests which fail validation are never acked.
+type RequestValidator interface {
+	ValidateRequest(clientID, reqNo uint64, data []byte) error
+}
+
 type RequestStore interface {
 	GetAllocation(clientID, reqNo uint64) ([]byte, error)
 	PutAllocation(clientID, reqNo uint64, digest []byte) error
 	GetRequest(requestAck *msgs.RequestAck) ([]byte, error)
 	PutRequest(requestAck *msgs.RequestAck, data []byte) error
+
+	// GarbageCollect removes all allocations and request data for the
+	// given client whose request numbers are below the low watermark.
+	GarbageCollect(clientID, lowWatermark uint64) error
 	Sync() error
 }
 
@@ -96,6 +108,54 @@ func IntializeWALForNewNode(
 		},
 	}
 
+	return initializeWAL(wal, runtimeParms, entries)
+}
+
+// InitializeWALForJoiningNode prepares the WAL of a node which is added to a
+// running network.  The node starts from the stable checkpoint at seqNo,
+// whose network configuration includes it, and immediately transfers the
+// state of that checkpoint from the other nodes.
+func InitializeWALForJoiningNode(
+	wal WAL,
+	runtimeParms *state.EventInitialParameters,
+	seqNo uint64,
+	networkState *msgs.NetworkState,
+	checkpointValue []byte,
+) (*statemachine.EventList, error) {
+	entries := []*msgs.Persistent{
+		{
+			Type: &msgs.Persistent_CEntry{
+				CEntry: &msgs.CEntry{
+					SeqNo:           seqNo,
+					CheckpointValue: checkpointValue,
+					NetworkState:    networkState,
+				},
+			},
+		},
+		{
+			Type: &msgs.Persistent_FEntry{
+				FEntry: &msgs.FEntry{
+					EndsEpochConfig: &msgs.EpochConfig{
+						Number:  0,
+						Leaders: networkState.Config.Nodes,
+					},
+				},
+			},
+		},
+		{
+			Type: &msgs.Persistent_TEntry{
+				TEntry: &msgs.TEntry{
+					SeqNo: seqNo,
+					Value: checkpointValue,
+				},
+			},
+		},
+	}
+
+	return initializeWAL(wal, runtimeParms, entries)
+}
+
+func initializeWAL(wal WAL, runtimeParms *state.EventInitialParameters, entries []*msgs.Persistent) (*statemachine.EventList, error) {
 	events := &statemachine.EventList{}
 	events.Initialize(runtimeParms)
 	for i, entry := range entries {
@@ -155,19 +215,68 @@ func ProcessWALActions(wal WAL, actions *statemachine.ActionList) (*statemachine
 	return netActions, nil
 }
 
-func ProcessNetActions(selfID uint64, link Link, actions *statemachine.ActionList) (*statemachine.EventList, error) {
+// ProcessNetActions sends the messages requested by the state machine.  If
+// signer is non-nil, each message is signed once before it is sent to any
+// remote replica.  Messages to ourselves are looped back unsigned.
+func ProcessNetActions(selfID uint64, link Link, reqStore RequestStore, signer Signer, actions *statemachine.ActionList) (*statemachine.EventList, error) {
 	events := &statemachine.EventList{}
 
+	sign := func(msg *msgs.Msg) (*msgs.Msg, error) {
+		if signer == nil {
+			return msg, nil
+		}
+		return signMsg(signer, msg)
+	}
+
 	iter := actions.Iterator()
 	for action := iter.Next(); action != nil; action = iter.Next() {
 		switch t := action.Type.(type) {
 		case *state.Action_Send:
+			var signedMsg *msgs.Msg
 			for _, replica := range t.Send.Targets {
 				if replica == selfID {
 					events.Step(replica, t.Send.Msg)
-				} else {
-					link.Send(replica, t.Send.Msg)
+					continue
+				}
+
+				if signedMsg == nil {
+					var err error
+					signedMsg, err = sign(t.Send.Msg)
+					if err != nil {
+						return nil, err
+					}
+				}
+				link.Send(replica, signedMsg)
+			}
+		case *state.Action_ForwardRequest:
+			requestAck := t.ForwardRequest.Ack
+			requestData, err := reqStore.GetRequest(requestAck)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "could not read request data for client_id=%d req_no=%d to forward", requestAck.ClientId, requestAck.ReqNo)
+			}
+
+			if requestData == nil {
+				// Null requests have no data, and so need no forwarding
+				continue
+			}
+
+			msg, err := sign(&msgs.Msg{
+				Type: &msgs.Msg_ForwardRequest{
+					ForwardRequest: &msgs.ForwardRequest{
+						RequestAck:  requestAck,
+						RequestData: requestData,
+					},
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, replica := range t.ForwardRequest.Targets {
+				if replica == selfID {
+					continue
 				}
+				link.Send(replica, msg)
 			}
 		default:
 			return nil, errors.Errorf("unexpected type for Net action: %T", action.Type)
@@ -197,7 +306,10 @@ func ProcessHashActions(hasher Hasher, actions *statemachine.ActionList) (*state
 	return events, nil
 }
 
-func ProcessAppActions(app App, actions *statemachine.ActionList) (*statemachine.EventList, error) {
+// ProcessAppActions applies the given actions to the application.  If
+// stateTransfer is non-nil, snapshots are taken and state transfers are
+// performed through it, rather than through the app directly.
+func ProcessAppActions(app App, stateTransfer *StateTransfer, actions *statemachine.ActionList) (*statemachine.EventList, error) {
 	events := &statemachine.EventList{}
 	iter := actions.Iterator()
 	for action := iter.Next(); action != nil; action = iter.Next() {
@@ -208,13 +320,21 @@ func ProcessAppActions(app App, actions *statemachine.ActionList) (*statemachine
 			}
 		case *state.Action_Checkpoint:
 			cp := t.Checkpoint
-			value, pendingReconf, err := app.Snap(cp.NetworkConfig, cp.ClientStates)
+			snap := app.Snap
+			if stateTransfer != nil {
+				snap = stateTransfer.Snap
+			}
+			value, pendingReconf, err := snap(cp.NetworkConfig, cp.ClientStates)
 			if err != nil {
 				return nil, errors.WithMessage(err, "app failed to generate snapshot")
 			}
 			events.CheckpointResult(value, pendingReconf, cp)
 		case *state.Action_StateTransfer:
 			stateTarget := t.StateTransfer
+			if stateTransfer != nil {
+				events.PushBackList(stateTransfer.transferTo(stateTarget))
+				continue
+			}
 			state, err := app.TransferTo(stateTarget.SeqNo, stateTarget.Value)
 			if err != nil {
 				events.StateTransferFailed(stateTarget)
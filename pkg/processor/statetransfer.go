@@ -0,0 +1,390 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+const (
+	defaultChunkSize         = 1024 * 1024
+	defaultFetchTimeoutTicks = 4
+	defaultRetainedSnapshots = 3
+	defaultMaxChunks         = 4096
+	defaultMaxDataSize       = 1024 * 1024 * 1024
+)
+
+// StateTransfer is a built-in implementation of state transfer.  When it is
+// configured, the snapshots returned by App.Snap are treated as opaque checkpoint
+// data which is retained and served to peers, while the checkpoint value agreed
+// on by the network is the hash of that data.  When the state machine requests a
+// state transfer, the checkpoint data is fetched in chunks over the Link from the
+// nodes which attested to the checkpoint value, one node at a time.  Only once the
+// fetched data hashes to the agreed value is it passed to App.TransferTo, which,
+// like App.Apply, is invoked while processing the app actions.
+type StateTransfer struct {
+	NodeID uint64
+	Link   Link
+	Hasher Hasher
+	App    App
+
+	// ChunkSize is the maximum number of bytes of checkpoint data sent
+	// in a single message, it defaults to 1MB.
+	ChunkSize int
+
+	// FetchTimeoutTicks is the number of ticks without receiving a requested
+	// chunk before the transfer moves on to the next source, it defaults to 4.
+	FetchTimeoutTicks int
+
+	// RetainedSnapshots is the number of most recent snapshots kept available
+	// to serve to peers, it defaults to 3.
+	RetainedSnapshots int
+
+	// MaxChunks is the maximum number of chunks a source may split the
+	// checkpoint data into, it defaults to 4096.
+	MaxChunks int
+
+	// MaxDataSize is the maximum number of bytes of checkpoint data which
+	// will be fetched from a source, it defaults to 1GB.
+	MaxDataSize int
+
+	mutex     sync.Mutex
+	snapshots []*snapshot
+	pending   *pendingTransfer
+	fetched   *fetchedTransfer
+}
+
+type snapshot struct {
+	value []byte
+	data  []byte
+}
+
+type pendingTransfer struct {
+	target      *state.ActionStateTarget
+	sourceIndex int
+	totalChunks uint64
+	chunks      [][]byte
+	size        int
+	idleTicks   int
+}
+
+type fetchedTransfer struct {
+	target *state.ActionStateTarget
+	data   []byte
+	queued bool
+}
+
+type chunkFetch struct {
+	source uint64
+	msg    *msgs.Msg
+}
+
+// Snap invokes Snap on the underlying App, retains the resulting snapshot
+// so that it may be served to peers, and returns the hash of the snapshot
+// as the checkpoint value.  The initial checkpoint value supplied when
+// starting a new node must likewise be obtained from this method.
+func (st *StateTransfer) Snap(networkConfig *msgs.NetworkState_Config, clientsState []*msgs.NetworkState_Client) ([]byte, []*msgs.Reconfiguration, error) {
+	data, pendingReconf, err := st.App.Snap(networkConfig, clientsState)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := st.Hasher.New()
+	h.Write(data)
+	value := h.Sum(nil)
+
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	retained := st.RetainedSnapshots
+	if retained <= 0 {
+		retained = defaultRetainedSnapshots
+	}
+
+	st.snapshots = append(st.snapshots, &snapshot{
+		value: value,
+		data:  data,
+	})
+	if len(st.snapshots) > retained {
+		st.snapshots = st.snapshots[len(st.snapshots)-retained:]
+	}
+
+	return value, pendingReconf, nil
+}
+
+// Tick must be invoked whenever a tick elapses.  If the current source has not
+// responded within the fetch timeout, the transfer moves on to the next source.
+func (st *StateTransfer) Tick() *statemachine.EventList {
+	events, fetch := st.tick()
+	st.send(fetch)
+	return events
+}
+
+func (st *StateTransfer) tick() (*statemachine.EventList, *chunkFetch) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	if st.pending == nil {
+		return &statemachine.EventList{}, nil
+	}
+
+	timeout := st.FetchTimeoutTicks
+	if timeout <= 0 {
+		timeout = defaultFetchTimeoutTicks
+	}
+
+	st.pending.idleTicks++
+	if st.pending.idleTicks < timeout {
+		return &statemachine.EventList{}, nil
+	}
+
+	return st.nextSource()
+}
+
+// Fetched returns a state transfer action for the target whose checkpoint
+// data has been fetched, if any.  It must be added to the app actions, so
+// that App.TransferTo is invoked serially with App.Apply.
+func (st *StateTransfer) Fetched() *statemachine.ActionList {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	if st.fetched == nil || st.fetched.queued {
+		return &statemachine.ActionList{}
+	}
+
+	target := st.fetched.target
+	st.fetched.queued = true
+
+	return (&statemachine.ActionList{}).StateTransfer(target.SeqNo, target.Value, target.Sources)
+}
+
+// transferTo transfers the app to the checkpoint data for the given target
+// once it has been fetched, otherwise it begins fetching the data.  Any
+// previously pending transfer is abandoned.
+func (st *StateTransfer) transferTo(target *state.ActionStateTarget) *statemachine.EventList {
+	data, ok := st.takeFetched(target)
+	if !ok {
+		events, fetch := st.startFetch(target)
+		st.send(fetch)
+		return events
+	}
+
+	networkState, err := st.App.TransferTo(target.SeqNo, data)
+	if err != nil {
+		return (&statemachine.EventList{}).StateTransferFailed(target)
+	}
+
+	return (&statemachine.EventList{}).StateTransferComplete(networkState, target)
+}
+
+// takeFetched returns the fetched checkpoint data if it is for the given target.
+func (st *StateTransfer) takeFetched(target *state.ActionStateTarget) ([]byte, bool) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	f := st.fetched
+	if f == nil ||
+		f.target.SeqNo != target.SeqNo ||
+		!bytes.Equal(f.target.Value, target.Value) {
+		return nil, false
+	}
+
+	st.fetched = nil
+	return f.data, true
+}
+
+func (st *StateTransfer) startFetch(target *state.ActionStateTarget) (*statemachine.EventList, *chunkFetch) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	st.fetched = nil
+	st.pending = &pendingTransfer{
+		target:      target,
+		sourceIndex: -1,
+	}
+
+	return st.nextSource()
+}
+
+// send sends the chunk fetch, if any.  It must be called without the mutex
+// held, so that a slow link does not block the transfer.
+func (st *StateTransfer) send(fetch *chunkFetch) {
+	if fetch == nil {
+		return
+	}
+
+	st.Link.Send(fetch.source, fetch.msg)
+}
+
+// nextSource must be called with the mutex held.
+func (st *StateTransfer) nextSource() (*statemachine.EventList, *chunkFetch) {
+	p := st.pending
+	p.totalChunks = 0
+	p.chunks = nil
+	p.size = 0
+	p.idleTicks = 0
+
+	for p.sourceIndex++; p.sourceIndex < len(p.target.Sources); p.sourceIndex++ {
+		if p.target.Sources[p.sourceIndex] == st.NodeID {
+			continue
+		}
+
+		return &statemachine.EventList{}, st.fetchChunk(0)
+	}
+
+	st.pending = nil
+	return (&statemachine.EventList{}).StateTransferFailed(p.target), nil
+}
+
+// fetchChunk must be called with the mutex held.
+func (st *StateTransfer) fetchChunk(index uint64) *chunkFetch {
+	p := st.pending
+	return &chunkFetch{
+		source: p.target.Sources[p.sourceIndex],
+		msg: &msgs.Msg{
+			Type: &msgs.Msg_FetchCheckpointChunk{
+				FetchCheckpointChunk: &msgs.FetchCheckpointChunk{
+					SeqNo: p.target.SeqNo,
+					Value: p.target.Value,
+					Index: index,
+				},
+			},
+		},
+	}
+}
+
+func (st *StateTransfer) step(source uint64, msg *msgs.Msg) (*statemachine.EventList, error) {
+	switch t := msg.Type.(type) {
+	case *msgs.Msg_FetchCheckpointChunk:
+		st.serveChunk(source, t.FetchCheckpointChunk)
+		return &statemachine.EventList{}, nil
+	case *msgs.Msg_CheckpointChunk:
+		return st.applyChunk(source, t.CheckpointChunk)
+	default:
+		return nil, errors.Errorf("unexpected state transfer message type %T", msg.Type)
+	}
+}
+
+func (st *StateTransfer) serveChunk(source uint64, fetch *msgs.FetchCheckpointChunk) {
+	st.mutex.Lock()
+	var data []byte
+	for _, snap := range st.snapshots {
+		if bytes.Equal(snap.value, fetch.Value) {
+			data = snap.data
+			break
+		}
+	}
+	st.mutex.Unlock()
+
+	chunkSize := st.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	reply := &msgs.CheckpointChunk{
+		SeqNo: fetch.SeqNo,
+		Value: fetch.Value,
+		Index: fetch.Index,
+	}
+
+	if data != nil {
+		// Note, empty checkpoint data is still sent as a single empty chunk.
+		reply.TotalChunks = uint64((len(data) + chunkSize - 1) / chunkSize)
+		if reply.TotalChunks == 0 {
+			reply.TotalChunks = 1
+		}
+
+		if fetch.Index < reply.TotalChunks {
+			start := int(fetch.Index) * chunkSize
+			end := start + chunkSize
+			if end > len(data) {
+				end = len(data)
+			}
+			reply.Data = data[start:end]
+		}
+	}
+
+	st.Link.Send(source, &msgs.Msg{
+		Type: &msgs.Msg_CheckpointChunk{
+			CheckpointChunk: reply,
+		},
+	})
+}
+
+func (st *StateTransfer) applyChunk(source uint64, chunk *msgs.CheckpointChunk) (*statemachine.EventList, error) {
+	events, fetch := st.addChunk(source, chunk)
+	st.send(fetch)
+	return events, nil
+}
+
+// addChunk adds the chunk to the pending transfer.  Once all chunks have been
+// received and the data hashes to the target value, the data is retained until
+// the app transfers to it, see Fetched.
+func (st *StateTransfer) addChunk(source uint64, chunk *msgs.CheckpointChunk) (*statemachine.EventList, *chunkFetch) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	p := st.pending
+	if p == nil ||
+		p.target.Sources[p.sourceIndex] != source ||
+		p.target.SeqNo != chunk.SeqNo ||
+		!bytes.Equal(p.target.Value, chunk.Value) ||
+		uint64(len(p.chunks)) != chunk.Index {
+		// This chunk is stale or unsolicited, ignore it.
+		return &statemachine.EventList{}, nil
+	}
+
+	maxChunks := st.MaxChunks
+	if maxChunks <= 0 {
+		maxChunks = defaultMaxChunks
+	}
+
+	maxDataSize := st.MaxDataSize
+	if maxDataSize <= 0 {
+		maxDataSize = defaultMaxDataSize
+	}
+
+	if chunk.TotalChunks == 0 ||
+		chunk.TotalChunks > uint64(maxChunks) ||
+		chunk.Index >= chunk.TotalChunks ||
+		(p.totalChunks != 0 && p.totalChunks != chunk.TotalChunks) ||
+		len(chunk.Data) > maxDataSize-p.size {
+		// The source does not have the data, or is misbehaving.
+		return st.nextSource()
+	}
+
+	p.totalChunks = chunk.TotalChunks
+	p.chunks = append(p.chunks, chunk.Data)
+	p.size += len(chunk.Data)
+	p.idleTicks = 0
+
+	if uint64(len(p.chunks)) < p.totalChunks {
+		return &statemachine.EventList{}, st.fetchChunk(uint64(len(p.chunks)))
+	}
+
+	data := bytes.Join(p.chunks, nil)
+	h := st.Hasher.New()
+	h.Write(data)
+	if !bytes.Equal(h.Sum(nil), p.target.Value) {
+		return st.nextSource()
+	}
+
+	st.pending = nil
+	st.fetched = &fetchedTransfer{
+		target: p.target,
+		data:   data,
+	}
+
+	return &statemachine.EventList{}, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor_test
+
+import (
+	"crypto"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+type transferApp struct {
+	transfers [][]byte
+}
+
+func (ta *transferApp) Apply(*msgs.QEntry) error {
+	return nil
+}
+
+func (ta *transferApp) Snap(*msgs.NetworkState_Config, []*msgs.NetworkState_Client) ([]byte, []*msgs.Reconfiguration, error) {
+	return nil, nil, nil
+}
+
+func (ta *transferApp) TransferTo(seqNo uint64, snap []byte) (*msgs.NetworkState, error) {
+	ta.transfers = append(ta.transfers, snap)
+	return &msgs.NetworkState{}, nil
+}
+
+// sendLink records the messages sent, and invokes onSend for each.
+type sendLink struct {
+	sent   []*msgs.Msg
+	onSend func()
+}
+
+func (sl *sendLink) Send(dest uint64, msg *msgs.Msg) {
+	sl.sent = append(sl.sent, msg)
+	if sl.onSend != nil {
+		sl.onSend()
+	}
+}
+
+var _ = Describe("StateTransfer", func() {
+	var (
+		app           *transferApp
+		link          *sendLink
+		stateTransfer *processor.StateTransfer
+		replicas      *processor.Replicas
+		data          []byte
+		transfer      *statemachine.ActionList
+	)
+
+	BeforeEach(func() {
+		app = &transferApp{}
+		link = &sendLink{}
+		stateTransfer = &processor.StateTransfer{
+			NodeID:    0,
+			Link:      link,
+			Hasher:    crypto.SHA256,
+			App:       app,
+			ChunkSize: 4,
+		}
+		replicas = &processor.Replicas{
+			StateTransfer: stateTransfer,
+		}
+
+		data = []byte("checkpoint-data")
+		transfer = (&statemachine.ActionList{}).StateTransfer(5, digest(data), []uint64{0, 1})
+	})
+
+	chunk := func(index uint64) *msgs.Msg {
+		end := int(index+1) * 4
+		if end > len(data) {
+			end = len(data)
+		}
+		return &msgs.Msg{
+			Type: &msgs.Msg_CheckpointChunk{
+				CheckpointChunk: &msgs.CheckpointChunk{
+					SeqNo:       5,
+					Value:       digest(data),
+					Index:       index,
+					TotalChunks: 4,
+					Data:        data[int(index)*4 : end],
+				},
+			},
+		}
+	}
+
+	It("transfers the app to the fetched data only while processing app actions", func() {
+		events, err := processor.ProcessAppActions(app, stateTransfer, transfer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events.Len()).To(Equal(0))
+		Expect(link.sent).To(HaveLen(1))
+
+		for i := uint64(0); i < 4; i++ {
+			events, err := replicas.Replica(1).Step(chunk(i))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events.Len()).To(Equal(0))
+		}
+		Expect(link.sent).To(HaveLen(4))
+		Expect(app.transfers).To(BeEmpty())
+
+		fetched := stateTransfer.Fetched()
+		Expect(fetched).To(Equal(transfer))
+		Expect(stateTransfer.Fetched().Len()).To(Equal(0))
+
+		events, err = processor.ProcessAppActions(app, stateTransfer, fetched)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(app.transfers).To(Equal([][]byte{data}))
+		Expect(events.Len()).To(Equal(1))
+		_, ok := events.Iterator().Next().Type.(*state.Event_StateTransferComplete)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("sends chunk fetches without holding the lock", func() {
+		// Fetched takes the lock, and so would deadlock were it held.
+		link.onSend = func() {
+			stateTransfer.Fetched()
+		}
+
+		transfer = (&statemachine.ActionList{}).StateTransfer(5, digest(data), []uint64{0, 1, 2})
+		_, err := processor.ProcessAppActions(app, stateTransfer, transfer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(link.sent).To(HaveLen(1))
+
+		By("moving on to the next source once the fetch times out")
+		for i := 0; i < 4; i++ {
+			stateTransfer.Tick()
+		}
+		Expect(link.sent).To(HaveLen(2))
+
+		By("fetching the next chunk once a chunk arrives")
+		_, err = replicas.Replica(2).Step(chunk(0))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(link.sent).To(HaveLen(3))
+	})
+})
@@ -131,6 +131,21 @@ func (pi *WorkItems) AddWALResults(actions *statemachine.ActionList) {
 	pi.NetActions().PushBackList(actions)
 }
 
+// AddStepResults splits the events resulting from stepping a message
+// into those which must first be synced to the request store (forwarded
+// requests) and those which may be applied to the state machine directly.
+func (pi *WorkItems) AddStepResults(events *statemachine.EventList) {
+	iter := events.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		switch event.Type.(type) {
+		case *state.Event_RequestPersisted:
+			pi.ReqStoreEvents().PushBack(event)
+		default:
+			pi.ResultEvents().PushBack(event)
+		}
+	}
+}
+
 func (pi *WorkItems) AddReqStoreResults(events *statemachine.EventList) {
 	pi.ResultEvents().PushBackList(events)
 }
@@ -174,9 +189,14 @@ func (pi *WorkItems) AddStateMachineResults(actions *statemachine.ActionList) {
 			pi.ClientActions().PushBack(action)
 			// TODO, create replicas
 		case *state.Action_ForwardRequest:
-			// XXX address
+			// Any request we forward, we have already acked, and
+			// therefore persisted, so no need to wait for the WAL.
+			pi.NetActions().PushBack(action)
 		case *state.Action_StateTransfer:
 			pi.AppActions().PushBack(action)
+		case *state.Action_Oddity:
+			// Oddities are reported as the state machine produces
+			// them, and require no further work.
 		}
 	}
 }
@@ -12,9 +12,13 @@ package reqstore
 
 import (
 	"fmt"
-	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"strconv"
+	"strings"
+
 	badger "github.com/dgraph-io/badger/v2"
 	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 )
 
 func reqKey(ack *msgs.RequestAck) []byte {
@@ -25,6 +29,21 @@ func allocKey(clientID, reqNo uint64) []byte {
 	return []byte(fmt.Sprintf("alloc-%d.%d", clientID, reqNo))
 }
 
+func reqPrefix(clientID uint64) []byte {
+	return []byte(fmt.Sprintf("req-%d.", clientID))
+}
+
+func allocPrefix(clientID uint64) []byte {
+	return []byte(fmt.Sprintf("alloc-%d.", clientID))
+}
+
+// keyReqNo extracts the request number from a request or allocation
+// key, given the client specific prefix of that key.
+func keyReqNo(key, prefix []byte) (uint64, error) {
+	reqNo := strings.SplitN(string(key[len(prefix):]), ".", 2)[0]
+	return strconv.ParseUint(reqNo, 10, 64)
+}
+
 type Store struct {
 	db *badger.DB
 }
@@ -103,6 +122,55 @@ func (s *Store) Commit(ack *msgs.RequestAck) error {
 	})
 }
 
+// GarbageCollect deletes the allocations and request data of the given client
+// for all request numbers below the low watermark.
+func (s *Store) GarbageCollect(clientID, lowWatermark uint64) error {
+	var keys [][]byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		for _, prefix := range [][]byte{reqPrefix(clientID), allocPrefix(clientID)} {
+			it := txn.NewIterator(badger.IteratorOptions{
+				Prefix: prefix,
+			})
+
+			for it.Rewind(); it.Valid(); it.Next() {
+				key := it.Item().KeyCopy(nil)
+				reqNo, err := keyReqNo(key, prefix)
+				if err != nil {
+					it.Close()
+					return errors.WithMessagef(err, "could not parse key %s", key)
+				}
+
+				if reqNo < lowWatermark {
+					keys = append(keys, key)
+				}
+			}
+
+			it.Close()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	// A write batch is used, as the deletions might exceed the
+	// size limit of a single transaction.
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, key := range keys {
+		if err := wb.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return wb.Flush()
+}
+
 func (s *Store) Sync() error {
 	return s.db.Sync()
 }
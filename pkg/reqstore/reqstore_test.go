@@ -87,4 +87,37 @@ var _ = Describe("Reqstore", func() {
 		_ = reqStore
 		// XXX need to actually test this
 	})
+
+	It("garbage collects requests and allocations below the low watermark", func() {
+		for _, ack := range []*msgs.RequestAck{ack1dot3, ack2dot1, ack2dot2} {
+			err := reqStore.PutAllocation(ack.ClientId, ack.ReqNo, ack.Digest)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		err := reqStore.GarbageCollect(1, 4)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := reqStore.GetRequest(ack1dot3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(BeNil())
+
+		digest, err := reqStore.GetAllocation(1, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(BeNil())
+
+		err = reqStore.GarbageCollect(2, 2)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err = reqStore.GetRequest(ack2dot1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(BeNil())
+
+		data, err = reqStore.GetRequest(ack2dot2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal([]byte("data2dot2")))
+
+		digest, err = reqStore.GetAllocation(2, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal(ack2dot2.Digest))
+	})
 })
@@ -0,0 +1,650 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package segmentwal is a WAL implementation intended for production use.  Entries
+// are appended to preallocated segment files, each entry is aligned to an 8 byte
+// boundary, and each entry carries a CRC so that corruption may be detected.
+//
+// Each entry is laid out as:
+//
+//	length uint32 | crc uint32 | index uint64 | data [length]byte | padding
+//
+// where all integers are little endian, the CRC is the CRC-32C of the index and
+// data, and the padding aligns the next entry to 8 bytes.  Because segments are
+// zero filled when they are allocated, an all zero header marks the end of the
+// entries in a segment.
+//
+// Whenever the WAL is synced, the index of the last synced entry is recorded.
+// When a WAL is opened, every entry is validated.  An invalid entry beyond the
+// last synced index is assumed to be the result of a crash, for instance a power
+// loss, before the unsynced tail of the log reached the disk.  As the tail may
+// have been written out of order, it is zeroed from the first invalid entry on,
+// even if valid entries follow.  Any other invalid entry indicates corruption of
+// the log, and the WAL refuses to open.
+package segmentwal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+)
+
+const (
+	headerSize         = 16
+	alignment          = 8
+	defaultSegmentSize = 16 * 1024 * 1024
+	segmentSuffix      = ".seg"
+	lowIndexFile       = "low_index"
+	syncedIndexFile    = "synced_index"
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Options configures a WAL.  The zero value selects sensible defaults.
+type Options struct {
+	// SegmentSize is the size in bytes to which each segment is preallocated,
+	// it is rounded up to the alignment, and defaults to 16MB.  Entries larger
+	// than the segment size are written to a segment of their own.
+	SegmentSize int64
+}
+
+type segment struct {
+	firstIndex uint64
+	path       string
+	size       int64
+}
+
+type WAL struct {
+	mutex       sync.Mutex
+	dir         string
+	segmentSize int64
+	segments    []*segment
+	lowIndex    uint64
+	lastIndex   uint64
+	syncedIndex uint64
+	syncedFile  *os.File
+	file        *os.File
+	offset      int64
+}
+
+// Open opens the WAL stored in the given directory, creating it if it does not
+// exist.  A nil options uses the defaults.  An error is returned if the log is
+// corrupt, other than in its unsynced tail, which is truncated.
+func Open(path string, options *Options) (*WAL, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	segmentSize := options.SegmentSize
+	if segmentSize <= 0 {
+		segmentSize = defaultSegmentSize
+	}
+
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, errors.WithMessage(err, "could not create WAL directory")
+	}
+
+	w := &WAL{
+		dir:         path,
+		segmentSize: align(segmentSize),
+	}
+
+	if err := w.readLowIndex(); err != nil {
+		return nil, err
+	}
+
+	if err := w.readSyncedIndex(); err != nil {
+		return nil, err
+	}
+
+	if err := w.readSegments(); err != nil {
+		w.syncedFile.Close()
+		return nil, err
+	}
+
+	if err := w.recover(); err != nil {
+		w.syncedFile.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *WAL) readLowIndex() error {
+	data, err := ioutil.ReadFile(filepath.Join(w.dir, lowIndexFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.WithMessage(err, "could not read low index")
+	}
+
+	if len(data) != 12 || crc32.Checksum(data[:8], crcTable) != binary.LittleEndian.Uint32(data[8:]) {
+		return errors.Errorf("low index file is corrupt")
+	}
+
+	w.lowIndex = binary.LittleEndian.Uint64(data[:8])
+	return nil
+}
+
+func (w *WAL) writeLowIndex() error {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint64(data[:8], w.lowIndex)
+	binary.LittleEndian.PutUint32(data[8:], crc32.Checksum(data[:8], crcTable))
+
+	tmpPath := filepath.Join(w.dir, lowIndexFile+".tmp")
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.WithMessage(err, "could not create low index file")
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return errors.WithMessage(err, "could not write low index file")
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.WithMessage(err, "could not sync low index file")
+	}
+
+	if err := f.Close(); err != nil {
+		return errors.WithMessage(err, "could not close low index file")
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(w.dir, lowIndexFile)); err != nil {
+		return errors.WithMessage(err, "could not replace low index file")
+	}
+
+	return w.syncDir()
+}
+
+// readSyncedIndex opens the synced index file, creating it if it does not
+// exist, and reads the index of the last synced entry.  The synced index is
+// overwritten in place, so should its write have been torn by a crash, it
+// is ignored, and the entries of the last segment are assumed to be unsynced.
+func (w *WAL) readSyncedIndex() error {
+	path := filepath.Join(w.dir, syncedIndexFile)
+	_, err := os.Stat(path)
+	created := os.IsNotExist(err)
+
+	w.syncedFile, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return errors.WithMessage(err, "could not open synced index file")
+	}
+
+	if created {
+		return w.syncDir()
+	}
+
+	data, err := ioutil.ReadAll(w.syncedFile)
+	if err != nil {
+		w.syncedFile.Close()
+		return errors.WithMessage(err, "could not read synced index")
+	}
+
+	if len(data) == 12 && crc32.Checksum(data[:8], crcTable) == binary.LittleEndian.Uint32(data[8:]) {
+		w.syncedIndex = binary.LittleEndian.Uint64(data[:8])
+	}
+
+	return nil
+}
+
+// sync syncs the current segment, then records its last entry as synced.
+func (w *WAL) sync() error {
+	if err := w.file.Sync(); err != nil {
+		return errors.WithMessage(err, "could not sync segment")
+	}
+
+	if w.lastIndex <= w.syncedIndex {
+		return nil
+	}
+
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint64(data[:8], w.lastIndex)
+	binary.LittleEndian.PutUint32(data[8:], crc32.Checksum(data[:8], crcTable))
+
+	if _, err := w.syncedFile.WriteAt(data, 0); err != nil {
+		return errors.WithMessage(err, "could not write synced index")
+	}
+
+	if err := w.syncedFile.Sync(); err != nil {
+		return errors.WithMessage(err, "could not sync synced index")
+	}
+
+	w.syncedIndex = w.lastIndex
+	return nil
+}
+
+func (w *WAL) readSegments() error {
+	infos, err := ioutil.ReadDir(w.dir)
+	if err != nil {
+		return errors.WithMessage(err, "could not list WAL directory")
+	}
+
+	for _, info := range infos {
+		name := info.Name()
+		if !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		firstIndex, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			return errors.WithMessagef(err, "unexpected segment file name %s", name)
+		}
+
+		w.segments = append(w.segments, &segment{
+			firstIndex: firstIndex,
+			path:       filepath.Join(w.dir, name),
+			size:       info.Size(),
+		})
+	}
+
+	sort.Slice(w.segments, func(i, j int) bool {
+		return w.segments[i].firstIndex < w.segments[j].firstIndex
+	})
+
+	return nil
+}
+
+// recover validates every segment, truncating the unsynced tail of the last
+// segment from its first invalid entry, and opens the last segment for appending.
+func (w *WAL) recover() error {
+	// A crash during Truncate may leave segments which were to be removed.
+	if err := w.removeSegmentsBelow(w.lowIndex); err != nil {
+		return err
+	}
+
+	for i, seg := range w.segments {
+		data, err := ioutil.ReadFile(seg.path)
+		if err != nil {
+			return errors.WithMessagef(err, "could not read segment %s", seg.path)
+		}
+
+		if w.lastIndex != 0 && seg.firstIndex != w.lastIndex+1 {
+			return errors.Errorf("WAL is corrupt, segment %s does not follow index %d", seg.path, w.lastIndex)
+		}
+
+		offset, lastIndex, valid := scan(data, seg.firstIndex, nil)
+		invalidIndex := seg.firstIndex
+		if lastIndex != 0 {
+			w.lastIndex = lastIndex
+			invalidIndex = lastIndex + 1
+		}
+
+		last := i == len(w.segments)-1
+		if !last && lastIndex == 0 {
+			return errors.Errorf("WAL is corrupt, segment %s contains no entries", seg.path)
+		}
+
+		if !valid {
+			// Earlier segments are synced before rolling over to the next.
+			if !last || invalidIndex <= w.syncedIndex {
+				return errors.Errorf("WAL is corrupt, invalid entry in segment %s at offset %d", seg.path, offset)
+			}
+
+			// The unsynced tail of the log was not entirely written, zero it out.
+			if err := zeroFill(seg.path, offset, seg.size); err != nil {
+				return errors.WithMessage(err, "could not truncate unsynced tail")
+			}
+		}
+
+		if last {
+			w.file, err = os.OpenFile(seg.path, os.O_RDWR, 0600)
+			if err != nil {
+				return errors.WithMessagef(err, "could not open segment %s", seg.path)
+			}
+			w.offset = offset
+		}
+	}
+
+	return nil
+}
+
+// scan walks the entries of a segment, invoking forEach (if non-nil) for each valid
+// entry.  It returns the offset after the last valid entry, the index of the last
+// valid entry (or zero if there were none), and whether the entries ended cleanly
+// rather than with an invalid entry.
+func scan(data []byte, firstIndex uint64, forEach func(index uint64, entryData []byte)) (int64, uint64, bool) {
+	var offset int64
+	var lastIndex uint64
+	expectedIndex := firstIndex
+	for {
+		if offset+headerSize > int64(len(data)) {
+			return offset, lastIndex, true
+		}
+
+		length, crc, index := decodeHeader(data[offset:])
+		if length == 0 && crc == 0 && index == 0 {
+			return offset, lastIndex, true
+		}
+
+		entryData, ok := entryAt(data, offset, length, crc)
+		if !ok || index != expectedIndex {
+			return offset, lastIndex, false
+		}
+
+		if forEach != nil {
+			forEach(index, entryData)
+		}
+
+		lastIndex = index
+		expectedIndex++
+		offset += entrySize(len(entryData))
+	}
+}
+
+func decodeHeader(data []byte) (uint32, uint32, uint64) {
+	return binary.LittleEndian.Uint32(data[0:4]),
+		binary.LittleEndian.Uint32(data[4:8]),
+		binary.LittleEndian.Uint64(data[8:16])
+}
+
+func entryAt(data []byte, offset int64, length, crc uint32) ([]byte, bool) {
+	end := offset + headerSize + int64(length)
+	if end > int64(len(data)) {
+		return nil, false
+	}
+
+	if crc32.Checksum(data[offset+8:end], crcTable) != crc {
+		return nil, false
+	}
+
+	return data[offset+headerSize : end], true
+}
+
+func entrySize(dataLen int) int64 {
+	return align(int64(headerSize + dataLen))
+}
+
+func align(size int64) int64 {
+	return (size + alignment - 1) / alignment * alignment
+}
+
+var zeros = make([]byte, 64*1024)
+
+// zeroFill writes zeros to the file from start to end, and syncs it.
+func zeroFill(path string, start, end int64) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err := writeZeros(f, start, end); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func writeZeros(f *os.File, start, end int64) error {
+	for offset := start; offset < end; offset += int64(len(zeros)) {
+		chunk := zeros
+		if end-offset < int64(len(chunk)) {
+			chunk = chunk[:end-offset]
+		}
+
+		if _, err := f.WriteAt(chunk, offset); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *WAL) IsEmpty() (bool, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.lastIndex == 0 || w.lastIndex < w.lowIndex, nil
+}
+
+func (w *WAL) LoadAll(forEach func(index uint64, p *msgs.Persistent)) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, seg := range w.segments {
+		data, err := ioutil.ReadFile(seg.path)
+		if err != nil {
+			return errors.WithMessagef(err, "could not read segment %s", seg.path)
+		}
+
+		var decodeErr error
+		_, _, valid := scan(data, seg.firstIndex, func(index uint64, entryData []byte) {
+			if decodeErr != nil || index < w.lowIndex || index > w.lastIndex {
+				return
+			}
+
+			result := &msgs.Persistent{}
+			if err := proto.Unmarshal(entryData, result); err != nil {
+				decodeErr = errors.WithMessagef(err, "error decoding entry at index %d to proto", index)
+				return
+			}
+
+			forEach(index, result)
+		})
+
+		if decodeErr != nil {
+			return decodeErr
+		}
+
+		if !valid {
+			return errors.Errorf("WAL is corrupt, invalid entry in segment %s", seg.path)
+		}
+	}
+
+	return nil
+}
+
+func (w *WAL) Write(index uint64, p *msgs.Persistent) error {
+	data, err := proto.Marshal(p)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal")
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	switch {
+	case w.lastIndex != 0 && index != w.lastIndex+1:
+		return errors.Errorf("cannot write index %d, expected index %d", index, w.lastIndex+1)
+	case w.lastIndex == 0 && w.file != nil && index != w.segments[len(w.segments)-1].firstIndex:
+		return errors.Errorf("cannot write index %d, expected index %d", index, w.segments[len(w.segments)-1].firstIndex)
+	}
+
+	size := entrySize(len(data))
+	if w.file == nil || w.offset+size > w.segments[len(w.segments)-1].size {
+		if err := w.rollover(index, size); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, size)
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(data)))
+	binary.LittleEndian.PutUint64(buf[8:16], index)
+	copy(buf[headerSize:], data)
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(buf[8:headerSize+len(data)], crcTable))
+
+	if _, err := w.file.WriteAt(buf, w.offset); err != nil {
+		return errors.WithMessagef(err, "could not write index %d", index)
+	}
+
+	w.offset += size
+	w.lastIndex = index
+	return nil
+}
+
+// rollover syncs and closes the current segment, then creates and preallocates
+// a new segment whose first entry will be the given index.  If a crash during
+// a previous rollover left an empty segment for the index, possibly only
+// partially preallocated, that segment is preallocated again and reused.
+func (w *WAL) rollover(index uint64, entrySize int64) error {
+	if w.file != nil {
+		if err := w.sync(); err != nil {
+			return err
+		}
+
+		if err := w.file.Close(); err != nil {
+			return errors.WithMessage(err, "could not close segment")
+		}
+		w.file = nil
+	}
+
+	size := w.segmentSize
+	if entrySize > size {
+		size = entrySize
+	}
+
+	var existing *segment
+	if len(w.segments) > 0 && w.segments[len(w.segments)-1].firstIndex == index {
+		// As no entries follow the first index, the segment must be empty.
+		existing = w.segments[len(w.segments)-1]
+	}
+
+	path := filepath.Join(w.dir, fmt.Sprintf("%020d%s", index, segmentSuffix))
+	flags := os.O_CREATE | os.O_EXCL | os.O_RDWR
+	if existing != nil {
+		flags = os.O_RDWR
+	}
+
+	f, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		return errors.WithMessage(err, "could not create segment")
+	}
+
+	// Zeros are written rather than simply extending the file, so that the
+	// blocks are actually allocated and appends need not modify metadata.
+	if err := writeZeros(f, 0, size); err != nil {
+		f.Close()
+		return errors.WithMessage(err, "could not preallocate segment")
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.WithMessage(err, "could not sync segment")
+	}
+
+	if err := w.syncDir(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if existing != nil {
+		existing.size = size
+	} else {
+		w.segments = append(w.segments, &segment{
+			firstIndex: index,
+			path:       path,
+			size:       size,
+		})
+	}
+	w.file = f
+	w.offset = 0
+	return nil
+}
+
+// Truncate discards all entries with an index below the given index.  Whole
+// segments are removed once all of their entries have been discarded.
+func (w *WAL) Truncate(index uint64) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if index <= w.lowIndex {
+		return nil
+	}
+
+	w.lowIndex = index
+	if err := w.writeLowIndex(); err != nil {
+		return err
+	}
+
+	return w.removeSegmentsBelow(index)
+}
+
+// removeSegmentsBelow removes every segment, other than the last, whose
+// entries all have indices below the given index.
+func (w *WAL) removeSegmentsBelow(index uint64) error {
+	removed := 0
+	for removed < len(w.segments)-1 && w.segments[removed+1].firstIndex <= index {
+		if err := os.Remove(w.segments[removed].path); err != nil {
+			return errors.WithMessagef(err, "could not remove segment %s", w.segments[removed].path)
+		}
+		removed++
+	}
+
+	if removed == 0 {
+		return nil
+	}
+
+	w.segments = w.segments[removed:]
+	return w.syncDir()
+}
+
+func (w *WAL) syncDir() error {
+	d, err := os.Open(w.dir)
+	if err != nil {
+		return errors.WithMessage(err, "could not open WAL directory")
+	}
+
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return errors.WithMessage(err, "could not sync WAL directory")
+	}
+
+	return d.Close()
+}
+
+func (w *WAL) Sync() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	return w.sync()
+}
+
+func (w *WAL) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file != nil {
+		if err := w.sync(); err != nil {
+			return err
+		}
+
+		if err := w.file.Close(); err != nil {
+			return errors.WithMessage(err, "could not close segment")
+		}
+		w.file = nil
+	}
+
+	if w.syncedFile == nil {
+		return nil
+	}
+
+	err := w.syncedFile.Close()
+	w.syncedFile = nil
+	return err
+}
@@ -0,0 +1,13 @@
+package segmentwal_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSegmentwal(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Segmentwal Suite")
+}
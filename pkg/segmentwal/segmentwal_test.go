@@ -0,0 +1,222 @@
+package segmentwal_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/segmentwal"
+)
+
+func entry(seqNo uint64) *msgs.Persistent {
+	return &msgs.Persistent{
+		Type: &msgs.Persistent_CEntry{
+			CEntry: &msgs.CEntry{
+				SeqNo: seqNo,
+			},
+		},
+	}
+}
+
+// entrySize mirrors the on disk layout, a 16 byte header followed by the
+// data, padded to 8 bytes.
+func entrySize(seqNo uint64) int64 {
+	return (16 + int64(proto.Size(entry(seqNo))) + 7) / 8 * 8
+}
+
+var _ = Describe("Segmentwal", func() {
+	var (
+		tmpDir  string
+		options *segmentwal.Options
+		wal     *segmentwal.WAL
+		crashed []*segmentwal.WAL
+	)
+
+	loadAll := func(wal *segmentwal.WAL) []uint64 {
+		var indices []uint64
+		err := wal.LoadAll(func(index uint64, p *msgs.Persistent) {
+			Expect(p.Type.(*msgs.Persistent_CEntry).CEntry.SeqNo).To(Equal(index))
+			indices = append(indices, index)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return indices
+	}
+
+	segments := func() []string {
+		paths, err := filepath.Glob(filepath.Join(tmpDir, "*.seg"))
+		Expect(err).NotTo(HaveOccurred())
+		sort.Strings(paths)
+		return paths
+	}
+
+	corrupt := func(path string, offset int64) {
+		f, err := os.OpenFile(path, os.O_RDWR, 0600)
+		Expect(err).NotTo(HaveOccurred())
+		defer f.Close()
+
+		b := make([]byte, 1)
+		_, err = f.ReadAt(b, offset)
+		Expect(err).NotTo(HaveOccurred())
+		b[0] ^= 0xff
+		_, err = f.WriteAt(b, offset)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "segmentwal-test-*")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Five entries fit in each segment.
+		options = &segmentwal.Options{
+			SegmentSize: 5 * entrySize(1),
+		}
+
+		wal, err = segmentwal.Open(tmpDir, options)
+		Expect(err).NotTo(HaveOccurred())
+
+		empty, err := wal.IsEmpty()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(empty).To(BeTrue())
+
+		for i := uint64(1); i <= 12; i++ {
+			Expect(wal.Write(i, entry(i))).To(Succeed())
+		}
+		Expect(wal.Sync()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if wal != nil {
+			wal.Close()
+		}
+		for _, w := range crashed {
+			w.Close()
+		}
+		crashed = nil
+		os.RemoveAll(tmpDir)
+	})
+
+	reopen := func() error {
+		Expect(wal.Close()).To(Succeed())
+		var err error
+		wal, err = segmentwal.Open(tmpDir, options)
+		return err
+	}
+
+	// crash opens the WAL again without closing it, and so without
+	// syncing the entries written since it was last synced.
+	crash := func() error {
+		crashed = append(crashed, wal)
+		var err error
+		wal, err = segmentwal.Open(tmpDir, options)
+		return err
+	}
+
+	It("loads the written entries across segments", func() {
+		Expect(segments()).To(HaveLen(3))
+		Expect(loadAll(wal)).To(Equal([]uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}))
+
+		Expect(reopen()).To(Succeed())
+		empty, err := wal.IsEmpty()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(empty).To(BeFalse())
+		Expect(loadAll(wal)).To(Equal([]uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}))
+
+		Expect(wal.Write(13, entry(13))).To(Succeed())
+		Expect(loadAll(wal)).To(HaveLen(13))
+	})
+
+	It("rejects out of order writes", func() {
+		Expect(wal.Write(14, entry(14))).To(MatchError("cannot write index 14, expected index 13"))
+	})
+
+	It("truncates entries and removes unneeded segments", func() {
+		Expect(wal.Truncate(8)).To(Succeed())
+		Expect(segments()).To(HaveLen(2))
+		Expect(loadAll(wal)).To(Equal([]uint64{8, 9, 10, 11, 12}))
+
+		Expect(reopen()).To(Succeed())
+		Expect(loadAll(wal)).To(Equal([]uint64{8, 9, 10, 11, 12}))
+	})
+
+	It("recovers from a torn write at the tail", func() {
+		Expect(wal.Write(13, entry(13))).To(Succeed())
+		Expect(wal.Write(14, entry(14))).To(Succeed())
+
+		last := segments()[2]
+		corrupt(last, entrySize(11)+entrySize(12)+entrySize(13)+17)
+
+		Expect(crash()).To(Succeed())
+		Expect(loadAll(wal)).To(Equal([]uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13}))
+
+		Expect(wal.Write(14, entry(14))).To(Succeed())
+		Expect(reopen()).To(Succeed())
+		Expect(loadAll(wal)).To(HaveLen(14))
+	})
+
+	It("truncates the unsynced tail from an invalid entry followed by a valid one", func() {
+		Expect(wal.Write(13, entry(13))).To(Succeed())
+		Expect(wal.Write(14, entry(14))).To(Succeed())
+
+		last := segments()[2]
+		corrupt(last, entrySize(11)+entrySize(12)+17)
+
+		Expect(crash()).To(Succeed())
+		Expect(loadAll(wal)).To(Equal([]uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}))
+
+		Expect(wal.Write(13, entry(13))).To(Succeed())
+		Expect(reopen()).To(Succeed())
+		Expect(loadAll(wal)).To(HaveLen(13))
+	})
+
+	It("recovers from a crash while preallocating a new segment", func() {
+		for i := uint64(13); i <= 15; i++ {
+			Expect(wal.Write(i, entry(i))).To(Succeed())
+		}
+		Expect(wal.Close()).To(Succeed())
+
+		// The next segment was created, but only partially preallocated.
+		short := filepath.Join(tmpDir, "00000000000000000016.seg")
+		Expect(ioutil.WriteFile(short, make([]byte, 3), 0600)).To(Succeed())
+
+		var err error
+		wal, err = segmentwal.Open(tmpDir, options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loadAll(wal)).To(HaveLen(15))
+
+		for i := uint64(16); i <= 18; i++ {
+			Expect(wal.Write(i, entry(i))).To(Succeed())
+		}
+		Expect(segments()).To(HaveLen(4))
+
+		info, err := os.Stat(short)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Size()).To(Equal(options.SegmentSize))
+
+		Expect(reopen()).To(Succeed())
+		Expect(loadAll(wal)).To(HaveLen(18))
+	})
+
+	It("refuses to open with corruption in the synced entries of the last segment", func() {
+		last := segments()[2]
+		corrupt(last, entrySize(11)+17)
+
+		err := reopen()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("WAL is corrupt"))
+	})
+
+	It("refuses to open with corruption in an earlier segment", func() {
+		corrupt(segments()[1], 4*entrySize(6)+17)
+
+		err := reopen()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("WAL is corrupt"))
+	})
+})
@@ -6,7 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 // Package simplewal is a basic WAL implementation meant to be the first 'real' WAL
 // option for mirbft.  More sophisticated WALs with checksums, byte alignments, etc.
-// may be produced in the future, but this is just a simple place to start.
+// may be produced in the future, but this is just a simple place to start.  See
+// package segmentwal for a WAL with checksums and recovery from torn writes.
 package simplewal
 
 import (
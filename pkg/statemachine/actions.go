@@ -202,17 +202,35 @@ func ActionStateApplied(seqNo uint64, ns *msgs.NetworkState) *state.Action {
 	}
 }
 
-func (al *ActionList) StateTransfer(seqNo uint64, value []byte) *ActionList {
-	al.PushBack(ActionStateTransfer(seqNo, value))
+func (al *ActionList) StateTransfer(seqNo uint64, value []byte, sources []uint64) *ActionList {
+	al.PushBack(ActionStateTransfer(seqNo, value, sources))
 	return al
 }
 
-func ActionStateTransfer(seqNo uint64, value []byte) *state.Action {
+func ActionStateTransfer(seqNo uint64, value []byte, sources []uint64) *state.Action {
 	return &state.Action{
 		Type: &state.Action_StateTransfer{
 			StateTransfer: &state.ActionStateTarget{
-				SeqNo: seqNo,
-				Value: value,
+				SeqNo:   seqNo,
+				Value:   value,
+				Sources: sources,
+			},
+		},
+	}
+}
+
+func (al *ActionList) Oddity(nodeID uint64, msg *msgs.Msg, rule state.ActionOddity_Rule) *ActionList {
+	al.PushBack(ActionOddity(nodeID, msg, rule))
+	return al
+}
+
+func ActionOddity(nodeID uint64, msg *msgs.Msg, rule state.ActionOddity_Rule) *state.Action {
+	return &state.Action{
+		Type: &state.Action_Oddity{
+			Oddity: &state.ActionOddity{
+				NodeId: nodeID,
+				Msg:    msg,
+				Rule:   rule,
 			},
 		},
 	}
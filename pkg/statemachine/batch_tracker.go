@@ -28,6 +28,10 @@ type batchTracker struct {
 	// Referring to a batch solely by its digest is thus not enough.
 	fetchInFlight map[string][]uint64
 
+	// Maps the digests of batches being fetched to the nodes which claimed
+	// to have them, and which have not yet forwarded a bad batch instead.
+	fetchSources map[string][]uint64
+
 	persisted *persisted
 }
 
@@ -40,6 +44,7 @@ func newBatchTracker(persisted *persisted) *batchTracker {
 	return &batchTracker{
 		batchesByDigest: map[string]*batch{},
 		fetchInFlight:   map[string][]uint64{},
+		fetchSources:    map[string][]uint64{},
 		persisted:       persisted,
 	}
 }
@@ -103,6 +108,7 @@ func (bt *batchTracker) addBatch(seqNo uint64, digest []byte, requestAcks []*msg
 			b.observedFor[ifSeqNo] = struct{}{}
 		}
 		delete(bt.fetchInFlight, string(digest))
+		delete(bt.fetchSources, string(digest))
 	}
 }
 
@@ -126,6 +132,9 @@ func (bt *batchTracker) fetchBatch(seqNo uint64, digest []byte, sources []uint64
 	// Mark the batch as "being fetched" (if inFlight is nil, it behaves like an empty slice).
 	inFlight = append(inFlight, seqNo)
 	bt.fetchInFlight[string(digest)] = inFlight
+	if !ok {
+		bt.fetchSources[string(digest)] = sources
+	}
 
 	// Request the batch from all nodes that have it.
 	// TODO: If there are many sources, we probably get bombarded by loads of data.
@@ -188,17 +197,19 @@ func (bt *batchTracker) applyForwardBatchMsg(source nodeID, seqNo uint64, digest
 	})
 }
 
-func (bt *batchTracker) applyVerifyBatchHashResult(digest []byte, verifyBatch *state.HashOrigin_VerifyBatch) {
+// applyVerifyBatchHashResult returns false if the forwarded batch does not
+// have the expected digest, in which case it is discarded, and the fetch
+// remains in flight.
+func (bt *batchTracker) applyVerifyBatchHashResult(digest []byte, verifyBatch *state.HashOrigin_VerifyBatch) bool {
 	if !bytes.Equal(verifyBatch.ExpectedDigest, digest) {
-		panic("byzantine")
-		// XXX this should be a log only, but panic-ing to make dev easier for now
+		return false
 	}
 
 	inFlight, ok := bt.fetchInFlight[string(digest)]
 	if !ok {
 		// We must have gotten multiple responses, and already
 		// committed one, which is fine.
-		return
+		return true
 	}
 
 	b, ok := bt.batchesByDigest[string(digest)]
@@ -215,6 +226,57 @@ func (bt *batchTracker) applyVerifyBatchHashResult(digest []byte, verifyBatch *s
 	}
 
 	delete(bt.fetchInFlight, string(digest))
+	delete(bt.fetchSources, string(digest))
+	return true
+}
+
+// refetchBatch discards the source of a forwarded batch which did not have
+// the expected digest, and fetches the batch again from the remaining
+// sources.  Further bad batches from a discarded source are ignored.  Once
+// every source has been discarded, the fetch fails and is no longer in
+// flight, so that the epoch target may fetch the batch anew.
+func (bt *batchTracker) refetchBatch(verifyBatch *state.HashOrigin_VerifyBatch) *ActionList {
+	digest := verifyBatch.ExpectedDigest
+
+	inFlight, ok := bt.fetchInFlight[string(digest)]
+	if !ok {
+		// We already have the batch from another source.
+		return &ActionList{}
+	}
+
+	var sources []uint64
+	discarded := false
+	for _, source := range bt.fetchSources[string(digest)] {
+		if source == verifyBatch.Source {
+			discarded = true
+			continue
+		}
+		sources = append(sources, source)
+	}
+
+	if !discarded {
+		return &ActionList{}
+	}
+
+	if len(sources) == 0 {
+		delete(bt.fetchInFlight, string(digest))
+		delete(bt.fetchSources, string(digest))
+		return &ActionList{}
+	}
+
+	bt.fetchSources[string(digest)] = sources
+
+	return (&ActionList{}).Send(
+		sources,
+		&msgs.Msg{
+			Type: &msgs.Msg_FetchBatch{
+				FetchBatch: &msgs.FetchBatch{
+					SeqNo:  inFlight[0],
+					Digest: digest,
+				},
+			},
+		},
+	)
 }
 
 func (bt *batchTracker) hasFetchInFlight() bool {
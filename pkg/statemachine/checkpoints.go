@@ -22,8 +22,7 @@ type checkpointState int
 const (
 	cpsIdle checkpointState = iota
 	cpsGarbageCollectable
-	cpsPendingReconfig // TODO, implement
-	cpsStateTransfer   // TODO, implement
+	cpsPendingReconfig
 )
 
 type checkpointTracker struct {
@@ -36,6 +35,10 @@ type checkpointTracker struct {
 	networkConfig      *msgs.NetworkState_Config
 	persisted          *persisted
 
+	// reconfigSeqNo is the checkpoint at which a pending reconfiguration
+	// takes effect, or zero if there is none.
+	reconfigSeqNo uint64
+
 	nodeBuffers *nodeBuffers
 	myConfig    *state.EventInitialParameters
 	logger      Logger
@@ -62,6 +65,7 @@ func (ct *checkpointTracker) reinitialize() {
 	ct.activeCheckpoints = list.New()
 	ct.msgBuffers = map[nodeID]*msgBuffer{}
 	ct.networkConfig = nil
+	ct.reconfigSeqNo = 0
 
 	ct.persisted.iterate(logIterator{
 		onCEntry: func(cEntry *msgs.CEntry) {
@@ -70,6 +74,9 @@ func (ct *checkpointTracker) reinitialize() {
 				// time we reinitialize.
 				ct.networkConfig = cEntry.NetworkState.Config
 			}
+			if len(cEntry.NetworkState.PendingReconfigurations) > 0 {
+				ct.reconfigureAt(cEntry.SeqNo + uint64(cEntry.NetworkState.Config.CheckpointInterval))
+			}
 			cp := ct.checkpoint(cEntry.SeqNo)
 			cp.applyCheckpointMsg(nodeID(ct.myConfig.Id), cEntry.CheckpointValue)
 			ct.activeCheckpoints.PushBack(cp)
@@ -169,8 +176,21 @@ func (ct *checkpointTracker) garbageCollect() uint64 {
 		ct.msgBuffers[nodeID(id)].iterate(ct.filter, ct.applyMsg)
 	}
 
-	ct.state = cpsIdle
-	return highestStable.Value.(*checkpoint).seqNo
+	highestStableSeqNo := highestStable.Value.(*checkpoint).seqNo
+	if ct.reconfigSeqNo != 0 && highestStableSeqNo >= ct.reconfigSeqNo {
+		ct.state = cpsPendingReconfig
+	} else {
+		ct.state = cpsIdle
+	}
+
+	return highestStableSeqNo
+}
+
+// reconfigureAt records that the network configuration changes at the given
+// checkpoint, so that once it is stable, the reconfiguration may be applied.
+func (ct *checkpointTracker) reconfigureAt(seqNo uint64) {
+	ct.logger.Log(LevelDebug, "reconfiguration pending at checkpoint", "seq_no", seqNo)
+	ct.reconfigSeqNo = seqNo
 }
 
 func (ct *checkpointTracker) checkpoint(seqNo uint64) *checkpoint {
@@ -240,6 +260,23 @@ func (ct *checkpointTracker) applyCheckpointMsg(source nodeID, seqNo uint64, val
 	}
 }
 
+// highestCommittedCheckpoint returns the checkpoint with the highest sequence
+// number for which a weak quorum agrees on the value, or nil if there is none.
+func (ct *checkpointTracker) highestCommittedCheckpoint() *checkpoint {
+	var highest *checkpoint
+	for _, cp := range ct.checkpointMap {
+		if cp.committedValue == nil {
+			continue
+		}
+
+		if highest == nil || cp.seqNo > highest.seqNo {
+			highest = cp
+		}
+	}
+
+	return highest
+}
+
 func (ct *checkpointTracker) status() []*status.Checkpoint {
 	result := make([]*status.Checkpoint, len(ct.checkpointMap))
 	i := 0
@@ -162,10 +162,19 @@ func (ct *clientHashDisseminator) reinitialize(seqNo uint64, networkState *msgs.
 		}
 
 		ct.clients[clientState.Id] = client
-		// TODO, this assumes no client deletion, need to handle that
 		actions.concat(client.reinitialize(seqNo, networkState.Config, clientState, reconfiguring))
 	}
 
+	// Clients which are absent from the network state have been removed,
+	// they are released simply by not carrying them over.
+	removedClients := map[uint64]struct{}{}
+	for id := range oldClients {
+		if _, ok := ct.clients[id]; !ok {
+			ct.logger.Log(LevelDebug, "releasing removed client", "client_id", id)
+			removedClients[id] = struct{}{}
+		}
+	}
+
 	oldMsgBuffers := ct.msgBuffers
 	ct.msgBuffers = map[nodeID]*msgBuffer{}
 	for _, id := range networkState.Config.Nodes {
@@ -174,6 +183,20 @@ func (ct *clientHashDisseminator) reinitialize(seqNo uint64, networkState *msgs.
 		} else {
 			ct.msgBuffers[nodeID(id)] = newMsgBuffer("clients", ct.nodeBuffers.nodeBuffer(nodeID(id)))
 		}
+
+		if len(removedClients) == 0 {
+			continue
+		}
+
+		// Acks for removed clients will never become current, so discard them.
+		ct.msgBuffers[nodeID(id)].iterate(func(_ nodeID, msg *msgs.Msg) applyable {
+			if ack, ok := msg.Type.(*msgs.Msg_RequestAck); ok {
+				if _, ok := removedClients[ack.RequestAck.ClientId]; ok {
+					return past
+				}
+			}
+			return future
+		}, nil)
 	}
 
 	return actions
@@ -239,7 +262,7 @@ func (ct *clientHashDisseminator) applyMsg(source nodeID, msg *msgs.Msg) *Action
 	}
 }
 
-func (ct *clientHashDisseminator) applyNewRequest(ack *msgs.RequestAck) *ActionList {
+func (ct *clientHashDisseminator) applyNewRequest(ack *msgs.RequestAck, size uint64) *ActionList {
 	client, ok := ct.clients[ack.ClientId]
 	if !ok {
 		// Unusual, client must have been removed since we processed the request
@@ -251,7 +274,7 @@ func (ct *clientHashDisseminator) applyNewRequest(ack *msgs.RequestAck) *ActionL
 		return &ActionList{}
 	}
 
-	client.reqNo(ack.ReqNo).applyNewRequest(ack)
+	client.reqNo(ack.ReqNo).applyNewRequest(ack, size)
 
 	return client.advanceAcks()
 }
@@ -402,6 +425,7 @@ func (crn *clientReqNo) reinitialize(networkConfig *msgs.NetworkState_Config) {
 		if oldClientReq.stored {
 			newClientReq := crn.clientReq(oldClientReq.ack)
 			newClientReq.stored = true
+			newClientReq.size = oldClientReq.size
 			crn.myRequests[digest] = newClientReq
 		}
 	}
@@ -428,7 +452,7 @@ func (crn *clientReqNo) clientReq(ack *msgs.RequestAck) *clientRequest {
 	return clientReq
 }
 
-func (crn *clientReqNo) applyNewRequest(ack *msgs.RequestAck) {
+func (crn *clientReqNo) applyNewRequest(ack *msgs.RequestAck, size uint64) {
 	_, ok := crn.myRequests[string(ack.Digest)]
 	if ok {
 		// We have already persisted this request, likely
@@ -438,6 +462,7 @@ func (crn *clientReqNo) applyNewRequest(ack *msgs.RequestAck) {
 
 	clientReq := crn.clientReq(ack)
 	clientReq.stored = true
+	clientReq.size = size
 
 	crn.myRequests[string(ack.Digest)] = clientReq
 }
@@ -566,9 +591,7 @@ func (crn *clientReqNo) tick() *ActionList {
 			continue
 		}
 
-		fetchTimeoutTicks := uint(4) // TODO make configurable
-
-		if cr.ticksFetching <= fetchTimeoutTicks {
+		if cr.ticksFetching <= uint(fetchTimeoutTicks(crn.myConfig)) {
 			cr.ticksFetching++
 			continue
 		}
@@ -589,13 +612,11 @@ func (crn *clientReqNo) tick() *ActionList {
 	// Finally, if we have sent any acks, and it has been long enough, we re-send.
 	// Since it's possible the client did not send the request to enough parties,
 	// we perform a linear backoff, waiting an additional interval longer after each re-ack
-	ackResendTicks := uint(20) // TODO make configurable
-
 	if crn.acksSent == 0 {
 		return actions
 	}
 
-	if crn.ticksSinceAck != crn.acksSent*ackResendTicks {
+	if crn.ticksSinceAck != crn.acksSent*uint(ackResendTicks(crn.myConfig)) {
 		crn.ticksSinceAck++
 		return actions
 	}
@@ -632,10 +653,11 @@ type clientRequest struct {
 	myConfig      *state.EventInitialParameters
 	ack           *msgs.RequestAck
 	agreements    map[nodeID]struct{}
-	stored        bool // set when the request is persisted locally
-	fetching      bool // set when we have sent a request for this request
-	ticksFetching uint // incremented by one each tick while fetching is true
-	ticksCorrect  uint // incremented by one each tick while not stored
+	stored        bool   // set when the request is persisted locally
+	size          uint64 // the size of the request data, known once stored
+	fetching      bool   // set when we have sent a request for this request
+	ticksFetching uint   // incremented by one each tick while fetching is true
+	ticksCorrect  uint   // incremented by one each tick while not stored
 }
 
 func (cr *clientRequest) fetch() *ActionList {
@@ -8,9 +8,11 @@ package statemachine
 
 import (
 	"bytes"
+	"sort"
 
 	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
 )
 
 // commitState represents our state, as reflected within our log watermarks.
@@ -24,6 +26,8 @@ import (
 type commitState struct {
 	persisted         *persisted
 	committingClients map[uint64]*committingClient
+	checkpointTracker *checkpointTracker
+	myConfig          *state.EventInitialParameters
 	logger            Logger
 
 	lowWatermark      uint64
@@ -35,12 +39,24 @@ type commitState struct {
 	upperHalfCommits  []*msgs.QEntry
 	checkpointPending bool
 	transferring      bool
+	transfer          *stateTransfer
 }
 
-func newCommitState(persisted *persisted, logger Logger) *commitState {
+// stateTransfer tracks the target of an in-progress state transfer, along
+// with any failed attempts, so that the transfer may be retried after a backoff.
+type stateTransfer struct {
+	seqNo      uint64
+	value      []byte
+	failures   uint
+	retryTicks uint // non-zero only while waiting to retry a failed transfer
+}
+
+func newCommitState(persisted *persisted, checkpointTracker *checkpointTracker, myConfig *state.EventInitialParameters, logger Logger) *commitState {
 	cs := &commitState{
-		persisted: persisted,
-		logger:    logger,
+		persisted:         persisted,
+		checkpointTracker: checkpointTracker,
+		myConfig:          myConfig,
+		logger:            logger,
 	}
 
 	return cs
@@ -49,13 +65,19 @@ func newCommitState(persisted *persisted, logger Logger) *commitState {
 func (cs *commitState) reinitialize() *ActionList {
 	var lastCEntry, secondToLastCEntry *msgs.CEntry
 	var lastTEntry *msgs.TEntry
+	// tEntryLast is set when a TEntry follows the last CEntry in the log.  This
+	// is the case for a node joining the network, which is bootstrapped from
+	// a peer checkpoint, and must transfer to that checkpoint's state.
+	var tEntryLast bool
 
 	cs.persisted.iterate(logIterator{
 		onCEntry: func(cEntry *msgs.CEntry) {
 			lastCEntry, secondToLastCEntry = cEntry, lastCEntry
+			tEntryLast = false
 		},
 		onTEntry: func(tEntry *msgs.TEntry) {
 			lastTEntry = tEntry
+			tEntryLast = true
 		},
 	})
 
@@ -88,9 +110,10 @@ func (cs *commitState) reinitialize() *ActionList {
 		cs.committingClients[clientState.Id] = newCommittingClient(lastCEntry.SeqNo, clientState)
 	}
 
-	if lastTEntry == nil || lastCEntry.SeqNo >= lastTEntry.SeqNo {
+	if lastTEntry == nil || (lastCEntry.SeqNo >= lastTEntry.SeqNo && !tEntryLast) {
 		cs.logger.Log(LevelDebug, "reinitialized commit-state", "low_watermark", cs.lowWatermark, "stop_at_seq_no", cs.stopAtSeqNo, "len(pending_reconfigurations)", len(cs.activeState.PendingReconfigurations), "last_checkpoint_seq_no", lastCEntry.SeqNo)
 		cs.transferring = false
+		cs.transfer = nil
 		return (&ActionList{}).StateApplied(cs.lowWatermark, cs.activeState)
 	}
 
@@ -98,17 +121,125 @@ func (cs *commitState) reinitialize() *ActionList {
 
 	// We crashed during a state transfer
 	cs.transferring = true
-	return actions.StateTransfer(lastTEntry.SeqNo, lastTEntry.Value)
+	cs.transfer = &stateTransfer{
+		seqNo: lastTEntry.SeqNo,
+		value: lastTEntry.Value,
+	}
+	return actions.StateTransfer(lastTEntry.SeqNo, lastTEntry.Value, cs.transferSources(lastTEntry.SeqNo, lastTEntry.Value))
 }
 
 func (cs *commitState) transferTo(seqNo uint64, value []byte) *ActionList {
 	cs.logger.Log(LevelDebug, "initiating state transfer", "target_seq_no", seqNo, "target_value", value)
 	assertEqual(cs.transferring, false, "multiple state transfers are not supported concurrently")
 	cs.transferring = true
+	cs.transfer = &stateTransfer{
+		seqNo: seqNo,
+		value: value,
+	}
 	return cs.persisted.addTEntry(&msgs.TEntry{
 		SeqNo: seqNo,
 		Value: value,
-	}).StateTransfer(seqNo, value)
+	}).StateTransfer(seqNo, value, cs.transferSources(seqNo, value))
+}
+
+// transferSources returns the nodes from which the checkpoint data for
+// the given checkpoint may be fetched.  If we do not know of a weak quorum
+// of nodes which attested to the checkpoint value, then we fall back to
+// every other node in the network, and rely on the data being verified
+// against the value.
+func (cs *commitState) transferSources(seqNo uint64, value []byte) []uint64 {
+	if cp, ok := cs.checkpointTracker.checkpointMap[seqNo]; ok {
+		attesters := cp.values[string(value)]
+		if len(attesters) >= someCorrectQuorum(cs.activeState.Config) {
+			sources := make([]uint64, len(attesters))
+			for i, attester := range attesters {
+				sources[i] = uint64(attester)
+			}
+			sort.Slice(sources, func(i, j int) bool {
+				return sources[i] < sources[j]
+			})
+			return sources
+		}
+	}
+
+	sources := make([]uint64, 0, len(cs.activeState.Config.Nodes))
+	for _, id := range cs.activeState.Config.Nodes {
+		if id != cs.myConfig.Id {
+			sources = append(sources, id)
+		}
+	}
+
+	return sources
+}
+
+// transferFailed schedules a retry of the current state transfer.  The number of
+// ticks waited before retrying doubles with each consecutive failure, starting
+// from the heartbeat interval and capped at the new epoch timeout.
+func (cs *commitState) transferFailed(seqNo uint64, value []byte) {
+	assertEqual(cs.transferring, true, "state transfer failure received but the state machine did not request transfer")
+
+	if cs.transfer.seqNo != seqNo || !bytes.Equal(cs.transfer.value, value) {
+		cs.logger.Log(LevelWarn, "ignoring failure of stale state transfer", "seq_no", seqNo, "target_seq_no", cs.transfer.seqNo)
+		return
+	}
+
+	cs.transfer.failures++
+
+	maxBackoff := uint(cs.myConfig.NewEpochTimeoutTicks)
+	backoff := uint(cs.myConfig.HeartbeatTicks)
+	for i := uint(1); i < cs.transfer.failures && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	if backoff == 0 {
+		backoff = 1
+	}
+
+	cs.transfer.retryTicks = backoff
+	cs.logger.Log(LevelWarn, "state transfer failed, will retry", "seq_no", seqNo, "failures", cs.transfer.failures, "retry_ticks", backoff)
+}
+
+// tick counts down towards retrying a failed state transfer.  When the retry
+// is due, if the network has since agreed on a later checkpoint than the one
+// we were attempting to transfer to, we retarget the transfer to that checkpoint
+// as the original target may no longer be available.
+func (cs *commitState) tick() *ActionList {
+	if cs.transfer == nil || cs.transfer.retryTicks == 0 {
+		return &ActionList{}
+	}
+
+	cs.transfer.retryTicks--
+	if cs.transfer.retryTicks > 0 {
+		return &ActionList{}
+	}
+
+	if cp := cs.checkpointTracker.highestCommittedCheckpoint(); cp != nil && cp.seqNo > cs.transfer.seqNo {
+		cs.logger.Log(LevelInfo, "retrying state transfer against newer checkpoint", "old_target_seq_no", cs.transfer.seqNo, "target_seq_no", cp.seqNo)
+		cs.transfer.seqNo = cp.seqNo
+		cs.transfer.value = cp.committedValue
+		return cs.persisted.addTEntry(&msgs.TEntry{
+			SeqNo: cp.seqNo,
+			Value: cp.committedValue,
+		}).StateTransfer(cp.seqNo, cp.committedValue, cs.transferSources(cp.seqNo, cp.committedValue))
+	}
+
+	cs.logger.Log(LevelInfo, "retrying state transfer", "target_seq_no", cs.transfer.seqNo, "failures", cs.transfer.failures)
+	return (&ActionList{}).StateTransfer(cs.transfer.seqNo, cs.transfer.value, cs.transferSources(cs.transfer.seqNo, cs.transfer.value))
+}
+
+func (cs *commitState) status() *status.StateTransfer {
+	if cs.transfer == nil {
+		return nil
+	}
+
+	return &status.StateTransfer{
+		SeqNo:      cs.transfer.seqNo,
+		Value:      cs.transfer.value,
+		Failures:   cs.transfer.failures,
+		RetryTicks: cs.transfer.retryTicks,
+	}
 }
 
 func (cs *commitState) applyCheckpointResult(epochConfig *msgs.EpochConfig, result *state.EventCheckpointResult) *ActionList {
@@ -123,12 +254,27 @@ func (cs *commitState) applyCheckpointResult(epochConfig *msgs.EpochConfig, resu
 		panic("dev sanity test -- this panic is helpful for dev, but needs to be removed as we could get stale checkpoint results")
 	}
 
-	if len(result.NetworkState.PendingReconfigurations) == 0 {
+	// If the previous checkpoint carried pending reconfigurations, then this
+	// checkpoint is the first under the new configuration.  We may not commit
+	// beyond it until it is stable and we have reconfigured.
+	reconfiguring := len(cs.activeState.PendingReconfigurations) > 0
+
+	switch {
+	case reconfiguring:
+		cs.logger.Log(LevelDebug, "checkpoint result applies reconfiguration, not extending stop", "stop_at_seq_no", cs.stopAtSeqNo)
+	case len(result.NetworkState.PendingReconfigurations) == 0:
 		cs.stopAtSeqNo = result.SeqNo + 2*ci
-	} else {
+	default:
 		cs.logger.Log(LevelDebug, "checkpoint result has pending reconfigurations, not extending stop", "stop_at_seq_no", cs.stopAtSeqNo)
 	}
 
+	// Nodes being added must learn of the checkpoint, so that they may
+	// bootstrap from it, and nodes being removed must help it become stable.
+	recipients := cs.activeState.Config.Nodes
+	if reconfiguring {
+		recipients = unionNodes(recipients, result.NetworkState.Config.Nodes)
+	}
+
 	cs.activeState = result.NetworkState
 	cs.lowerHalfCommits = cs.upperHalfCommits
 	cs.upperHalfCommits = make([]*msgs.QEntry, ci)
@@ -140,7 +286,7 @@ func (cs *commitState) applyCheckpointResult(epochConfig *msgs.EpochConfig, resu
 		CheckpointValue: result.Value,
 		NetworkState:    result.NetworkState,
 	}).Send(
-		cs.activeState.Config.Nodes,
+		recipients,
 		&msgs.Msg{
 			Type: &msgs.Msg_Checkpoint{
 				Checkpoint: &msgs.Checkpoint{
@@ -218,12 +364,43 @@ func nextNetworkConfig(startingState *msgs.NetworkState, committingClients map[u
 			assertTruef(found, "asked to remove client %d which doesn't exist", rc.RemoveClient)
 		case *msgs.Reconfiguration_NewConfig:
 			nextConfig = rc.NewConfig
+		case *msgs.Reconfiguration_AddNode:
+			for _, id := range nextConfig.Nodes {
+				assertNotEqualf(id, rc.AddNode, "asked to add node %d which already exists", rc.AddNode)
+			}
+
+			nodes := append(append([]uint64{}, nextConfig.Nodes...), rc.AddNode)
+			nextConfig = configWithNodes(nextConfig, nodes)
+		case *msgs.Reconfiguration_RemoveNode:
+			nodes := make([]uint64, 0, len(nextConfig.Nodes))
+			for _, id := range nextConfig.Nodes {
+				if id != rc.RemoveNode {
+					nodes = append(nodes, id)
+				}
+			}
+
+			assertTruef(len(nodes) < len(nextConfig.Nodes), "asked to remove node %d which doesn't exist", rc.RemoveNode)
+			assertTruef(len(nodes) > 0, "asked to remove node %d which is the last node", rc.RemoveNode)
+			nextConfig = configWithNodes(nextConfig, nodes)
 		}
 	}
 
 	return nextConfig, nextClients
 }
 
+// configWithNodes returns a copy of the config with the given set of nodes,
+// recomputing the number of tolerated failures and the number of buckets
+// to match the new network size.
+func configWithNodes(config *msgs.NetworkState_Config, nodes []uint64) *msgs.NetworkState_Config {
+	return &msgs.NetworkState_Config{
+		Nodes:              nodes,
+		F:                  int32((len(nodes) - 1) / 3),
+		NumberOfBuckets:    int32(len(nodes)),
+		CheckpointInterval: config.CheckpointInterval,
+		MaxEpochLength:     config.MaxEpochLength,
+	}
+}
+
 // drain returns all available Commits (including checkpoint requests)
 func (cs *commitState) drain() *ActionList {
 	ci := uint64(cs.activeState.Config.CheckpointInterval)
@@ -19,11 +19,17 @@ type preprepareBuffer struct {
 	buffer    *msgBuffer
 }
 
+// badBatchFunc is invoked with a batch which failed validation, and returns
+// the actions required to suspect the epoch.
+type badBatchFunc func(source nodeID, seqNo uint64, reason string, msg *msgs.Msg, rule state.ActionOddity_Rule) *ActionList
+
 type activeEpoch struct {
 	epochConfig   *msgs.EpochConfig
 	networkConfig *msgs.NetworkState_Config
 	myConfig      *state.EventInitialParameters
 	logger        Logger
+	timeouts      *epochTimeouts
+	badBatch      badBatchFunc
 
 	outstandingReqs *allOutstandingReqs
 	proposer        *proposer
@@ -42,7 +48,7 @@ type activeEpoch struct {
 	ticksSinceProgress  uint32
 }
 
-func newActiveEpoch(epochConfig *msgs.EpochConfig, persisted *persisted, nodeBuffers *nodeBuffers, commitState *commitState, clientTracker *clientTracker, myConfig *state.EventInitialParameters, logger Logger) *activeEpoch {
+func newActiveEpoch(epochConfig *msgs.EpochConfig, persisted *persisted, nodeBuffers *nodeBuffers, commitState *commitState, clientTracker *clientTracker, myConfig *state.EventInitialParameters, logger Logger, timeouts *epochTimeouts, badBatch badBatchFunc) *activeEpoch {
 	networkConfig := commitState.activeState.Config
 	startingSeqNo := commitState.highestCommit
 
@@ -118,6 +124,8 @@ func newActiveEpoch(epochConfig *msgs.EpochConfig, persisted *persisted, nodeBuf
 		lowestUncommitted: lowestUncommitted,
 		outstandingReqs:   outstandingReqs,
 		logger:            logger,
+		timeouts:          timeouts,
+		badBatch:          badBatch,
 	}
 }
 
@@ -280,8 +288,15 @@ func (e *activeEpoch) applyPreprepareMsg(source nodeID, seqNo uint64, batch []*m
 	// outstanding requests before transitioning the sequence to preprepared
 	actions, err := e.outstandingReqs.applyAcks(bucketID, seq, batch)
 	if err != nil {
-		// TODO implement suspect on bad batch
-		panic(fmt.Sprintf("handle me, seq_no=%d we need to stop the bucket and suspect: %s", seqNo, err))
+		return e.badBatch(source, seqNo, err.Error(), &msgs.Msg{
+			Type: &msgs.Msg_Preprepare{
+				Preprepare: &msgs.Preprepare{
+					SeqNo: seqNo,
+					Epoch: e.epochConfig.Number,
+					Batch: batch,
+				},
+			},
+		}, state.ActionOddity_INVALID_BATCH)
 	}
 
 	return actions
@@ -393,6 +408,14 @@ func (e *activeEpoch) advance() *ActionList {
 
 	e.proposer.advance(e.lowestUncommitted)
 
+	return actions.concat(e.proposeBatches())
+}
+
+// proposeBatches allocates a sequence for each batch which is ready to be cut
+// in the buckets we own, for as long as the watermarks allow.
+func (e *activeEpoch) proposeBatches() *ActionList {
+	actions := &ActionList{}
+
 	for bid := bucketID(0); bid < bucketID(e.networkConfig.NumberOfBuckets); bid++ {
 		ownerID := e.buckets[bid]
 		if ownerID != nodeID(e.myConfig.Id) {
@@ -436,16 +459,24 @@ func (e *activeEpoch) applyBatchHashResult(seqNo uint64, digest []byte) *ActionL
 }
 
 func (e *activeEpoch) tick() *ActionList {
+	actions := &ActionList{}
+
+	if e.myConfig.MaxBatchLatencyTicks != 0 {
+		for _, prb := range e.proposer.proposalBuckets {
+			prb.tick()
+		}
+		actions.concat(e.proposeBatches())
+	}
+
 	if e.lastCommittedAtTick < e.commitState.highestCommit {
 		e.lastCommittedAtTick = e.commitState.highestCommit
 		e.ticksSinceProgress = 0
-		return &ActionList{}
+		return actions
 	}
 
 	e.ticksSinceProgress++
-	actions := &ActionList{}
 
-	if e.ticksSinceProgress > e.myConfig.SuspectTicks {
+	if e.ticksSinceProgress > e.timeouts.suspectTicks() {
 		suspect := &msgs.Suspect{
 			Epoch: e.epochConfig.Number,
 		}
@@ -508,12 +539,16 @@ func (e *activeEpoch) status() []*status.Bucket {
 		return []*status.Bucket{}
 	}
 
+	// The number of buckets need not divide the number of sequences, as after
+	// a reconfiguration, so some buckets may have one fewer sequence than others.
+	bucketLen := (len(e.sequences)*len(e.sequences[0]) + len(e.buckets) - 1) / len(e.buckets)
+
 	buckets := make([]*status.Bucket, len(e.buckets))
 	for i := range buckets {
 		buckets[i] = &status.Bucket{
 			ID:        uint64(i),
 			Leader:    e.buckets[bucketID(i)] == nodeID(e.myConfig.Id),
-			Sequences: make([]status.SequenceState, len(e.sequences)*len(e.sequences[0])/len(buckets)),
+			Sequences: make([]status.SequenceState, bucketLen),
 		}
 	}
 
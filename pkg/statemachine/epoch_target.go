@@ -53,9 +53,11 @@ type epochTarget struct {
 	myEpochChange   *parsedEpochChange
 	myLeaderChoice  []uint64             // Set along with myEpochChange
 	leaderNewEpoch  *msgs.NewEpoch       // The NewEpoch msg we received directly from the leader
+	leaderInvalid   bool                 // Set once leaderNewEpoch is found to be malformed
 	networkNewEpoch *msgs.NewEpochConfig // The NewEpoch msg as received via the bracha broadcast
 	isPrimary       bool
 	prestartBuffers map[nodeID]*msgBuffer
+	badBatches      []*status.BadBatch // Evidence of invalid batches, for which we suspected this epoch
 
 	persisted              *persisted
 	nodeBuffers            *nodeBuffers
@@ -65,6 +67,7 @@ type epochTarget struct {
 	networkConfig          *msgs.NetworkState_Config
 	myConfig               *state.EventInitialParameters
 	logger                 Logger
+	timeouts               *epochTimeouts
 }
 
 func newEpochTarget(
@@ -78,6 +81,7 @@ func newEpochTarget(
 	networkConfig *msgs.NetworkState_Config,
 	myConfig *state.EventInitialParameters,
 	logger Logger,
+	timeouts *epochTimeouts,
 ) *epochTarget {
 	prestartBuffers := map[nodeID]*msgBuffer{}
 	for _, id := range networkConfig.Nodes {
@@ -96,7 +100,7 @@ func newEpochTarget(
 		strongChanges:          map[nodeID]*parsedEpochChange{},
 		echos:                  map[*msgs.NewEpochConfig]map[nodeID]struct{}{},
 		readies:                map[*msgs.NewEpochConfig]map[nodeID]struct{}{},
-		isPrimary:              number%uint64(len(networkConfig.Nodes)) == myConfig.Id,
+		isPrimary:              epochPrimary(number, networkConfig) == nodeID(myConfig.Id),
 		prestartBuffers:        prestartBuffers,
 		persisted:              persisted,
 		nodeBuffers:            nodeBuffers,
@@ -106,6 +110,7 @@ func newEpochTarget(
 		networkConfig:          networkConfig,
 		myConfig:               myConfig,
 		logger:                 logger,
+		timeouts:               timeouts,
 	}
 }
 
@@ -164,8 +169,13 @@ func (et *epochTarget) constructNewEpoch(newLeaders []uint64, nc *msgs.NetworkSt
 
 // Verifies that the NewEpoch message we obtained from the new primary is valid
 // and that we have received all the EpochChange messages it references.
-// If this is the case, advances the state to etFetching.
-func (et *epochTarget) verifyNewEpochState() {
+// If this is the case, advances the state to etFetching.  If the message is
+// malformed, the primary is reported as odd.
+func (et *epochTarget) verifyNewEpochState() *ActionList {
+	if et.leaderInvalid {
+		return &ActionList{}
+	}
+
 	epochChanges := map[nodeID]*parsedEpochChange{}
 
 	// Verify that:
@@ -173,21 +183,21 @@ func (et *epochTarget) verifyNewEpochState() {
 
 		// Each EpochChange is only referenced once.
 		if _, ok := epochChanges[nodeID(remoteEpochChange.NodeId)]; ok {
-			// TODO, references multiple epoch changes from the same node, malformed, log oddity
-			return
+			// References multiple epoch changes from the same node, malformed.
+			return et.leaderOddity(state.ActionOddity_NEW_EPOCH_DUPLICATE_EPOCH_CHANGE)
 		}
 
 		// We have received an EpochChange from the source of the referenced message.
 		change, ok := et.changes[nodeID(remoteEpochChange.NodeId)]
 		if !ok {
 			// Either the primary is lying, or we simply don't have enough information yet.
-			return
+			return &ActionList{}
 		}
 
 		// The received EpochChange has the correct digest and is acknowledged.
 		parsedChange, ok := change.parsedByDigest[string(remoteEpochChange.Digest)]
 		if !ok || len(parsedChange.acks) < someCorrectQuorum(et.networkConfig) {
-			return
+			return &ActionList{}
 		}
 
 		epochChanges[nodeID(remoteEpochChange.NodeId)] = parsedChange
@@ -203,12 +213,25 @@ func (et *epochTarget) verifyNewEpochState() {
 	// The reconstructed new epoch configuration must be the same as the one obtained from the leader.
 	// Otherwise the leader must be faulty.
 	if !proto.Equal(newEpochConfig, et.leaderNewEpoch.NewConfig) {
-		// TODO byzantine, log oddity
-		return
+		return et.leaderOddity(state.ActionOddity_NEW_EPOCH_CONFIG_MISMATCH)
 	}
 
 	et.logger.Log(LevelDebug, "epoch transitioning from from verifying to fetching", "epoch_no", et.number)
 	et.state = etFetching
+	return &ActionList{}
+}
+
+// leaderOddity marks the NewEpoch message from the primary as invalid, so
+// that it is not verified again, and reports the primary as odd.
+func (et *epochTarget) leaderOddity(rule state.ActionOddity_Rule) *ActionList {
+	primary := epochPrimary(et.number, et.networkConfig)
+	et.logger.Log(LevelWarn, "primary sent invalid new epoch message", "epoch_no", et.number, "primary", primary, "rule", rule)
+	et.leaderInvalid = true
+	return (&ActionList{}).Oddity(uint64(primary), &msgs.Msg{
+		Type: &msgs.Msg_NewEpoch{
+			NewEpoch: et.leaderNewEpoch,
+		},
+	}, rule)
 }
 
 func (et *epochTarget) fetchNewEpochState() *ActionList {
@@ -425,6 +448,8 @@ func (et *epochTarget) repeatEpochChangeBroadcast() *ActionList {
 
 func (et *epochTarget) tickPrepending() *ActionList {
 	if et.myNewEpoch == nil {
+		// The rebroadcast only recovers lost messages, so, unlike the
+		// epoch timeouts, it does not back off after failed epochs.
 		if et.stateTicks%uint64(et.myConfig.NewEpochTimeoutTicks/2) == 0 {
 			return et.repeatEpochChangeBroadcast()
 		}
@@ -447,7 +472,7 @@ func (et *epochTarget) tickPrepending() *ActionList {
 }
 
 func (et *epochTarget) tickPending() *ActionList {
-	pendingTicks := et.stateTicks % uint64(et.myConfig.NewEpochTimeoutTicks)
+	pendingTicks := et.stateTicks % uint64(et.timeouts.newEpochTimeoutTicks())
 	if et.isPrimary {
 		// resend the new-view if others perhaps missed it
 		if pendingTicks%2 == 0 {
@@ -597,6 +622,7 @@ func (et *epochTarget) checkEpochQuorum() *ActionList {
 // This is important for the case where the NewEpoch cannot be processed yet.
 func (et *epochTarget) applyNewEpochMsg(msg *msgs.NewEpoch) *ActionList {
 	et.leaderNewEpoch = msg
+	et.leaderInvalid = false
 	return et.advanceState()
 }
 
@@ -808,7 +834,7 @@ func (et *epochTarget) advanceState() *ActionList {
 			et.logger.Log(LevelDebug, "epoch transitioning from pending to verifying", "epoch_no", et.number)
 			et.state = etVerifying
 		case etVerifying: // Have a NewEpoch message but it references epoch changes we cannot yet verify
-			et.verifyNewEpochState()
+			actions.concat(et.verifyNewEpochState())
 		case etFetching: // Have received and verified a new epoch messages, and are waiting to get state
 			actions.concat(et.fetchNewEpochState())
 		case etEchoing: // Have received and validated a new-epoch, waiting for a quorum of echos
@@ -819,7 +845,7 @@ func (et *epochTarget) advanceState() *ActionList {
 			et.checkEpochResumed()
 		case etReady: // New epoch is ready to begin
 			// TODO, handle case where planned epoch expiration is now
-			et.activeEpoch = newActiveEpoch(et.networkNewEpoch.Config, et.persisted, et.nodeBuffers, et.commitState, et.clientTracker, et.myConfig, et.logger)
+			et.activeEpoch = newActiveEpoch(et.networkNewEpoch.Config, et.persisted, et.nodeBuffers, et.commitState, et.clientTracker, et.myConfig, et.logger, et.timeouts, et.suspectBadBatch)
 
 			actions.concat(et.activeEpoch.advance())
 
@@ -864,6 +890,39 @@ func (et *epochTarget) moveLowWatermark(seqNo uint64) *ActionList {
 	return actions
 }
 
+// suspectBadBatch records a batch which failed validation as evidence, and
+// reports its source as odd.  The first bad batch causes this node to suspect
+// the epoch immediately, rather than waiting for the suspect timeout, so that
+// a Byzantine leader is removed quickly.
+func (et *epochTarget) suspectBadBatch(source nodeID, seqNo uint64, reason string, msg *msgs.Msg, rule state.ActionOddity_Rule) *ActionList {
+	et.logger.Log(LevelWarn, "received bad batch", "epoch_no", et.number, "source", source, "seq_no", seqNo, "reason", reason)
+
+	et.badBatches = append(et.badBatches, &status.BadBatch{
+		Source: uint64(source),
+		SeqNo:  seqNo,
+		Reason: reason,
+	})
+
+	actions := (&ActionList{}).Oddity(uint64(source), msg, rule)
+	if len(et.badBatches) > 1 {
+		// We have already suspected this epoch.
+		return actions
+	}
+
+	et.logger.Log(LevelDebug, "suspect epoch to have failed due to bad batch", "epoch_no", et.number)
+	suspect := &msgs.Suspect{
+		Epoch: et.number,
+	}
+	return actions.Send(
+		et.networkConfig.Nodes,
+		&msgs.Msg{
+			Type: &msgs.Msg_Suspect{
+				Suspect: suspect,
+			},
+		},
+	).concat(et.persisted.addSuspect(suspect))
+}
+
 func (et *epochTarget) applySuspectMsg(source nodeID) {
 	et.suspicions[source] = struct{}{}
 
@@ -963,6 +1022,7 @@ func (et *epochTarget) status() *status.EpochTarget {
 		Echos:        make([]uint64, 0, len(et.echos)),
 		Readies:      make([]uint64, 0, len(et.readies)),
 		Suspicions:   make([]uint64, 0, len(et.suspicions)),
+		BadBatches:   et.badBatches,
 	}
 
 	for node, change := range et.changes {
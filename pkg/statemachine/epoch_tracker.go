@@ -8,6 +8,7 @@ package statemachine
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
@@ -31,6 +32,42 @@ type epochTracker struct {
 	maxEpochs              map[nodeID]uint64
 	maxCorrectEpoch        uint64
 	ticksOutOfCorrectEpoch int
+
+	timeouts           *epochTimeouts
+	commitAtEpochStart uint64 // The highest commit when the current epoch target was created
+}
+
+// maxEpochBackoff bounds the growth of the epoch timeouts, which
+// are multiplied by at most 2^maxEpochBackoff.
+const maxEpochBackoff = 6
+
+// epochTimeouts computes the epoch change and suspect timeouts, which
+// double with each consecutive epoch which ended without making progress,
+// so that nodes which cannot reach one another do not churn through epochs.
+type epochTimeouts struct {
+	myConfig     *state.EventInitialParameters
+	failedEpochs uint32
+}
+
+func (t *epochTimeouts) backoff(ticks uint32) uint32 {
+	shift := t.failedEpochs
+	if shift > maxEpochBackoff {
+		shift = maxEpochBackoff
+	}
+
+	if ticks > math.MaxUint32>>shift {
+		return math.MaxUint32
+	}
+
+	return ticks << shift
+}
+
+func (t *epochTimeouts) newEpochTimeoutTicks() uint32 {
+	return t.backoff(t.myConfig.NewEpochTimeoutTicks)
+}
+
+func (t *epochTimeouts) suspectTicks() uint32 {
+	return t.backoff(t.myConfig.SuspectTicks)
 }
 
 func newEpochTracker(
@@ -54,6 +91,9 @@ func newEpochTracker(
 		clientTracker:          clientTracker,
 		clientHashDisseminator: clientHashDisseminator,
 		maxEpochs:              map[nodeID]uint64{},
+		timeouts: &epochTimeouts{
+			myConfig: myConfig,
+		},
 	}
 }
 
@@ -138,6 +178,7 @@ func (et *epochTracker) reinitialize() *ActionList {
 			et.networkConfig,
 			et.myConfig,
 			et.logger,
+			et.timeouts,
 		)
 
 		startingSeqNo := highestPreprepared + 1
@@ -195,6 +236,7 @@ func (et *epochTracker) reinitialize() *ActionList {
 			et.networkConfig,
 			et.myConfig,
 			et.logger,
+			et.timeouts,
 		)
 
 		et.currentEpoch.myEpochChange = parsedEpochChange
@@ -228,6 +270,12 @@ func (et *epochTracker) advanceState() *ActionList {
 		return &ActionList{}
 	}
 
+	if et.commitState.highestCommit <= et.commitAtEpochStart {
+		et.timeouts.failedEpochs++
+		et.logger.Log(LevelDebug, "epoch ended without progress, backing off epoch timeouts", "epoch_no", et.currentEpoch.number, "failed_epochs", et.timeouts.failedEpochs)
+	}
+	et.commitAtEpochStart = et.commitState.highestCommit
+
 	newEpochNumber := et.currentEpoch.number + 1
 	if et.maxCorrectEpoch > newEpochNumber {
 		newEpochNumber = et.maxCorrectEpoch
@@ -248,6 +296,7 @@ func (et *epochTracker) advanceState() *ActionList {
 		et.networkConfig,
 		et.myConfig,
 		et.logger,
+		et.timeouts,
 	)
 	et.currentEpoch.myEpochChange = myEpochChange
 	et.currentEpoch.myLeaderChoice = []uint64{et.myConfig.Id} // XXX, wrong
@@ -350,9 +399,9 @@ func (et *epochTracker) applyMsg(source nodeID, msg *msgs.Msg) *ActionList {
 		return target.applyEpochChangeAckMsg(source, nodeID(innerMsg.EpochChangeAck.Originator), innerMsg.EpochChangeAck.EpochChange)
 	case *msgs.Msg_NewEpoch:
 		// Ignore NewEpoch message if not sent by the epoch primary.
-		if innerMsg.NewEpoch.NewConfig.Config.Number%uint64(len(et.networkConfig.Nodes)) != uint64(source) {
-			// TODO, log oddity
-			return &ActionList{}
+		if epochPrimary(innerMsg.NewEpoch.NewConfig.Config.Number, et.networkConfig) != source {
+			et.logger.Log(LevelWarn, "ignoring new epoch message not from the epoch primary", "source", source, "epoch_no", innerMsg.NewEpoch.NewConfig.Config.Number)
+			return (&ActionList{}).Oddity(uint64(source), msg, state.ActionOddity_NEW_EPOCH_NOT_FROM_PRIMARY)
 		}
 		return target.applyNewEpochMsg(innerMsg.NewEpoch)
 	case *msgs.Msg_NewEpochEcho:
@@ -393,11 +442,15 @@ func (et *epochTracker) tick() *ActionList {
 		et.maxCorrectEpoch = maxEpoch
 	}
 
+	if et.timeouts.failedEpochs != 0 && et.currentEpoch.state == etInProgress && et.commitState.highestCommit > et.commitAtEpochStart {
+		et.logger.Log(LevelDebug, "epoch made progress, resetting epoch timeouts", "epoch_no", et.currentEpoch.number)
+		et.timeouts.failedEpochs = 0
+	}
+
 	if et.maxCorrectEpoch > et.currentEpoch.number {
 		et.ticksOutOfCorrectEpoch++
 
-		// TODO make this configurable
-		if et.ticksOutOfCorrectEpoch > 10 {
+		if et.ticksOutOfCorrectEpoch > int(outOfCorrectEpochTicks(et.myConfig)) {
 			et.currentEpoch.state = etDone
 		}
 	}
@@ -424,6 +477,9 @@ func (et *epochTracker) applyEpochChangeDigest(origin *state.HashOrigin_EpochCha
 
 func (et *epochTracker) status() *status.EpochTracker {
 	return &status.EpochTracker{
-		ActiveEpoch: et.currentEpoch.status(),
+		ActiveEpoch:          et.currentEpoch.status(),
+		FailedEpochs:         et.timeouts.failedEpochs,
+		NewEpochTimeoutTicks: et.timeouts.newEpochTimeoutTicks(),
+		SuspectTicks:         et.timeouts.suspectTicks(),
 	}
 }
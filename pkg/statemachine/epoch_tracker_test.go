@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+)
+
+var _ = Describe("epochTimeouts", func() {
+	var timeouts *epochTimeouts
+
+	BeforeEach(func() {
+		timeouts = &epochTimeouts{
+			myConfig: &state.EventInitialParameters{
+				NewEpochTimeoutTicks: 8,
+				SuspectTicks:         4,
+			},
+		}
+	})
+
+	It("uses the configured timeouts while epochs succeed", func() {
+		Expect(timeouts.newEpochTimeoutTicks()).To(Equal(uint32(8)))
+		Expect(timeouts.suspectTicks()).To(Equal(uint32(4)))
+	})
+
+	It("doubles the timeouts with each failed epoch", func() {
+		timeouts.failedEpochs = 3
+		Expect(timeouts.newEpochTimeoutTicks()).To(Equal(uint32(64)))
+		Expect(timeouts.suspectTicks()).To(Equal(uint32(32)))
+	})
+
+	It("bounds the growth of the timeouts", func() {
+		timeouts.failedEpochs = 100
+		Expect(timeouts.newEpochTimeoutTicks()).To(Equal(uint32(8 << maxEpochBackoff)))
+
+		timeouts.myConfig.NewEpochTimeoutTicks = math.MaxUint32 / 2
+		Expect(timeouts.newEpochTimeoutTicks()).To(Equal(uint32(math.MaxUint32)))
+	})
+})
+
+var _ = Describe("epochTracker backoff", func() {
+	var (
+		myConfig      *state.EventInitialParameters
+		networkConfig *msgs.NetworkState_Config
+		cs            *commitState
+		tracker       *epochTracker
+	)
+
+	BeforeEach(func() {
+		myConfig = &state.EventInitialParameters{
+			Id:                   0,
+			BufferSize:           100,
+			NewEpochTimeoutTicks: 8,
+			SuspectTicks:         4,
+		}
+
+		networkConfig = &msgs.NetworkState_Config{
+			Nodes: []uint64{0, 1, 2, 3},
+			F:     1,
+		}
+
+		p := newPersisted(ConsoleErrorLogger)
+		p.appendInitialLoad(1, &msgs.Persistent{
+			Type: &msgs.Persistent_CEntry{
+				CEntry: &msgs.CEntry{
+					SeqNo:           0,
+					CheckpointValue: []byte("checkpoint"),
+				},
+			},
+		})
+		p.appendInitialLoad(2, &msgs.Persistent{
+			Type: &msgs.Persistent_NEntry{
+				NEntry: &msgs.NEntry{
+					SeqNo: 1,
+					EpochConfig: &msgs.EpochConfig{
+						Number: 1,
+					},
+				},
+			},
+		})
+
+		cs = &commitState{}
+		nodeBuffers := newNodeBuffers(myConfig, ConsoleErrorLogger)
+
+		tracker = newEpochTracker(p, nodeBuffers, cs, networkConfig, ConsoleErrorLogger, myConfig, nil, nil, nil)
+		tracker.networkConfig = networkConfig
+		tracker.futureMsgs = map[nodeID]*msgBuffer{}
+		for _, id := range networkConfig.Nodes {
+			tracker.futureMsgs[nodeID(id)] = newMsgBuffer("future-epochs", nodeBuffers.nodeBuffer(nodeID(id)))
+		}
+		tracker.currentEpoch = &epochTarget{
+			number: 1,
+			state:  etDone,
+		}
+	})
+
+	// endEpoch times out the current epoch, and starts the next.
+	endEpoch := func() {
+		tracker.currentEpoch.state = etDone
+		tracker.advanceState()
+	}
+
+	It("counts the epochs which end without progress", func() {
+		endEpoch()
+		Expect(tracker.currentEpoch.number).To(Equal(uint64(2)))
+		Expect(tracker.timeouts.failedEpochs).To(Equal(uint32(1)))
+
+		endEpoch()
+		Expect(tracker.timeouts.failedEpochs).To(Equal(uint32(2)))
+
+		By("not counting an epoch which committed before ending")
+		cs.highestCommit = 5
+		endEpoch()
+		Expect(tracker.timeouts.failedEpochs).To(Equal(uint32(2)))
+	})
+
+	It("resets the count once an active epoch makes progress", func() {
+		endEpoch()
+		endEpoch()
+		Expect(tracker.timeouts.failedEpochs).To(Equal(uint32(2)))
+
+		tracker.currentEpoch.state = etInProgress
+		tracker.currentEpoch.activeEpoch = &activeEpoch{
+			myConfig:    myConfig,
+			commitState: cs,
+			timeouts:    tracker.timeouts,
+		}
+
+		tracker.tick()
+		Expect(tracker.timeouts.failedEpochs).To(Equal(uint32(2)))
+
+		cs.highestCommit = 5
+		tracker.tick()
+		Expect(tracker.timeouts.failedEpochs).To(Equal(uint32(0)))
+	})
+
+	It("does not back off the rebroadcast of the epoch change", func() {
+		endEpoch()
+		endEpoch()
+		endEpoch()
+		Expect(tracker.timeouts.newEpochTimeoutTicks()).To(Equal(uint32(64)))
+
+		epochChange := tracker.currentEpoch.myEpochChange.underlying
+		for i := 1; i < 4; i++ {
+			Expect(tracker.tick()).To(Equal(&ActionList{}))
+		}
+
+		Expect(tracker.tick()).To(Equal((&ActionList{}).Send(
+			[]uint64{0, 1, 2, 3},
+			&msgs.Msg{
+				Type: &msgs.Msg_EpochChange{
+					EpochChange: epochChange,
+				},
+			},
+		)))
+	})
+})
@@ -130,16 +130,17 @@ func EventCheckpointResult(value []byte, pendingReconfigurations []*msgs.Reconfi
 	}
 }
 
-func (el *EventList) RequestPersisted(ack *msgs.RequestAck) *EventList {
-	el.PushBack(EventRequestPersisted(ack))
+func (el *EventList) RequestPersisted(ack *msgs.RequestAck, size uint64) *EventList {
+	el.PushBack(EventRequestPersisted(ack, size))
 	return el
 }
 
-func EventRequestPersisted(ack *msgs.RequestAck) *state.Event {
+func EventRequestPersisted(ack *msgs.RequestAck, size uint64) *state.Event {
 	return &state.Event{
 		Type: &state.Event_RequestPersisted{
 			RequestPersisted: &state.EventRequestPersisted{
-				RequestAck: ack,
+				RequestAck:  ack,
+				RequestSize: size,
 			},
 		},
 	}
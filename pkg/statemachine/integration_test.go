@@ -17,6 +17,8 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	. "github.com/hyperledger-labs/mirbft/pkg/testengine"
 )
 
@@ -37,6 +39,7 @@ type Assertions struct {
 	CompletesInSteps      int
 	StateTransferOccurred map[uint64]Occurred
 	IsNotLeader           map[uint64]Occurred
+	MaxBatchBytes         uint64
 }
 
 var _ = Describe("Mirbft", func() {
@@ -117,6 +120,12 @@ var _ = Describe("Mirbft", func() {
 			default:
 			}
 
+			if testConf.Assertions.MaxBatchBytes != 0 {
+				for _, batchBytes := range node.State.BatchBytes {
+					Expect(batchBytes).To(BeNumerically("<=", testConf.Assertions.MaxBatchBytes))
+				}
+			}
+
 			status, err := node.StateMachine.Status()
 			Expect(err).NotTo(HaveOccurred())
 			isLeader := false
@@ -162,6 +171,35 @@ var _ = Describe("Mirbft", func() {
 				CompletesInSteps: 300,
 			},
 		}),
+		Entry("one-node-one-client-byte-limited-batch-green", TestConf{
+			Spec: Spec{
+				NodeCount:      1,
+				ClientCount:    1,
+				ReqsPerClient:  100,
+				BatchSize:      20,
+				BatchSizeBytes: 85, // five requests
+			},
+			Assertions: Assertions{
+				CompletesInSteps: 300,
+				MaxBatchBytes:    85,
+			},
+		}),
+		Entry("one-node-one-client-latency-limited-batch-green", TestConf{
+			Spec: Spec{
+				NodeCount:            1,
+				ClientCount:          1,
+				ReqsPerClient:        100,
+				BatchSize:            200,
+				MaxBatchLatencyTicks: 1,
+				TweakRecorder: func(r *Recorder) {
+					// Heartbeats would otherwise cut the partial batches.
+					r.NodeConfigs[0].InitParms.HeartbeatTicks = 1000
+				},
+			},
+			Assertions: Assertions{
+				CompletesInSteps: 300,
+			},
+		}),
 		Entry("one-node-four-client-green", TestConf{
 			Spec: Spec{
 				NodeCount:     1,
@@ -229,9 +267,9 @@ var _ = Describe("Mirbft", func() {
 				ClientsIgnore: []uint64{0},
 			},
 			Assertions: Assertions{
-				CompletesInSteps: 30000,
+				CompletesInSteps: 15000,
 				StateTransferOccurred: map[uint64]Occurred{
-					0: Yes, // XXX this should be false once forwarding is working again
+					0: No,
 				},
 				IsNotLeader: map[uint64]Occurred{
 					0: Maybe, // TODO No
@@ -320,6 +358,146 @@ var _ = Describe("Mirbft", func() {
 				},
 			},
 		}),
+		Entry("node3 starts late and its first state transfers fail", TestConf{
+			Spec: Spec{
+				NodeCount:     4,
+				ClientCount:   4,
+				ReqsPerClient: 20,
+				TweakRecorder: func(r *Recorder) {
+					r.Mangler = Until(MatchMsgs().FromNode(1).OfTypeCheckpoint().WithSequence(20)).Do(For(MatchNodeStartup().ForNode(3)).Delay(500))
+					r.NodeConfigs[3].RuntimeParms.StateTransferFailures = 3
+				},
+			},
+			Assertions: Assertions{
+				CompletesInSteps: 20000,
+				StateTransferOccurred: map[uint64]Occurred{
+					3: Yes,
+				},
+				IsNotLeader: map[uint64]Occurred{
+					0: Maybe,
+					1: Maybe,
+					2: Maybe,
+					3: Maybe,
+				},
+			},
+		}),
+		Entry("node3 starts late and fetches state from its peers", TestConf{
+			Spec: Spec{
+				NodeCount:     4,
+				ClientCount:   4,
+				ReqsPerClient: 20,
+				TweakRecorder: func(r *Recorder) {
+					r.Mangler = Until(MatchMsgs().FromNode(1).OfTypeCheckpoint().WithSequence(20)).Do(For(MatchNodeStartup().ForNode(3)).Delay(500))
+					r.BuiltinStateTransfer = true
+				},
+			},
+			Assertions: Assertions{
+				CompletesInSteps: 20000,
+				StateTransferOccurred: map[uint64]Occurred{
+					3: Yes,
+				},
+				IsNotLeader: map[uint64]Occurred{
+					0: Maybe,
+					1: Maybe,
+					2: Maybe,
+					3: Maybe,
+				},
+			},
+		}),
+		Entry("client1 is removed from the network", TestConf{
+			Spec: Spec{
+				NodeCount:     4,
+				ClientCount:   2,
+				ReqsPerClient: 100,
+				TweakRecorder: func(r *Recorder) {
+					r.ReconfigPoints = []*ReconfigPoint{
+						{
+							ClientID: 0,
+							ReqNo:    10,
+							Reconfiguration: &msgs.Reconfiguration{
+								Type: &msgs.Reconfiguration_RemoveClient{
+									RemoveClient: 1,
+								},
+							},
+						},
+					}
+				},
+			},
+			Assertions: Assertions{
+				CompletesInSteps: 15000,
+				IsNotLeader: map[uint64]Occurred{
+					0: Maybe,
+					1: Maybe,
+					2: Maybe,
+					3: Maybe,
+				},
+			},
+		}),
+		Entry("node3 is removed from the network", TestConf{
+			Spec: Spec{
+				NodeCount:     4,
+				ClientCount:   1,
+				ReqsPerClient: 100,
+				TweakRecorder: func(r *Recorder) {
+					r.ReconfigPoints = []*ReconfigPoint{
+						{
+							ClientID: 0,
+							ReqNo:    10,
+							Reconfiguration: &msgs.Reconfiguration{
+								Type: &msgs.Reconfiguration_RemoveNode{
+									RemoveNode: 3,
+								},
+							},
+						},
+					}
+				},
+			},
+			Assertions: Assertions{
+				CompletesInSteps: 10000,
+				IsNotLeader: map[uint64]Occurred{
+					0: Maybe,
+					1: Maybe,
+					2: Maybe,
+					3: Yes,
+				},
+			},
+		}),
+		Entry("node4 is added to the network", TestConf{
+			Spec: Spec{
+				NodeCount:     5,
+				ClientCount:   1,
+				ReqsPerClient: 100,
+				TweakRecorder: func(r *Recorder) {
+					r.NetworkState = mirbft.StandardInitialNetworkState(4, 1)
+					r.NodeConfigs[4].Joining = true
+					r.ReconfigPoints = []*ReconfigPoint{
+						{
+							ClientID: 0,
+							ReqNo:    10,
+							Reconfiguration: &msgs.Reconfiguration{
+								Type: &msgs.Reconfiguration_AddNode{
+									AddNode: 4,
+								},
+							},
+						},
+					}
+				},
+			},
+			Assertions: Assertions{
+				CompletesInSteps: 12000,
+				StateTransferOccurred: map[uint64]Occurred{
+					0: No,
+					4: Yes,
+				},
+				IsNotLeader: map[uint64]Occurred{
+					0: Maybe,
+					1: Maybe,
+					2: Maybe,
+					3: Maybe,
+					4: Maybe,
+				},
+			},
+		}),
 		Entry("network drops 2 percent of messages", TestConf{
 			Spec: Spec{
 				NodeCount:     4,
@@ -355,7 +533,12 @@ var _ = Describe("Mirbft", func() {
 				},
 			},
 			Assertions: Assertions{
-				CompletesInSteps: 20000,
+				// The leader forwards requests to the nodes whose acks it has
+				// not seen, and as every queued event draws from the mangler's
+				// random source, the forwards change which acks are dropped.
+				// Depending on the drops, this test takes either about 15000
+				// or about 27000 steps, with or without forwarding.
+				CompletesInSteps: 30000,
 				IsNotLeader: map[uint64]Occurred{
 					0: Maybe,
 					1: Maybe,
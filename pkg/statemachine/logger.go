@@ -21,6 +21,10 @@ const (
 
 type consoleLogger LogLevel
 
+func (l consoleLogger) Enabled(level LogLevel, component string) bool {
+	return level >= LogLevel(l)
+}
+
 func (l consoleLogger) Log(level LogLevel, text string, args ...interface{}) {
 	if level < LogLevel(l) {
 		return
@@ -65,3 +69,52 @@ type Logger interface {
 	// values are unspecified.
 	Log(level LogLevel, text string, args ...interface{})
 }
+
+// LevelChecker may be implemented by a Logger which discards some entries,
+// so that the state machine may skip building entries which would be
+// discarded anyway.
+type LevelChecker interface {
+	// Enabled returns whether an entry at the given level, from the given
+	// component, would be logged.
+	Enabled(level LogLevel, component string) bool
+}
+
+// ComponentKey is the key of the first key/value pair of every log entry
+// emitted by a component of the state machine, its value is the name of
+// the component, so that the entries may be filtered by component.
+const ComponentKey = "component"
+
+// The names of the components of the state machine which tag their logs.
+const (
+	ComponentPersisted              = "persisted"
+	ComponentNodeBuffers            = "node_buffers"
+	ComponentCheckpointTracker      = "checkpoint_tracker"
+	ComponentClientTracker          = "client_tracker"
+	ComponentCommitState            = "commit_state"
+	ComponentClientHashDisseminator = "client_hash_disseminator"
+	ComponentEpochTracker           = "epoch_tracker"
+)
+
+// componentLogger prepends the component tag to the details of each entry.
+type componentLogger struct {
+	logger    Logger
+	checker   LevelChecker
+	component string
+}
+
+func newComponentLogger(logger Logger, component string) Logger {
+	checker, _ := logger.(LevelChecker)
+	return &componentLogger{
+		logger:    logger,
+		checker:   checker,
+		component: component,
+	}
+}
+
+func (cl *componentLogger) Log(level LogLevel, text string, args ...interface{}) {
+	if cl.checker != nil && !cl.checker.Enabled(level, cl.component) {
+		return
+	}
+
+	cl.logger.Log(level, text, append([]interface{}{ComponentKey, cl.component}, args...)...)
+}
@@ -0,0 +1,314 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+var _ = Describe("oddities", func() {
+	var (
+		s *sequence
+	)
+
+	BeforeEach(func() {
+		s = newSequence(
+			0, 4, 5, nil,
+			&msgs.NetworkState_Config{
+				Nodes: []uint64{0, 1, 2, 3},
+				F:     1,
+			},
+			&state.EventInitialParameters{
+				Id: 1,
+			},
+			ConsoleErrorLogger,
+		)
+	})
+
+	It("reports a duplicate prepare from a non-owner", func() {
+		s.nodeChoice(2).state = nodeSeqPreprepared
+
+		actions := s.applyPrepareMsg(2, []byte("digest"))
+		Expect(actions).To(Equal((&ActionList{}).Oddity(2, &msgs.Msg{
+			Type: &msgs.Msg_Prepare{
+				Prepare: &msgs.Prepare{
+					SeqNo:  5,
+					Epoch:  4,
+					Digest: []byte("digest"),
+				},
+			},
+		}, state.ActionOddity_DUPLICATE_PREPARE)))
+	})
+
+	It("reports a duplicate commit", func() {
+		s.nodeChoice(3).state = nodeSeqPrepared
+
+		actions := s.applyCommitMsg(3, []byte("digest"))
+		Expect(actions).To(Equal((&ActionList{}).Oddity(3, &msgs.Msg{
+			Type: &msgs.Msg_Commit{
+				Commit: &msgs.Commit{
+					SeqNo:  5,
+					Epoch:  4,
+					Digest: []byte("digest"),
+				},
+			},
+		}, state.ActionOddity_DUPLICATE_COMMIT)))
+	})
+
+	It("reports a prepare which disagrees with our digest once against its source", func() {
+		s.state = sequencePreprepared
+		s.digest = []byte("digest")
+		s.nodeChoices[1] = &nodeSeqChoice{
+			state:  nodeSeqPreprepared,
+			digest: []byte("digest"),
+		}
+		s.nodeChoices[2] = &nodeSeqChoice{
+			state:  nodeSeqPreprepared,
+			digest: []byte("other"),
+		}
+
+		actions := s.advanceState()
+		Expect(actions).To(Equal((&ActionList{}).Oddity(2, &msgs.Msg{
+			Type: &msgs.Msg_Prepare{
+				Prepare: &msgs.Prepare{
+					SeqNo:  5,
+					Epoch:  4,
+					Digest: []byte("other"),
+				},
+			},
+		}, state.ActionOddity_PREPARE_DIGEST_MISMATCH)))
+
+		Expect(s.advanceState()).To(Equal(&ActionList{}))
+	})
+
+	It("reports a preprepare which disagrees with our digest once against the owner", func() {
+		s.state = sequencePreprepared
+		s.batch = []*msgs.RequestAck{
+			{ClientId: 1, ReqNo: 2, Digest: []byte("request")},
+		}
+		s.digest = []byte("digest")
+		s.nodeChoices[1] = &nodeSeqChoice{
+			state:  nodeSeqPreprepared,
+			digest: []byte("other"),
+		}
+
+		actions := s.advanceState()
+		Expect(actions).To(Equal((&ActionList{}).Oddity(0, &msgs.Msg{
+			Type: &msgs.Msg_Preprepare{
+				Preprepare: &msgs.Preprepare{
+					SeqNo: 5,
+					Epoch: 4,
+					Batch: s.batch,
+				},
+			},
+		}, state.ActionOddity_PREPARE_DIGEST_MISMATCH)))
+
+		Expect(s.advanceState()).To(Equal(&ActionList{}))
+	})
+})
+
+var _ = Describe("new epoch oddities", func() {
+	var (
+		networkConfig *msgs.NetworkState_Config
+		newEpoch      *msgs.NewEpoch
+		et            *epochTarget
+	)
+
+	BeforeEach(func() {
+		networkConfig = &msgs.NetworkState_Config{
+			Nodes: []uint64{0, 1, 2, 3},
+			F:     1,
+		}
+
+		newEpoch = &msgs.NewEpoch{
+			NewConfig: &msgs.NewEpochConfig{
+				Config: &msgs.EpochConfig{
+					Number: 3,
+				},
+			},
+			EpochChanges: []*msgs.NewEpoch_RemoteEpochChange{
+				{NodeId: 1, Digest: []byte("change")},
+				{NodeId: 1, Digest: []byte("change")},
+			},
+		}
+
+		et = &epochTarget{
+			number:         3,
+			networkConfig:  networkConfig,
+			logger:         ConsoleErrorLogger,
+			leaderNewEpoch: newEpoch,
+			changes: map[nodeID]*epochChange{
+				1: {
+					networkConfig: networkConfig,
+					parsedByDigest: map[string]*parsedEpochChange{
+						"change": {
+							acks: map[nodeID]struct{}{0: {}, 1: {}, 2: {}},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("reports a new epoch message which is not from the primary", func() {
+		tracker := &epochTracker{
+			currentEpoch:  et,
+			networkConfig: networkConfig,
+			logger:        ConsoleErrorLogger,
+		}
+
+		msg := &msgs.Msg{
+			Type: &msgs.Msg_NewEpoch{
+				NewEpoch: newEpoch,
+			},
+		}
+
+		actions := tracker.applyMsg(2, msg)
+		Expect(actions).To(Equal((&ActionList{}).Oddity(2, msg, state.ActionOddity_NEW_EPOCH_NOT_FROM_PRIMARY)))
+	})
+
+	It("reports a malformed new epoch message from the primary once", func() {
+		actions := et.verifyNewEpochState()
+		Expect(actions).To(Equal((&ActionList{}).Oddity(3, &msgs.Msg{
+			Type: &msgs.Msg_NewEpoch{
+				NewEpoch: newEpoch,
+			},
+		}, state.ActionOddity_NEW_EPOCH_DUPLICATE_EPOCH_CHANGE)))
+		Expect(et.leaderInvalid).To(BeTrue())
+
+		Expect(et.verifyNewEpochState()).To(Equal(&ActionList{}))
+	})
+})
+
+var _ = Describe("bad batches", func() {
+	var (
+		et  *epochTarget
+		msg *msgs.Msg
+	)
+
+	BeforeEach(func() {
+		p := newPersisted(ConsoleErrorLogger)
+		p.appendInitialLoad(1, &msgs.Persistent{
+			Type: &msgs.Persistent_NEntry{
+				NEntry: &msgs.NEntry{},
+			},
+		})
+
+		et = &epochTarget{
+			number:    3,
+			persisted: p,
+			networkConfig: &msgs.NetworkState_Config{
+				Nodes: []uint64{0, 1, 2, 3},
+				F:     1,
+			},
+			logger: ConsoleErrorLogger,
+		}
+
+		msg = &msgs.Msg{
+			Type: &msgs.Msg_Preprepare{
+				Preprepare: &msgs.Preprepare{
+					SeqNo: 7,
+					Epoch: 3,
+				},
+			},
+		}
+	})
+
+	It("suspects the epoch once, and records the evidence", func() {
+		suspect := &msgs.Suspect{
+			Epoch: 3,
+		}
+
+		actions := et.suspectBadBatch(2, 7, "no such client", msg, state.ActionOddity_INVALID_BATCH)
+		Expect(actions).To(Equal((&ActionList{}).
+			Oddity(2, msg, state.ActionOddity_INVALID_BATCH).
+			Send([]uint64{0, 1, 2, 3}, &msgs.Msg{
+				Type: &msgs.Msg_Suspect{
+					Suspect: suspect,
+				},
+			}).
+			Persist(2, &msgs.Persistent{
+				Type: &msgs.Persistent_Suspect{
+					Suspect: suspect,
+				},
+			}),
+		))
+
+		actions = et.suspectBadBatch(2, 11, "no such client", msg, state.ActionOddity_INVALID_BATCH)
+		Expect(actions).To(Equal((&ActionList{}).Oddity(2, msg, state.ActionOddity_INVALID_BATCH)))
+
+		Expect(et.status().BadBatches).To(Equal([]*status.BadBatch{
+			{Source: 2, SeqNo: 7, Reason: "no such client"},
+			{Source: 2, SeqNo: 11, Reason: "no such client"},
+		}))
+	})
+})
+
+var _ = Describe("bad forwarded batches", func() {
+	var (
+		bt          *batchTracker
+		verifyBatch *state.HashOrigin_VerifyBatch
+	)
+
+	BeforeEach(func() {
+		bt = newBatchTracker(nil)
+		bt.fetchBatch(7, []byte("digest"), []uint64{0, 1, 2})
+
+		verifyBatch = &state.HashOrigin_VerifyBatch{
+			Source:         1,
+			SeqNo:          7,
+			ExpectedDigest: []byte("digest"),
+		}
+	})
+
+	It("fetches the batch again from the remaining sources", func() {
+		Expect(bt.applyVerifyBatchHashResult([]byte("bad-digest"), verifyBatch)).To(BeFalse())
+
+		actions := bt.refetchBatch(verifyBatch)
+		Expect(actions).To(Equal((&ActionList{}).Send([]uint64{0, 2}, &msgs.Msg{
+			Type: &msgs.Msg_FetchBatch{
+				FetchBatch: &msgs.FetchBatch{
+					SeqNo:  7,
+					Digest: []byte("digest"),
+				},
+			},
+		})))
+
+		By("ignoring further bad batches from the discarded source")
+		Expect(bt.refetchBatch(verifyBatch)).To(Equal(&ActionList{}))
+
+		By("completing the fetch once a source forwards the batch")
+		verifyBatch.Source = 2
+		Expect(bt.applyVerifyBatchHashResult([]byte("digest"), verifyBatch)).To(BeTrue())
+		Expect(bt.hasFetchInFlight()).To(BeFalse())
+		Expect(bt.refetchBatch(verifyBatch)).To(Equal(&ActionList{}))
+	})
+
+	It("fails the fetch when its only source forwards a bad batch", func() {
+		bt = newBatchTracker(nil)
+		bt.fetchBatch(7, []byte("digest"), []uint64{1})
+
+		Expect(bt.applyVerifyBatchHashResult([]byte("bad-digest"), verifyBatch)).To(BeFalse())
+		Expect(bt.refetchBatch(verifyBatch)).To(Equal(&ActionList{}))
+		Expect(bt.hasFetchInFlight()).To(BeFalse())
+
+		By("fetching the batch anew")
+		Expect(bt.fetchBatch(7, []byte("digest"), []uint64{1})).To(Equal((&ActionList{}).Send([]uint64{1}, &msgs.Msg{
+			Type: &msgs.Msg_FetchBatch{
+				FetchBatch: &msgs.FetchBatch{
+					SeqNo:  7,
+					Digest: []byte("digest"),
+				},
+			},
+		})))
+	})
+})
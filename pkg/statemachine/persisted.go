@@ -136,6 +136,16 @@ func (p *persisted) addSuspect(suspect *msgs.Suspect) *ActionList {
 	return p.appendLogEntry(d)
 }
 
+func (p *persisted) addFEntry(fEntry *msgs.FEntry) *ActionList {
+	d := &msgs.Persistent{
+		Type: &msgs.Persistent_FEntry{
+			FEntry: fEntry,
+		},
+	}
+
+	return p.appendLogEntry(d)
+}
+
 func (p *persisted) addECEntry(ecEntry *msgs.ECEntry) *ActionList {
 	d := &msgs.Persistent{
 		Type: &msgs.Persistent_ECEntry{
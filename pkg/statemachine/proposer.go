@@ -28,7 +28,12 @@ type proposer struct {
 
 type proposalBucket struct {
 	requestCount       uint32
+	byteCount          uint64 // the maximum size of a batch in bytes, or unbounded if zero
+	latencyTicks       uint32 // the maximum ticks a partial batch is held, or unbounded if zero
 	pending            []*clientRequest
+	pendingBytes       uint64 // the total size of the pending requests
+	pendingTicks       uint32 // the number of ticks since the first pending request was queued
+	full               bool   // set when the next ready request would exceed the byte count
 	bucketID           bucketID
 	checkpointInterval uint64
 
@@ -60,6 +65,8 @@ func newProposer(baseCheckpoint uint64, checkpointInterval uint64, myConfig *sta
 			readyList:          list.New(),
 			nextReadyList:      list.New(),
 			requestCount:       myConfig.BatchSize,
+			byteCount:          myConfig.BatchSizeBytes,
+			latencyTicks:       myConfig.MaxBatchLatencyTicks,
 			pending:            make([]*clientRequest, 0, 1), // TODO, might be interesting to play with not preallocating for performance reasons
 		}
 	}
@@ -132,15 +139,41 @@ func (prb *proposalBucket) advance(toSeqNo uint64) {
 		prb.nextReadyList = list.New()
 	}
 
-	for uint32(len(prb.pending)) < prb.requestCount {
+	for !prb.full && uint32(len(prb.pending)) < prb.requestCount {
 		if prb.readyList.Len() == 0 {
 			break
 		}
 
-		prb.pending = append(
-			prb.pending,
-			prb.readyList.Remove(prb.readyList.Front()).(*clientRequest),
-		)
+		cr := prb.readyList.Front().Value.(*clientRequest)
+
+		// The size of a request is only known once we have stored it, as
+		// we may propose a request which reached a strong quorum without
+		// ever receiving it, so such a request is treated as oversized.
+		sizeKnown := cr.stored || len(cr.ack.Digest) == 0
+
+		// A request which would push the batch beyond the byte count is left
+		// to start the next batch, unless the batch is empty, in which case
+		// the oversized request is sent in a batch of its own.
+		if prb.byteCount != 0 && len(prb.pending) > 0 && (!sizeKnown || prb.pendingBytes+cr.size > prb.byteCount) {
+			prb.full = true
+			break
+		}
+
+		prb.readyList.Remove(prb.readyList.Front())
+		prb.pending = append(prb.pending, cr)
+		prb.pendingBytes += cr.size
+
+		if prb.byteCount != 0 && (!sizeKnown || prb.pendingBytes >= prb.byteCount) {
+			prb.full = true
+		}
+	}
+}
+
+// tick counts the ticks for which requests have been pending, so that
+// a partial batch may be cut once the latency ticks have elapsed.
+func (prb *proposalBucket) tick() {
+	if len(prb.pending) > 0 {
+		prb.pendingTicks++
 	}
 }
 
@@ -151,11 +184,20 @@ func (prb *proposalBucket) hasOutstanding(forSeqNo uint64) bool {
 
 func (prb *proposalBucket) hasPending(forSeqNo uint64) bool {
 	prb.advance(forSeqNo)
-	return len(prb.pending) > 0 && uint32(len(prb.pending)) == prb.requestCount
+	if len(prb.pending) == 0 {
+		return false
+	}
+
+	return prb.full ||
+		uint32(len(prb.pending)) == prb.requestCount ||
+		(prb.latencyTicks != 0 && prb.pendingTicks >= prb.latencyTicks)
 }
 
 func (prb *proposalBucket) next() []*clientRequest {
 	result := prb.pending
 	prb.pending = make([]*clientRequest, 0, prb.requestCount)
+	prb.pendingBytes = 0
+	prb.pendingTicks = 0
+	prb.full = false
 	return result
 }
@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+)
+
+var _ = Describe("proposer", func() {
+	var (
+		tracker *clientTracker
+		reqNo   uint64
+	)
+
+	// ready queues the next request of client 0 as strongly correct,
+	// optionally having stored it with the given size.
+	ready := func(stored bool, size uint64) *clientRequest {
+		cr := &clientRequest{
+			ack: &msgs.RequestAck{
+				ReqNo:  reqNo,
+				Digest: uint64ToBytes(reqNo),
+			},
+			stored: stored,
+			size:   size,
+		}
+
+		tracker.addReady(&clientReqNo{
+			reqNo: reqNo,
+			strongRequests: map[string]*clientRequest{
+				string(cr.ack.Digest): cr,
+			},
+		})
+		reqNo++
+
+		return cr
+	}
+
+	newBucket := func() *proposalBucket {
+		p := newProposer(0, 5, &state.EventInitialParameters{
+			BatchSize:      20,
+			BatchSizeBytes: 34,
+		}, tracker, map[bucketID]nodeID{0: 0})
+		p.advance(1)
+		return p.proposalBucket(0)
+	}
+
+	BeforeEach(func() {
+		tracker = &clientTracker{
+			readyList: newReadyList(),
+		}
+		reqNo = 0
+	})
+
+	It("cuts batches of stored requests at the byte count", func() {
+		first, second := ready(true, 17), ready(true, 17)
+		third := ready(true, 17)
+
+		prb := newBucket()
+		Expect(prb.hasPending(1)).To(BeTrue())
+		Expect(prb.next()).To(Equal([]*clientRequest{first, second}))
+		Expect(prb.hasOutstanding(1)).To(BeTrue())
+		Expect(prb.next()).To(Equal([]*clientRequest{third}))
+	})
+
+	It("proposes requests it never received in batches of their own", func() {
+		stored := ready(true, 10)
+		unstored := []*clientRequest{ready(false, 0), ready(false, 0)}
+
+		prb := newBucket()
+		Expect(prb.hasPending(1)).To(BeTrue())
+		Expect(prb.next()).To(Equal([]*clientRequest{stored}))
+		Expect(prb.hasPending(1)).To(BeTrue())
+		Expect(prb.next()).To(Equal(unstored[:1]))
+		Expect(prb.hasPending(1)).To(BeTrue())
+		Expect(prb.next()).To(Equal(unstored[1:]))
+		Expect(prb.hasOutstanding(1)).To(BeFalse())
+	})
+})
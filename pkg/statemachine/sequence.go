@@ -72,6 +72,10 @@ type sequence struct {
 
 	prepares map[string]int
 	commits  map[string]int
+
+	// mismatchesReported holds the nodes we have reported for a prepare,
+	// or in the case of the owner, a preprepare, disagreeing with our digest.
+	mismatchesReported map[nodeID]struct{}
 }
 
 func newSequence(owner nodeID, epoch, seqNo uint64, persisted *persisted, networkConfig *msgs.NetworkState_Config, myConfig *state.EventInitialParameters, logger Logger) *sequence {
@@ -87,6 +91,8 @@ func newSequence(owner nodeID, epoch, seqNo uint64, persisted *persisted, networ
 		nodeChoices:   map[nodeID]*nodeSeqChoice{},
 		prepares:      map[string]int{},
 		commits:       map[string]int{},
+
+		mismatchesReported: map[nodeID]struct{}{},
 	}
 }
 
@@ -219,6 +225,9 @@ func (s *sequence) prepare() *ActionList {
 					nodes = append(nodes, id)
 				}
 			}
+			if len(nodes) == 0 {
+				continue
+			}
 			actions.ForwardRequest(
 				nodes,
 				cr.ack,
@@ -261,8 +270,15 @@ func (s *sequence) applyPrepareMsg(source nodeID, digest []byte) *ActionList {
 	// the only prepare we get from the owner is our own artificial,
 	// and the choice has already been recorded for the preprepare.
 	if source != s.owner && choice.state > nodeSeqUninitialized {
-		// TODO log oddity
-		return &ActionList{}
+		return s.oddity(source, &msgs.Msg{
+			Type: &msgs.Msg_Prepare{
+				Prepare: &msgs.Prepare{
+					SeqNo:  s.seqNo,
+					Epoch:  s.epoch,
+					Digest: digest,
+				},
+			},
+		}, state.ActionOddity_DUPLICATE_PREPARE)
 	}
 
 	choice.state = nodeSeqPreprepared
@@ -270,7 +286,48 @@ func (s *sequence) applyPrepareMsg(source nodeID, digest []byte) *ActionList {
 
 	s.prepares[string(digest)] = s.prepares[string(digest)] + 1
 
-	return s.advanceState()
+	actions := &ActionList{}
+	if s.state > sequencePreprepared {
+		// Prepares which arrive before we have prepared are
+		// checked against our digest by checkPrepareQuorum.
+		actions.concat(s.reportPrepareMismatches())
+	}
+
+	return actions.concat(s.advanceState())
+}
+
+// reportPrepareMismatches reports, once per node, the prepares which
+// disagree with our digest of the batch.
+func (s *sequence) reportPrepareMismatches() *ActionList {
+	actions := &ActionList{}
+	for _, id := range s.networkConfig.Nodes {
+		source := nodeID(id)
+		if source == s.owner {
+			continue
+		}
+
+		choice, ok := s.nodeChoices[source]
+		if !ok || choice.state < nodeSeqPreprepared || bytes.Equal(choice.digest, s.digest) {
+			continue
+		}
+
+		if _, ok := s.mismatchesReported[source]; ok {
+			continue
+		}
+		s.mismatchesReported[source] = struct{}{}
+
+		actions.concat(s.oddity(source, &msgs.Msg{
+			Type: &msgs.Msg_Prepare{
+				Prepare: &msgs.Prepare{
+					SeqNo:  s.seqNo,
+					Epoch:  s.epoch,
+					Digest: choice.digest,
+				},
+			},
+		}, state.ActionOddity_PREPARE_DIGEST_MISMATCH))
+	}
+
+	return actions
 }
 
 func (s *sequence) checkPrepareQuorum() *ActionList {
@@ -284,16 +341,32 @@ func (s *sequence) checkPrepareQuorum() *ActionList {
 	}
 
 	if !bytes.Equal(myChoice.digest, s.digest) {
-		// TODO, log oddity, we have different digest than what net says is correct
-		return &ActionList{}
+		// The batch the owner preprepared does not hash to the digest
+		// we chose for this sequence.
+		if _, ok := s.mismatchesReported[s.owner]; ok {
+			return &ActionList{}
+		}
+		s.mismatchesReported[s.owner] = struct{}{}
+
+		return s.oddity(s.owner, &msgs.Msg{
+			Type: &msgs.Msg_Preprepare{
+				Preprepare: &msgs.Preprepare{
+					SeqNo: s.seqNo,
+					Epoch: s.epoch,
+					Batch: s.batch,
+				},
+			},
+		}, state.ActionOddity_PREPARE_DIGEST_MISMATCH)
 	}
 
+	actions := s.reportPrepareMismatches()
+
 	// We do require 2f+1 prepares (instead of 2f), as the preprepare
 	// for the leader will be applied as a prepare here
 	requiredPrepares := intersectionQuorum(s.networkConfig)
 
 	if agreements < requiredPrepares {
-		return &ActionList{}
+		return actions
 	}
 
 	s.state = sequencePrepared
@@ -303,7 +376,7 @@ func (s *sequence) checkPrepareQuorum() *ActionList {
 		Digest: s.digest,
 	}
 
-	return s.persisted.addPEntry(pEntry).Send(
+	return actions.concat(s.persisted.addPEntry(pEntry)).Send(
 		s.networkConfig.Nodes,
 		&msgs.Msg{
 			Type: &msgs.Msg_Commit{
@@ -320,8 +393,15 @@ func (s *sequence) checkPrepareQuorum() *ActionList {
 func (s *sequence) applyCommitMsg(source nodeID, digest []byte) *ActionList {
 	choice := s.nodeChoice(source)
 	if choice.state > nodeSeqPreprepared {
-		// TODO log oddity
-		return &ActionList{}
+		return s.oddity(source, &msgs.Msg{
+			Type: &msgs.Msg_Commit{
+				Commit: &msgs.Commit{
+					SeqNo:  s.seqNo,
+					Epoch:  s.epoch,
+					Digest: digest,
+				},
+			},
+		}, state.ActionOddity_DUPLICATE_COMMIT)
 	}
 
 	choice.state = nodeSeqPrepared
@@ -353,3 +433,10 @@ func (s *sequence) checkCommitQuorum() {
 
 	s.state = sequenceCommitted
 }
+
+// oddity reports a node which sent a message for this sequence
+// in violation of the protocol.
+func (s *sequence) oddity(source nodeID, msg *msgs.Msg, rule state.ActionOddity_Rule) *ActionList {
+	s.logger.Log(LevelWarn, "ignoring odd message", "source", source, "seq_no", s.seqNo, "epoch_no", s.epoch, "rule", rule)
+	return (&ActionList{}).Oddity(uint64(source), msg, rule)
+}
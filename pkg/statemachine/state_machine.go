@@ -118,7 +118,7 @@ func (sm *StateMachine) initialize(parameters *state.EventInitialParameters) {
 
 	sm.myConfig = parameters
 	sm.state = smLoadingPersisted
-	sm.persisted = newPersisted(sm.Logger)
+	sm.persisted = newPersisted(newComponentLogger(sm.Logger, ComponentPersisted))
 
 	// we use a dummy initial state for components to allow us to use
 	// a common 'reconfiguration'/'state transfer' path for initialization.
@@ -131,18 +131,18 @@ func (sm *StateMachine) initialize(parameters *state.EventInitialParameters) {
 		},
 	}
 
-	sm.nodeBuffers = newNodeBuffers(sm.myConfig, sm.Logger)
-	sm.checkpointTracker = newCheckpointTracker(0, dummyInitialState, sm.persisted, sm.nodeBuffers, sm.myConfig, sm.Logger)
-	sm.clientTracker = newClientTracker(sm.myConfig, sm.Logger)
-	sm.commitState = newCommitState(sm.persisted, sm.Logger)
-	sm.clientHashDisseminator = newClientHashDisseminator(sm.nodeBuffers, sm.myConfig, sm.Logger, sm.clientTracker)
+	sm.nodeBuffers = newNodeBuffers(sm.myConfig, newComponentLogger(sm.Logger, ComponentNodeBuffers))
+	sm.checkpointTracker = newCheckpointTracker(0, dummyInitialState, sm.persisted, sm.nodeBuffers, sm.myConfig, newComponentLogger(sm.Logger, ComponentCheckpointTracker))
+	sm.clientTracker = newClientTracker(sm.myConfig, newComponentLogger(sm.Logger, ComponentClientTracker))
+	sm.commitState = newCommitState(sm.persisted, sm.checkpointTracker, sm.myConfig, newComponentLogger(sm.Logger, ComponentCommitState))
+	sm.clientHashDisseminator = newClientHashDisseminator(sm.nodeBuffers, sm.myConfig, newComponentLogger(sm.Logger, ComponentClientHashDisseminator), sm.clientTracker)
 	sm.batchTracker = newBatchTracker(sm.persisted)
 	sm.epochTracker = newEpochTracker(
 		sm.persisted,
 		sm.nodeBuffers,
 		sm.commitState,
 		dummyInitialState.Config,
-		sm.Logger,
+		newComponentLogger(sm.Logger, ComponentEpochTracker),
 		sm.myConfig,
 		sm.batchTracker,
 		sm.clientTracker,
@@ -190,6 +190,7 @@ func (sm *StateMachine) applyEvent(stateEvent *state.Event) *ActionList {
 		assertInitialized()
 		actions.concat(sm.clientHashDisseminator.tick())
 		actions.concat(sm.epochTracker.tick())
+		actions.concat(sm.commitState.tick())
 	case *state.Event_Step:
 		assertInitialized()
 		actions.concat(sm.step(
@@ -206,10 +207,15 @@ func (sm *StateMachine) applyEvent(stateEvent *state.Event) *ActionList {
 		assertInitialized()
 		actions.concat(sm.clientHashDisseminator.applyNewRequest(
 			event.RequestPersisted.RequestAck,
+			event.RequestPersisted.RequestSize,
 		))
 	case *state.Event_StateTransferFailed:
+		assertInitialized()
 		sm.Logger.Log(LevelDebug, "state transfer failed", "seq_no", event.StateTransferFailed.SeqNo)
-		panic("XXX handle state transfer failure")
+		sm.commitState.transferFailed(
+			event.StateTransferFailed.SeqNo,
+			event.StateTransferFailed.CheckpointValue,
+		)
 	case *state.Event_StateTransferComplete:
 		assertEqualf(sm.commitState.transferring, true, "state transfer event received but the state machine did not request transfer")
 
@@ -240,14 +246,18 @@ func (sm *StateMachine) applyEvent(stateEvent *state.Event) *ActionList {
 		newLow := sm.checkpointTracker.garbageCollect()
 		sm.Logger.Log(LevelDebug, "garbage collecting through", "seq_no", newLow)
 
-		sm.persisted.truncate(newLow)
+		if sm.checkpointTracker.state == cpsPendingReconfig {
+			actions.concat(sm.reconfigure())
+		} else {
+			sm.persisted.truncate(newLow)
 
-		if newLow > uint64(sm.checkpointTracker.networkConfig.CheckpointInterval) {
-			// Note, we leave an extra checkpoint worth of batches around, to help
-			// during epoch change.
-			sm.batchTracker.truncate(newLow - uint64(sm.checkpointTracker.networkConfig.CheckpointInterval))
+			if newLow > uint64(sm.checkpointTracker.networkConfig.CheckpointInterval) {
+				// Note, we leave an extra checkpoint worth of batches around, to help
+				// during epoch change.
+				sm.batchTracker.truncate(newLow - uint64(sm.checkpointTracker.networkConfig.CheckpointInterval))
+			}
+			actions.concat(sm.epochTracker.moveLowWatermark(newLow))
 		}
-		actions.concat(sm.epochTracker.moveLowWatermark(newLow))
 	}
 
 	for {
@@ -286,6 +296,27 @@ func (sm *StateMachine) reinitialize() *ActionList {
 	return actions.concat(sm.epochTracker.reinitialize())
 }
 
+// reconfigure is invoked once the checkpoint which applies a reconfiguration
+// is stable.  The current epoch is ended at this checkpoint by persisting an
+// FEntry, and the state machine is reinitialized from the checkpoint, so that
+// the next epoch change is among the nodes of the new network configuration.
+func (sm *StateMachine) reconfigure() *ActionList {
+	epochConfig := &msgs.EpochConfig{
+		Number: sm.epochTracker.currentEpoch.number,
+	}
+	if sm.epochTracker.currentEpoch.activeEpoch != nil {
+		epochConfig = sm.epochTracker.currentEpoch.activeEpoch.epochConfig
+	}
+
+	sm.Logger.Log(LevelInfo, "reconfiguring, ending epoch", "epoch_no", epochConfig.Number)
+
+	actions := sm.persisted.addFEntry(&msgs.FEntry{
+		EndsEpochConfig: epochConfig,
+	})
+
+	return actions.concat(sm.reinitialize())
+}
+
 // Truncates the WAL based on the last FEntry found.
 func (sm *StateMachine) recoverLog() *ActionList {
 	var lastCEntry *msgs.CEntry
@@ -309,6 +340,15 @@ func (sm *StateMachine) recoverLog() *ActionList {
 
 func (sm *StateMachine) step(source nodeID, msg *msgs.Msg) *ActionList {
 	actions := &ActionList{}
+
+	if !isMember(source, sm.epochTracker.networkConfig) {
+		// The components are initialized for the network configuration in
+		// effect when we last reinitialized, so we ignore any nodes outside
+		// of it, whether they have been removed, or are yet to be added.
+		sm.Logger.Log(LevelDebug, "dropping message from non-member", "source", source)
+		return actions
+	}
+
 	switch msg.Type.(type) {
 	case *msgs.Msg_RequestAck:
 		return actions.concat(sm.clientHashDisseminator.step(source, msg))
@@ -360,7 +400,25 @@ func (sm *StateMachine) processHashResult(hashResult *state.EventHashResult) *Ac
 	case *state.HashOrigin_VerifyBatch_:
 		actions := &ActionList{}
 		verifyBatch := hashType.VerifyBatch
-		sm.batchTracker.applyVerifyBatchHashResult(hashResult.Digest, verifyBatch)
+		if !sm.batchTracker.applyVerifyBatchHashResult(hashResult.Digest, verifyBatch) {
+			// The source, and not the leader, forwarded a bad batch, so
+			// rather than suspect the epoch, we fetch from another source.
+			sm.Logger.Log(LevelWarn, "forwarded batch has unexpected digest", "source", verifyBatch.Source, "seq_no", verifyBatch.SeqNo, "digest", hashResult.Digest, "expected_digest", verifyBatch.ExpectedDigest)
+			actions.Oddity(
+				verifyBatch.Source,
+				&msgs.Msg{
+					Type: &msgs.Msg_ForwardBatch{
+						ForwardBatch: &msgs.ForwardBatch{
+							SeqNo:       verifyBatch.SeqNo,
+							Digest:      verifyBatch.ExpectedDigest,
+							RequestAcks: verifyBatch.RequestAcks,
+						},
+					},
+				},
+				state.ActionOddity_BATCH_DIGEST_MISMATCH,
+			)
+			actions.concat(sm.batchTracker.refetchBatch(verifyBatch))
+		}
 		if !sm.batchTracker.hasFetchInFlight() && sm.epochTracker.currentEpoch.state == etFetching {
 			actions.concat(sm.epochTracker.currentEpoch.fetchNewEpochState())
 		}
@@ -392,6 +450,9 @@ func (sm *StateMachine) processCheckpointResult(checkpointResult *state.EventChe
 
 	prevStopAtSeqNo := sm.commitState.stopAtSeqNo
 	actions.concat(sm.commitState.applyCheckpointResult(epochConfig, checkpointResult))
+	if len(checkpointResult.NetworkState.PendingReconfigurations) > 0 {
+		sm.checkpointTracker.reconfigureAt(checkpointResult.SeqNo + uint64(checkpointResult.NetworkState.Config.CheckpointInterval))
+	}
 	if prevStopAtSeqNo < sm.commitState.stopAtSeqNo {
 		sm.clientTracker.allocate(checkpointResult.SeqNo, checkpointResult.NetworkState)
 		actions.concat(sm.clientHashDisseminator.allocate(checkpointResult.SeqNo, checkpointResult.NetworkState))
@@ -434,5 +495,6 @@ func (sm *StateMachine) Status() (s *status.StateMachine, err error) {
 		Buckets:       bucketStatus,
 		Checkpoints:   checkpoints,
 		NodeBuffers:   sm.nodeBuffers.status(),
+		StateTransfer: sm.commitState.status(),
 	}, nil
 }
@@ -13,6 +13,7 @@ import (
 	"fmt"
 
 	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
 )
 
 func isCommitted(reqNo uint64, clientState *msgs.NetworkState_Client) bool {
@@ -112,6 +113,73 @@ func someCorrectQuorum(nc *msgs.NetworkState_Config) int {
 	return int(nc.F) + 1
 }
 
+// epochPrimary is the node responsible for sending the NewEpoch message
+// for the given epoch.  Node IDs need not be contiguous, as nodes may be
+// added and removed, so the primary is selected by index.
+func epochPrimary(epochNumber uint64, nc *msgs.NetworkState_Config) nodeID {
+	return nodeID(nc.Nodes[epochNumber%uint64(len(nc.Nodes))])
+}
+
+// isMember returns whether the node is part of the network configuration.
+func isMember(id nodeID, nc *msgs.NetworkState_Config) bool {
+	for _, node := range nc.Nodes {
+		if nodeID(node) == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unionNodes returns the nodes in either a or b, in order of first appearance.
+func unionNodes(a, b []uint64) []uint64 {
+	result := append([]uint64{}, a...)
+	for _, id := range b {
+		found := false
+		for _, existing := range a {
+			if existing == id {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+// The defaults for the optional timeouts of EventInitialParameters,
+// which apply when the parameters are zero.
+const (
+	DefaultFetchTimeoutTicks      = 4
+	DefaultAckResendTicks         = 20
+	DefaultOutOfCorrectEpochTicks = 10
+)
+
+func fetchTimeoutTicks(myConfig *state.EventInitialParameters) uint32 {
+	if myConfig.FetchTimeoutTicks == 0 {
+		return DefaultFetchTimeoutTicks
+	}
+	return myConfig.FetchTimeoutTicks
+}
+
+func ackResendTicks(myConfig *state.EventInitialParameters) uint32 {
+	if myConfig.AckResendTicks == 0 {
+		return DefaultAckResendTicks
+	}
+	return myConfig.AckResendTicks
+}
+
+func outOfCorrectEpochTicks(myConfig *state.EventInitialParameters) uint32 {
+	if myConfig.OutOfCorrectEpochTicks == 0 {
+		return DefaultOutOfCorrectEpochTicks
+	}
+	return myConfig.OutOfCorrectEpochTicks
+}
+
 func clientReqToBucket(clientID, reqNo uint64, nc *msgs.NetworkState_Config) bucketID {
 	return bucketID((clientID + reqNo) % uint64(nc.NumberOfBuckets))
 }
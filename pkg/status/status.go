@@ -80,6 +80,15 @@ type StateMachine struct {
 	Buckets       []*Bucket        `json:"buckets"`
 	Checkpoints   []*Checkpoint    `json:"checkpoints"`
 	ClientWindows []*ClientTracker `json:"client_tracker"`
+	StateTransfer *StateTransfer   `json:"state_transfer"`
+}
+
+// StateTransfer is non-nil in the status only while a state transfer is in progress.
+type StateTransfer struct {
+	SeqNo      uint64 `json:"seq_no"`
+	Value      []byte `json:"value"`
+	Failures   uint   `json:"failures"`
+	RetryTicks uint   `json:"retry_ticks"`
 }
 
 type Bucket struct {
@@ -97,6 +106,12 @@ type Checkpoint struct {
 
 type EpochTracker struct {
 	ActiveEpoch *EpochTarget `json:"last_active_epoch"`
+
+	// FailedEpochs is the number of consecutive epochs which ended without
+	// making progress, each of which doubles the timeouts below.
+	FailedEpochs         uint32 `json:"failed_epochs"`
+	NewEpochTimeoutTicks uint32 `json:"new_epoch_timeout_ticks"`
+	SuspectTicks         uint32 `json:"suspect_ticks"`
 }
 
 type EpochTarget struct {
@@ -107,6 +122,15 @@ type EpochTarget struct {
 	Readies      []uint64         `json:"readies"`
 	Suspicions   []uint64         `json:"suspicions"`
 	Leaders      []uint64         `json:"leaders"`
+	BadBatches   []*BadBatch      `json:"bad_batches"`
+}
+
+// BadBatch is the evidence for which this node suspected an epoch, a
+// batch which was proposed or forwarded by a node, but which was invalid.
+type BadBatch struct {
+	Source uint64 `json:"source"`
+	SeqNo  uint64 `json:"seq_no"`
+	Reason string `json:"reason"`
 }
 
 type EpochChange struct {
@@ -168,8 +192,14 @@ func (s *StateMachine) Pretty() string {
 	fmt.Fprintf(&buffer, "NodeID=%d, LowWatermark=%d, HighWatermark=%d, Epoch=%d\n", s.NodeID, s.LowWatermark, s.HighWatermark, s.EpochTracker.ActiveEpoch.Number)
 	fmt.Fprintf(&buffer, "===========================================\n\n")
 
+	if s.StateTransfer != nil {
+		fmt.Fprintf(&buffer, "=== State Transfer ===\n")
+		fmt.Fprintf(&buffer, "Transferring to SeqNo=%d Value=%.4x Failures=%d RetryTicks=%d\n\n", s.StateTransfer.SeqNo, s.StateTransfer.Value, s.StateTransfer.Failures, s.StateTransfer.RetryTicks)
+	}
+
 	fmt.Fprintf(&buffer, "=== Epoch Number %d ===\n", s.EpochTracker.ActiveEpoch.Number)
 	fmt.Fprintf(&buffer, "Epoch is in state: %d\n", s.EpochTracker.ActiveEpoch.State)
+	fmt.Fprintf(&buffer, "Timeouts: NewEpochTimeoutTicks=%d SuspectTicks=%d FailedEpochs=%d\n", s.EpochTracker.NewEpochTimeoutTicks, s.EpochTracker.SuspectTicks, s.EpochTracker.FailedEpochs)
 
 	et := s.EpochTracker.ActiveEpoch
 	fmt.Fprintf(&buffer, "  EpochChanges:\n")
@@ -182,6 +212,9 @@ func (s *StateMachine) Pretty() string {
 	fmt.Fprintf(&buffer, "  Readies: %v\n", et.Readies)
 	fmt.Fprintf(&buffer, "  Suspicions: %v\n", et.Suspicions)
 	fmt.Fprintf(&buffer, "  Leaders: %v\n", et.Leaders)
+	for _, bb := range et.BadBatches {
+		fmt.Fprintf(&buffer, "  BadBatch: Source=%d SeqNo=%d Reason=%s\n", bb.Source, bb.SeqNo, bb.Reason)
+	}
 	fmt.Fprintf(&buffer, "\n")
 	fmt.Fprintf(&buffer, "=====================\n")
 	fmt.Fprintf(&buffer, "\n")
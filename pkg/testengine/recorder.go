@@ -49,6 +49,11 @@ func (l *Link) Send(dest uint64, msg *msgs.Msg) {
 type NodeConfig struct {
 	InitParms    *state.EventInitialParameters
 	RuntimeParms *RuntimeParameters
+
+	// Joining causes the node to start with an empty WAL, and to wait
+	// until a checkpoint of another node includes it in the network
+	// configuration, before joining the network from that checkpoint.
+	Joining bool
 }
 
 type RuntimeParameters struct {
@@ -62,6 +67,11 @@ type RuntimeParameters struct {
 	ProcessAppLatency      int
 	ProcessReqStoreLatency int
 	ProcessEventsLatency   int
+
+	// StateTransferFailures may be set to cause the first
+	// state transfers requested of this node's application
+	// to fail.  This is useful for testing state transfer retries.
+	StateTransferFailures int
 }
 
 type clientReq struct {
@@ -99,7 +109,6 @@ func NewReqStore() *ReqStore {
 func (rs *ReqStore) PutRequest(ack *msgs.RequestAck, data []byte) error {
 	helper := newAckHelper(ack)
 	rs.requests[helper] = data
-	// TODO, deal with free-ing
 	return nil
 }
 
@@ -119,6 +128,22 @@ func (rs *ReqStore) GetAllocation(clientID, reqNo uint64) ([]byte, error) {
 	return digest, nil
 }
 
+func (rs *ReqStore) GarbageCollect(clientID, lowWatermark uint64) error {
+	for helper := range rs.requests {
+		if helper.clientID == clientID && helper.reqNo < lowWatermark {
+			delete(rs.requests, helper)
+		}
+	}
+
+	for cr := range rs.allocations {
+		if cr.clientID == clientID && cr.reqNo < lowWatermark {
+			delete(rs.allocations, cr)
+		}
+	}
+
+	return nil
+}
+
 func (rs *ReqStore) Sync() error {
 	return nil
 }
@@ -210,6 +235,8 @@ type Node struct {
 	ReqStore                     *ReqStore
 	WorkItems                    *processor.WorkItems
 	Clients                      *processor.Clients
+	Replicas                     *processor.Replicas
+	StateTransfer                *processor.StateTransfer
 	State                        *NodeState
 	ProcessResultEventsPending   bool
 	ProcessReqStoreEventsPending bool
@@ -229,6 +256,11 @@ func (n *Node) Initialize(initParms *state.EventInitialParameters, logger statem
 		Hasher:       n.Hasher,
 	}
 
+	n.Replicas = &processor.Replicas{
+		Clients:       n.Clients,
+		StateTransfer: n.StateTransfer,
+	}
+
 	n.StateMachine = &statemachine.StateMachine{
 		Logger: logger,
 	}
@@ -250,6 +282,22 @@ func (n *Node) Initialize(initParms *state.EventInitialParameters, logger statem
 	return nil
 }
 
+// isMember returns whether the node is among the nodes of its own most
+// recent checkpoint.
+func (n *Node) isMember() bool {
+	if n.State.CheckpointState == nil {
+		return false
+	}
+
+	for _, id := range n.State.CheckpointState.Config.Nodes {
+		if id == n.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
 type RecorderClient struct {
 	Config *ClientConfig
 	Hasher processor.Hasher
@@ -279,10 +327,12 @@ type NodeState struct {
 	CheckpointSeqNo         uint64
 	CheckpointHash          []byte
 	CheckpointState         *msgs.NetworkState
+	TransferFailures        int
 
 	// The below vars are used for assertions on results,
 	// but are not used directly in execution.
 	StateTransfers []uint64
+	BatchBytes     []uint64 // the request bytes of each committed batch
 }
 
 func (ns *NodeState) Snap(networkConfig *msgs.NetworkState_Config, clientsState []*msgs.NetworkState_Client) ([]byte, []*msgs.Reconfiguration, error) {
@@ -315,6 +365,11 @@ func (ns *NodeState) Snap(networkConfig *msgs.NetworkState_Config, clientsState
 
 func (ns *NodeState) TransferTo(seqNo uint64, snap []byte) (*msgs.NetworkState, error) {
 	ns.StateTransfers = append(ns.StateTransfers, seqNo)
+	if ns.TransferFailures > 0 {
+		ns.TransferFailures--
+		return nil, errors.Errorf("injected failure of state transfer to seq_no=%d", seqNo)
+	}
+
 	networkState := &msgs.NetworkState{}
 	if err := proto.Unmarshal(snap[32:], networkState); err != nil {
 		return nil, err
@@ -336,6 +391,7 @@ func (ns *NodeState) Apply(batch *msgs.QEntry) error {
 		return errors.Errorf("unexpected out of order commit sequence number, expected %d, got %d", ns.LastSeqNo, batch.SeqNo)
 	}
 
+	var batchBytes uint64
 	for _, request := range batch.Requests {
 		req, err := ns.ReqStore.GetRequest(request)
 		if err != nil {
@@ -345,6 +401,7 @@ func (ns *NodeState) Apply(batch *msgs.QEntry) error {
 			return errors.Errorf("reqstore should have request if we are committing it")
 		}
 
+		batchBytes += uint64(len(req))
 		ns.ActiveHash.Write(request.Digest)
 
 		for _, reconfigPoint := range ns.ReconfigPoints {
@@ -355,6 +412,8 @@ func (ns *NodeState) Apply(batch *msgs.QEntry) error {
 		}
 	}
 
+	ns.BatchBytes = append(ns.BatchBytes, batchBytes)
+
 	return nil
 }
 
@@ -394,6 +453,11 @@ type Recorder struct {
 	LogOutput      io.Writer
 	Hasher         processor.Hasher
 	RandomSeed     int64
+
+	// BuiltinStateTransfer causes the nodes to use processor.StateTransfer
+	// to fetch checkpoint data from one another, rather than relying
+	// on the checkpoint value to carry the whole of the state.
+	BuiltinStateTransfer bool
 }
 
 type interceptorFunc func(*state.Event) error
@@ -416,29 +480,53 @@ func (r *Recorder) Recording(output *gzip.Writer) (*Recording, error) {
 		reqStore := NewReqStore()
 
 		nodeState := &NodeState{
-			Hasher:         r.Hasher,
-			ActiveHash:     r.Hasher.New(),
-			ReconfigPoints: r.ReconfigPoints,
-			ReqStore:       reqStore,
+			Hasher:           r.Hasher,
+			ActiveHash:       r.Hasher.New(),
+			ReconfigPoints:   r.ReconfigPoints,
+			ReqStore:         reqStore,
+			TransferFailures: recorderNodeConfig.RuntimeParms.StateTransferFailures,
 		}
 
-		checkpointValue, _, err := nodeState.Snap(r.NetworkState.Config, r.NetworkState.Clients)
+		link := &Link{
+			EventQueue: eventQueue,
+			Source:     nodeID,
+			Delay:      int64(recorderNodeConfig.RuntimeParms.LinkLatency),
+		}
+
+		snap := nodeState.Snap
+		var stateTransfer *processor.StateTransfer
+		if r.BuiltinStateTransfer {
+			stateTransfer = &processor.StateTransfer{
+				NodeID: nodeID,
+				Link:   link,
+				Hasher: r.Hasher,
+				App:    nodeState,
+			}
+			snap = stateTransfer.Snap
+		}
+
+		checkpointValue, _, err := snap(r.NetworkState.Config, r.NetworkState.Clients)
 		if err != nil {
 			return nil, errors.WithMessage(err, "could not generate initial checkpoint")
 		}
 
 		wal := NewWAL(r.NetworkState, checkpointValue)
+		if recorderNodeConfig.Joining {
+			wal = &WAL{
+				List:     list.New(),
+				LowIndex: 1,
+			}
+			nodeState.CheckpointState = nil
+		}
 
 		nodes[i] = &Node{
-			Hasher:   r.Hasher,
-			State:    nodeState,
-			WAL:      wal,
-			ReqStore: reqStore,
-			Link: &Link{
-				EventQueue: eventQueue,
-				Source:     nodeID,
-				Delay:      int64(recorderNodeConfig.RuntimeParms.LinkLatency),
-			},
+			ID:            nodeID,
+			Hasher:        r.Hasher,
+			State:         nodeState,
+			WAL:           wal,
+			ReqStore:      reqStore,
+			Link:          link,
+			StateTransfer: stateTransfer,
 			Interceptor: interceptorFunc(func(e *state.Event) error {
 				return eventlog.WriteRecordedEvent(output, &recording.Event{
 					NodeId:     nodeID,
@@ -507,6 +595,28 @@ func (r *Recording) Step() error {
 			}
 		}
 
+		clientStates := []*msgs.NetworkState_Client{}
+		if node.State.CheckpointState != nil {
+			clientStates = node.State.CheckpointState.Clients
+		}
+
+		if node.Config.Joining && node.WAL.List.Len() == 0 {
+			cEntry := r.joinCheckpoint(nodeID)
+			if cEntry == nil {
+				// No node has yet added this one to the network,
+				// so check again later.
+				r.EventQueue.InsertInitialize(nodeID, event.Initialize.InitParms, int64(runtimeParms.TickInterval))
+				break
+			}
+
+			_, err := processor.InitializeWALForJoiningNode(node.WAL, event.Initialize.InitParms, cEntry.SeqNo, cEntry.NetworkState, cEntry.CheckpointValue)
+			if err != nil {
+				return errors.WithMessage(err, "could not initialize WAL of joining node")
+			}
+
+			clientStates = cEntry.NetworkState.Clients
+		}
+
 		err := node.Initialize(
 			event.Initialize.InitParms,
 			NamedLogger{
@@ -521,7 +631,7 @@ func (r *Recording) Step() error {
 
 		r.EventQueue.InsertTickEvent(nodeID, int64(runtimeParms.TickInterval))
 
-		for _, clientState := range node.State.CheckpointState.Clients {
+		for _, clientState := range clientStates {
 			client := r.Clients[int(clientState.Id)]
 			if client.Config.shouldSkip(nodeID) {
 				continue
@@ -537,7 +647,14 @@ func (r *Recording) Step() error {
 			// to prevent the receive from going into the eventqueue
 			break
 		}
-		node.WorkItems.ResultEvents().Step(event.MsgReceived.Source, event.MsgReceived.Msg)
+		events, err := node.Replicas.Replica(event.MsgReceived.Source).Step(event.MsgReceived.Msg)
+		if err != nil {
+			return errors.WithMessagef(err, "could not step message from node %d", event.MsgReceived.Source)
+		}
+		node.WorkItems.AddStepResults(events)
+		if node.StateTransfer != nil {
+			node.WorkItems.AppActions().PushBackList(node.StateTransfer.Fetched())
+		}
 	case event.ClientProposal != nil:
 		prop := event.ClientProposal
 		client := node.Clients.Client(prop.ClientID)
@@ -565,6 +682,13 @@ func (r *Recording) Step() error {
 		}
 
 		events, err := client.Propose(prop.ReqNo, prop.Data)
+		if errors.Is(err, processor.ErrReqNoOutOfWindow) {
+			// The request is beyond the client window, so wait
+			// for earlier requests to commit before retrying.
+			r.EventQueue.InsertClientProposal(nodeID, prop.ClientID, prop.ReqNo, prop.Data, int64(runtimeParms.ProcessClientLatency*100))
+			break
+		}
+
 		if err != nil {
 			return errors.WithMessage(err, "unanticipated client propose error")
 		}
@@ -576,6 +700,9 @@ func (r *Recording) Step() error {
 		}
 	case event.Tick != nil:
 		node.WorkItems.ResultEvents().TickElapsed()
+		if node.StateTransfer != nil {
+			node.WorkItems.ResultEvents().PushBackList(node.StateTransfer.Tick())
+		}
 		r.EventQueue.InsertTickEvent(nodeID, int64(runtimeParms.TickInterval))
 	case event.ProcessReqStoreEvents != nil:
 		node.WorkItems.AddReqStoreResults(event.ProcessReqStoreEvents)
@@ -595,7 +722,7 @@ func (r *Recording) Step() error {
 		node.WorkItems.AddWALResults(netActions)
 		node.ProcessWALActionsPending = false
 	case event.ProcessNetActions != nil:
-		netResults, err := processor.ProcessNetActions(nodeID, node.Link, event.ProcessNetActions)
+		netResults, err := processor.ProcessNetActions(nodeID, node.Link, node.ReqStore, nil, event.ProcessNetActions)
 		if err != nil {
 			return errors.WithMessage(err, "could not process net actions")
 		}
@@ -616,7 +743,7 @@ func (r *Recording) Step() error {
 		node.WorkItems.AddClientResults(clientResults)
 		node.ProcessClientActionsPending = false
 	case event.ProcessAppActions != nil:
-		appResults, err := processor.ProcessAppActions(node.State, event.ProcessAppActions)
+		appResults, err := processor.ProcessAppActions(node.State, node.StateTransfer, event.ProcessAppActions)
 		if err != nil {
 			return errors.WithMessage(err, "could not process app actions")
 		}
@@ -676,6 +803,39 @@ func (r *Recording) Step() error {
 	return nil
 }
 
+// joinCheckpoint returns the most recent checkpoint persisted by any
+// node whose network configuration includes the given node, or nil
+// if there is none.
+func (r *Recording) joinCheckpoint(nodeID uint64) *msgs.CEntry {
+	var result *msgs.CEntry
+	for _, node := range r.Nodes {
+		if node.ID == nodeID || node.StateMachine == nil {
+			continue
+		}
+
+		node.WAL.LoadAll(func(index uint64, p *msgs.Persistent) {
+			cEntryType, ok := p.Type.(*msgs.Persistent_CEntry)
+			if !ok {
+				return
+			}
+
+			cEntry := cEntryType.CEntry
+			if result != nil && result.SeqNo >= cEntry.SeqNo {
+				return
+			}
+
+			for _, id := range cEntry.NetworkState.Config.Nodes {
+				if id == nodeID {
+					result = cEntry
+					return
+				}
+			}
+		})
+	}
+
+	return result
+}
+
 // DrainClients will execute the recording until all client requests have committed.
 // It will return with an error if the number of accumulated log entries exceeds timeout.
 // If any step returns an error, this function returns that error.
@@ -696,6 +856,12 @@ func (r *Recording) DrainClients(timeout int) (count int, err error) {
 		allDone := true
 	outer:
 		for _, node := range r.Nodes {
+			if !node.isMember() {
+				// Nodes which have been removed, or are yet
+				// to join, are not expected to commit.
+				continue
+			}
+
 			for _, client := range node.State.CheckpointState.Clients {
 				if targetReqs[client.Id] != client.LowWatermark {
 					allDone = false
@@ -711,6 +877,10 @@ func (r *Recording) DrainClients(timeout int) (count int, err error) {
 		if count > timeout {
 			var errText string
 			for _, node := range r.Nodes {
+				if !node.isMember() {
+					continue
+				}
+
 				for _, client := range node.State.CheckpointState.Clients {
 					if targetReqs[client.Id] != client.LowWatermark {
 						errText = fmt.Sprintf("(at least) node%d failed with client %d committing only through %d when expected %d", node.Config.InitParms.Id, client.Id, client.LowWatermark, targetReqs[client.Id])
@@ -723,12 +893,14 @@ func (r *Recording) DrainClients(timeout int) (count int, err error) {
 }
 
 type Spec struct {
-	NodeCount     int
-	ClientCount   int
-	ReqsPerClient uint64
-	BatchSize     uint32
-	ClientsIgnore []uint64
-	TweakRecorder func(r *Recorder)
+	NodeCount            int
+	ClientCount          int
+	ReqsPerClient        uint64
+	BatchSize            uint32
+	BatchSizeBytes       uint64
+	MaxBatchLatencyTicks uint32
+	ClientsIgnore        []uint64
+	TweakRecorder        func(r *Recorder)
 }
 
 func (s *Spec) Recorder() *Recorder {
@@ -747,6 +919,8 @@ func (s *Spec) Recorder() *Recorder {
 				NewEpochTimeoutTicks: 8,
 				BufferSize:           5 * 1024 * 1024,
 				BatchSize:            batchSize,
+				BatchSizeBytes:       s.BatchSizeBytes,
+				MaxBatchLatencyTicks: s.MaxBatchLatencyTicks,
 			},
 			RuntimeParms: &RuntimeParameters{
 				TickInterval:           500,
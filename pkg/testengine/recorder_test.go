@@ -83,7 +83,7 @@ var _ = Describe("Recorder", func() {
 		It("Executes and produces a log", func() {
 			count, err := recording.DrainClients(50000)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(count).To(Equal(43950))
+			Expect(count).To(Equal(44450))
 
 			fmt.Printf("Executing test required a log of %d events\n", count)
 
@@ -95,7 +95,7 @@ var _ = Describe("Recorder", func() {
 				//Expect(status.EpochTracker.EpochTargets[0].Suspicions).To(BeEmpty())
 
 				// Expect(fmt.Sprintf("%x", node.State.ActiveHash.Sum(nil))).To(BeEmpty())
-				Expect(fmt.Sprintf("%x", node.State.ActiveHash.Sum(nil))).To(Equal("cb81c7299ad4019baca241f267d570f1b451b751717ce18bb8efc16ae8a555c4"))
+				Expect(fmt.Sprintf("%x", node.State.ActiveHash.Sum(nil))).To(Equal("c18145c5cf9d82ce4da400cdc8f9a177a1ed9a4fe32c7fbaeb1e63f7a9a9d239"))
 			}
 		})
 	})
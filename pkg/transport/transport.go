@@ -0,0 +1,541 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package transport implements processor.Link over TCP connections secured with
+// mutual TLS.  Each node is identified by the certificate it presents, which must
+// match the certificate configured for that node, so that the source of every
+// inbound message is authenticated before it is passed to Node.Step.
+//
+// Messages to each peer are sent over a dedicated outbound connection, which is
+// re-established with backoff whenever it fails.  Each peer has a bounded queue
+// of outbound messages, and when the queue is full, further messages are dropped,
+// as the state machine tolerates and recovers from lost messages.
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+)
+
+const (
+	defaultQueueSize            = 1000
+	defaultMaxMessageSize       = 64 * 1024 * 1024
+	defaultReconnectInterval    = 100 * time.Millisecond
+	defaultMaxReconnectInterval = 5 * time.Second
+	dialTimeout                 = 5 * time.Second
+)
+
+// Stepper receives the messages arriving from peers, it is implemented
+// by *mirbft.Node.
+type Stepper interface {
+	Step(ctx context.Context, source uint64, msg *msgs.Msg) error
+}
+
+// Peer identifies a remote node.
+type Peer struct {
+	ID uint64
+
+	// Address is the host:port on which the peer listens.
+	Address string
+
+	// Certificate is the DER encoded certificate the peer must present,
+	// both when it connects to us, and when we connect to it.
+	Certificate []byte
+}
+
+type Config struct {
+	// ID is the ID of this node.
+	ID uint64
+
+	// ListenAddress is the host:port on which to accept connections from peers.
+	ListenAddress string
+
+	// Certificate is presented to peers to prove our identity.
+	Certificate tls.Certificate
+
+	// Peers are the initial set of remote nodes, more may be
+	// added or removed after the transport is created.
+	Peers []*Peer
+
+	// QueueSize is the number of outbound messages buffered per peer,
+	// it defaults to 1000.
+	QueueSize int
+
+	// MaxMessageSize is the largest message in bytes which will be
+	// accepted from a peer, it defaults to 64MB.
+	MaxMessageSize int
+
+	// ReconnectInterval is the initial delay before reconnecting to a peer,
+	// it doubles on each failure up to MaxReconnectInterval.  These default
+	// to 100ms and 5s respectively.
+	ReconnectInterval    time.Duration
+	MaxReconnectInterval time.Duration
+
+	// Logger, if set, receives connection errors and other
+	// noteworthy events.
+	Logger mirbft.Logger
+}
+
+type Transport struct {
+	config   *Config
+	listener net.Listener
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	mutex   sync.Mutex
+	stepper Stepper
+	peers   map[uint64]*peer
+	conns   map[net.Conn]uint64
+}
+
+type peer struct {
+	dropped uint64 // accessed atomically, must be first for alignment
+
+	Peer
+	queueC chan *msgs.Msg
+	stopC  chan struct{}
+}
+
+// Listen creates a transport, listening for connections on the configured
+// address.  Messages may be sent immediately, but will not be delivered
+// until Start is called.
+func Listen(config *Config) (*Transport, error) {
+	t := &Transport{
+		config: config,
+		peers:  map[uint64]*peer{},
+		conns:  map[net.Conn]uint64{},
+	}
+
+	listener, err := tls.Listen("tcp", config.ListenAddress, &tls.Config{
+		Certificates:          []tls.Certificate{config.Certificate},
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: t.verifyInbound,
+		MinVersion:            tls.VersionTLS12,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not listen")
+	}
+
+	t.listener = listener
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+
+	for _, p := range config.Peers {
+		if err := t.AddPeer(p); err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Addr returns the address on which the transport is listening.
+func (t *Transport) Addr() net.Addr {
+	return t.listener.Addr()
+}
+
+// Start begins accepting connections from peers, passing their messages to
+// the given stepper, and begins sending queued messages to peers.
+func (t *Transport) Start(stepper Stepper) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.stepper = stepper
+
+	t.wg.Add(1)
+	go t.acceptLoop()
+
+	for _, p := range t.peers {
+		t.startPeer(p)
+	}
+}
+
+// Stop closes all connections and waits for all go routines to exit.
+func (t *Transport) Stop() {
+	t.cancel()
+	t.listener.Close()
+
+	t.mutex.Lock()
+	for conn := range t.conns {
+		conn.Close()
+	}
+	t.mutex.Unlock()
+
+	t.wg.Wait()
+}
+
+// AddPeer adds a remote node, to which messages may then be sent,
+// and from which connections are accepted.
+func (t *Transport) AddPeer(p *Peer) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if p.ID == t.config.ID {
+		return errors.Errorf("cannot add self (%d) as a peer", p.ID)
+	}
+
+	if _, ok := t.peers[p.ID]; ok {
+		return errors.Errorf("peer %d already exists", p.ID)
+	}
+
+	queueSize := t.config.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	newPeer := &peer{
+		Peer:   *p,
+		queueC: make(chan *msgs.Msg, queueSize),
+		stopC:  make(chan struct{}),
+	}
+	t.peers[p.ID] = newPeer
+
+	if t.stepper != nil {
+		t.startPeer(newPeer)
+	}
+
+	return nil
+}
+
+// RemovePeer stops sending messages to a remote node, and closes
+// any connections it has established.
+func (t *Transport) RemovePeer(id uint64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	p, ok := t.peers[id]
+	if !ok {
+		return
+	}
+
+	delete(t.peers, id)
+	close(p.stopC)
+
+	for conn, source := range t.conns {
+		if source == id {
+			conn.Close()
+		}
+	}
+}
+
+// Send enqueues a message for delivery to the given node.  It never blocks,
+// if the node is unknown, or its queue is full, the message is dropped.
+func (t *Transport) Send(dest uint64, msg *msgs.Msg) {
+	t.mutex.Lock()
+	p, ok := t.peers[dest]
+	t.mutex.Unlock()
+
+	if !ok {
+		t.log(mirbft.LevelWarn, "dropping message to unknown peer", "dest", dest)
+		return
+	}
+
+	select {
+	case p.queueC <- msg:
+	default:
+		atomic.AddUint64(&p.dropped, 1)
+	}
+}
+
+// Dropped returns the number of messages dropped because
+// the outbound queue to each peer was full.
+func (t *Transport) Dropped() map[uint64]uint64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	result := make(map[uint64]uint64, len(t.peers))
+	for id, p := range t.peers {
+		result[id] = atomic.LoadUint64(&p.dropped)
+	}
+	return result
+}
+
+func (t *Transport) log(level mirbft.LogLevel, text string, args ...interface{}) {
+	if t.config.Logger == nil {
+		return
+	}
+	t.config.Logger.Log(level, text, args...)
+}
+
+// peerByCertificate must be called with the mutex held.
+func (t *Transport) peerByCertificate(cert []byte) (*peer, bool) {
+	for _, p := range t.peers {
+		if bytes.Equal(p.Certificate, cert) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func (t *Transport) verifyInbound(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.Errorf("peer presented no certificate")
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.peerByCertificate(rawCerts[0]); !ok {
+		return errors.Errorf("peer presented an unknown certificate")
+	}
+
+	return nil
+}
+
+// trackConn records an open connection so that it may be closed on Stop, or when
+// the peer is removed.  It returns false if the transport is already stopping.
+func (t *Transport) trackConn(conn net.Conn, id uint64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.ctx.Err() != nil {
+		return false
+	}
+
+	t.conns[conn] = id
+	return true
+}
+
+func (t *Transport) untrackConn(conn net.Conn) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.conns, conn)
+}
+
+func (t *Transport) acceptLoop() {
+	defer t.wg.Done()
+
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			if t.ctx.Err() != nil {
+				return
+			}
+			t.log(mirbft.LevelWarn, "could not accept connection", "error", err)
+			continue
+		}
+
+		t.wg.Add(1)
+		go t.receive(conn.(*tls.Conn))
+	}
+}
+
+func (t *Transport) receive(conn *tls.Conn) {
+	defer t.wg.Done()
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+	if err := conn.Handshake(); err != nil {
+		t.log(mirbft.LevelWarn, "inbound handshake failed", "remote", conn.RemoteAddr(), "error", err)
+		return
+	}
+	conn.SetDeadline(time.Time{})
+
+	// The certificate was verified during the handshake, but the peer may
+	// have been removed since, so look it up again.
+	t.mutex.Lock()
+	p, ok := t.peerByCertificate(conn.ConnectionState().PeerCertificates[0].Raw)
+	stepper := t.stepper
+	t.mutex.Unlock()
+	if !ok {
+		return
+	}
+
+	if !t.trackConn(conn, p.ID) {
+		return
+	}
+	defer t.untrackConn(conn)
+
+	maxMessageSize := t.config.MaxMessageSize
+	if maxMessageSize <= 0 {
+		maxMessageSize = defaultMaxMessageSize
+	}
+
+	reader := bufio.NewReader(conn)
+	for {
+		msg, err := readMsg(reader, maxMessageSize)
+		if err != nil {
+			if t.ctx.Err() == nil && err != io.EOF {
+				t.log(mirbft.LevelWarn, "closing inbound connection", "source", p.ID, "error", err)
+			}
+			return
+		}
+
+		if err := stepper.Step(t.ctx, p.ID, msg); err != nil {
+			if t.ctx.Err() != nil {
+				return
+			}
+			t.log(mirbft.LevelWarn, "could not step message", "source", p.ID, "error", err)
+		}
+	}
+}
+
+// startPeer must be called with the mutex held.
+func (t *Transport) startPeer(p *peer) {
+	t.wg.Add(1)
+	go t.sendLoop(p)
+}
+
+func (t *Transport) sendLoop(p *peer) {
+	defer t.wg.Done()
+
+	interval := t.config.ReconnectInterval
+	if interval <= 0 {
+		interval = defaultReconnectInterval
+	}
+
+	maxInterval := t.config.MaxReconnectInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxReconnectInterval
+	}
+
+	backoff := interval
+	for {
+		conn, err := t.dial(p)
+		if err == nil {
+			backoff = interval
+			err = t.send(p, conn)
+			conn.Close()
+			t.untrackConn(conn)
+		}
+
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-p.stopC:
+			return
+		default:
+		}
+
+		t.log(mirbft.LevelWarn, "connection to peer failed, will reconnect", "dest", p.ID, "error", err, "backoff", backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-t.ctx.Done():
+			return
+		case <-p.stopC:
+			return
+		}
+
+		backoff *= 2
+		if backoff > maxInterval {
+			backoff = maxInterval
+		}
+	}
+}
+
+func (t *Transport) dial(p *peer) (*tls.Conn, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", p.Address, &tls.Config{
+		Certificates: []tls.Certificate{t.config.Certificate},
+		// The usual verification against CAs and host names is replaced
+		// by checking that the peer presents exactly its configured certificate.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], p.Certificate) {
+				return errors.Errorf("peer %d did not present its configured certificate", p.ID)
+			}
+			return nil
+		},
+		MinVersion: tls.VersionTLS12,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !t.trackConn(conn, p.ID) {
+		conn.Close()
+		return nil, t.ctx.Err()
+	}
+
+	return conn, nil
+}
+
+// send writes queued messages to the connection until a write fails, or
+// the transport or peer is stopped.
+func (t *Transport) send(p *peer, conn net.Conn) error {
+	writer := bufio.NewWriter(conn)
+	for {
+		var msg *msgs.Msg
+		select {
+		case msg = <-p.queueC:
+		case <-t.ctx.Done():
+			return t.ctx.Err()
+		case <-p.stopC:
+			return nil
+		}
+
+		if err := writeMsg(writer, msg); err != nil {
+			return err
+		}
+
+		// Only flush once the queue is drained, so that bursts
+		// of messages are coalesced into fewer writes.
+		if len(p.queueC) == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Messages are framed on the wire as a 4 byte big endian length,
+// followed by the serialized message.
+
+func writeMsg(w io.Writer, msg *msgs.Msg) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal message")
+	}
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+func readMsg(r io.Reader, maxMessageSize int) (*msgs.Msg, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[:])
+	if uint64(size) > uint64(maxMessageSize) {
+		return nil, errors.Errorf("message size %d exceeds maximum of %d", size, maxMessageSize)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	msg := &msgs.Msg{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, errors.WithMessage(err, "could not unmarshal message")
+	}
+
+	return msg, nil
+}
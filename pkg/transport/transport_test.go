@@ -0,0 +1,221 @@
+package transport_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/transport"
+)
+
+func newCertificate(id uint64) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(id) + 1),
+		Subject: pkix.Name{
+			CommonName: fmt.Sprintf("node%d", id),
+		},
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+type received struct {
+	source uint64
+	msg    *msgs.Msg
+}
+
+type fakeStepper chan received
+
+func (fs fakeStepper) Step(ctx context.Context, source uint64, msg *msgs.Msg) error {
+	select {
+	case fs <- received{source: source, msg: msg}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func checkpointMsg(seqNo uint64) *msgs.Msg {
+	return &msgs.Msg{
+		Type: &msgs.Msg_Checkpoint{
+			Checkpoint: &msgs.Checkpoint{
+				SeqNo: seqNo,
+				Value: []byte("value"),
+			},
+		},
+	}
+}
+
+func seqNoOf(r received) uint64 {
+	return r.msg.Type.(*msgs.Msg_Checkpoint).Checkpoint.SeqNo
+}
+
+var _ = Describe("Transport", func() {
+	var (
+		certs      []tls.Certificate
+		transports []*transport.Transport
+		steppers   []fakeStepper
+	)
+
+	peerFor := func(id int) *transport.Peer {
+		return &transport.Peer{
+			ID:          uint64(id),
+			Address:     transports[id].Addr().String(),
+			Certificate: certs[id].Certificate[0],
+		}
+	}
+
+	listen := func(id int, address string) *transport.Transport {
+		t, err := transport.Listen(&transport.Config{
+			ID:                   uint64(id),
+			ListenAddress:        address,
+			Certificate:          certs[id],
+			ReconnectInterval:    10 * time.Millisecond,
+			MaxReconnectInterval: 50 * time.Millisecond,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return t
+	}
+
+	BeforeEach(func() {
+		certs = make([]tls.Certificate, 4)
+		for i := range certs {
+			certs[i] = newCertificate(uint64(i))
+		}
+
+		transports = make([]*transport.Transport, 3)
+		steppers = make([]fakeStepper, 3)
+		for i := range transports {
+			transports[i] = listen(i, "127.0.0.1:0")
+			steppers[i] = make(fakeStepper, 100)
+		}
+
+		for i, t := range transports {
+			for j := range transports {
+				if i == j {
+					continue
+				}
+				Expect(t.AddPeer(peerFor(j))).To(Succeed())
+			}
+			t.Start(steppers[i])
+		}
+	})
+
+	AfterEach(func() {
+		for _, t := range transports {
+			t.Stop()
+		}
+	})
+
+	It("delivers messages in order with the authenticated source", func() {
+		for seqNo := uint64(1); seqNo <= 10; seqNo++ {
+			transports[0].Send(1, checkpointMsg(seqNo))
+			transports[2].Send(1, checkpointMsg(100+seqNo))
+		}
+
+		var from0, from2 []uint64
+		for i := 0; i < 20; i++ {
+			var r received
+			Eventually(steppers[1]).Should(Receive(&r))
+			switch r.source {
+			case 0:
+				from0 = append(from0, seqNoOf(r))
+			case 2:
+				from2 = append(from2, seqNoOf(r))
+			default:
+				Fail(fmt.Sprintf("unexpected source %d", r.source))
+			}
+		}
+
+		Expect(from0).To(Equal([]uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}))
+		Expect(from2).To(Equal([]uint64{101, 102, 103, 104, 105, 106, 107, 108, 109, 110}))
+	})
+
+	It("rejects connections from nodes with unknown certificates", func() {
+		stranger := listen(3, "127.0.0.1:0")
+		defer stranger.Stop()
+
+		// Node 3 is not among the peers of node 0, so its certificate is unknown.
+		Expect(stranger.AddPeer(peerFor(0))).To(Succeed())
+		stranger.Start(make(fakeStepper, 100))
+		stranger.Send(0, checkpointMsg(1))
+
+		Consistently(steppers[0], 200*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("reconnects after a peer restarts", func() {
+		address := transports[1].Addr().String()
+		transports[1].Stop()
+
+		transports[1] = listen(1, address)
+		steppers[1] = make(fakeStepper, 100)
+		Expect(transports[1].AddPeer(peerFor(0))).To(Succeed())
+		transports[1].Start(steppers[1])
+
+		// Messages sent over the broken connection may be lost,
+		// so keep sending until one arrives.
+		Eventually(func() bool {
+			transports[0].Send(1, checkpointMsg(1))
+			select {
+			case r := <-steppers[1]:
+				return r.source == 0
+			case <-time.After(10 * time.Millisecond):
+				return false
+			}
+		}, 5*time.Second).Should(BeTrue())
+	})
+
+	It("drops messages once a peer's queue is full", func() {
+		t, err := transport.Listen(&transport.Config{
+			ID:            3,
+			ListenAddress: "127.0.0.1:0",
+			Certificate:   certs[3],
+			QueueSize:     2,
+			Peers:         []*transport.Peer{peerFor(0)},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		defer t.Stop()
+
+		// The transport is not started, so nothing drains the queue.
+		for i := uint64(0); i < 5; i++ {
+			t.Send(0, checkpointMsg(i))
+		}
+
+		Expect(t.Dropped()).To(Equal(map[uint64]uint64{0: 3}))
+	})
+
+	It("stops sending to removed peers", func() {
+		transports[0].RemovePeer(1)
+		transports[0].Send(1, checkpointMsg(1))
+		Consistently(steppers[1], 200*time.Millisecond).ShouldNot(Receive())
+
+		Expect(transports[0].AddPeer(peerFor(1))).To(Succeed())
+		transports[0].Send(1, checkpointMsg(2))
+
+		var r received
+		Eventually(steppers[1]).Should(Receive(&r))
+		Expect(seqNoOf(r)).To(Equal(uint64(2)))
+	})
+})
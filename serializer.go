@@ -15,6 +15,13 @@ type logAdapter struct {
 	Logger
 }
 
+func (la logAdapter) Enabled(level statemachine.LogLevel, component string) bool {
+	if checker, ok := la.Logger.(LevelChecker); ok {
+		return checker.Enabled(LogLevel(level), component)
+	}
+	return true
+}
+
 func (la logAdapter) Log(level statemachine.LogLevel, msg string, args ...interface{}) {
 	la.Logger.Log(LogLevel(level), msg, args...)
 }